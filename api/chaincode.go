@@ -18,14 +18,14 @@ type Chaincode interface {
 	Invoke(fn string) ChaincodeInvokeBuilder
 	// Query returns query builder for presented function and arguments
 	Query(fn string, args ...string) ChaincodeQueryBuilder
-	// Install fetches chaincode from repository and installs it on local peer
-	Install(version string)
+	// Install fetches presented version of chaincode deployed on channel and installs it on all peers of current MSP
+	Install(ctx context.Context, version string) error
 	// Subscribe returns subscription on chaincode events
 	Subscribe(ctx context.Context) (EventCCSubscription, error)
 }
 
 type ChaincodePackage interface {
-	// Allows to get latest version of chaincode
+	// Latest returns chaincode deployed on channels joined by peer of current MSP
 	Latest(ctx context.Context) (*peer.ChaincodeDeploymentSpec, error)
 	// Installs chaincode using defined chaincode fetcher
 	Install(ctx context.Context, path, version string) error
@@ -44,6 +44,11 @@ type TxWaiter interface {
 	Wait(ctx context.Context, channel string, txid ChaincodeTx) error
 }
 
+// EndorsementVerifier is interface for verifying endorsements of peers before transaction is sent to orderer
+type EndorsementVerifier interface {
+	Verify(ctx context.Context, channel string, responses []*peer.ProposalResponse) error
+}
+
 type DoOptions struct {
 	Identity msp.SigningIdentity
 	Pool     PeerPool
@@ -51,6 +56,8 @@ type DoOptions struct {
 	TxWaiter TxWaiter
 	// necessary only for 'tx waiter all'
 	EndorsingMspIDs []string
+	// EndorsementVerifier is optional, endorsements are verified before sending transaction to orderer
+	EndorsementVerifier EndorsementVerifier
 }
 
 type DoOption func(opt *DoOptions) error
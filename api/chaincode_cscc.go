@@ -11,6 +11,8 @@ import (
 type CSCC interface {
 	// JoinChain allows to join channel using presented genesis block
 	JoinChain(ctx context.Context, channelName string, genesisBlock *common.Block) error
+	// JoinChainBySnapshot allows to join channel using ledger snapshot on peer file system, available since fabric v2.3
+	JoinChainBySnapshot(ctx context.Context, snapshotPath string) error
 	// GetConfigBlock returns genesis block of channel
 	GetConfigBlock(ctx context.Context, channelName string) (*common.Block, error)
 	// GetChannelConfig returns channel configuration
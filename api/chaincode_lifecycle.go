@@ -6,6 +6,41 @@ import (
 	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
 )
 
+type LifecycleCommitOptions struct {
+	// EndorsingMspIDs is list of MSPs which peers will endorse commit proposal,
+	// by default MSPs which approved chaincode definition and have peers in pool are used.
+	// Commit fails if endorsements of these MSPs don't satisfy lifecycle endorsement policy of channel
+	EndorsingMspIDs []string
+}
+
+type LifecycleCommitOption func(opts *LifecycleCommitOptions) error
+
+// WithCommitEndorsingMSPs allows to set MSPs which will endorse chaincode definition commit
+func WithCommitEndorsingMSPs(mspIDs ...string) LifecycleCommitOption {
+	return func(opts *LifecycleCommitOptions) error {
+		opts.EndorsingMspIDs = mspIDs
+		return nil
+	}
+}
+
+// Lifecycle describes Fabric v2 lifecycle system chaincode (_lifecycle)
 type Lifecycle interface {
+	// QueryInstalledChaincodes returns list of chaincode packages installed on peer
 	QueryInstalledChaincodes(ctx context.Context) (*lb.QueryInstalledChaincodesResult, error)
+	// InstallChaincode installs chaincode package on peer
+	InstallChaincode(ctx context.Context, args *lb.InstallChaincodeArgs) (*lb.InstallChaincodeResult, error)
+	// GetInstalledChaincodePackage returns chaincode package installed on peer by package id
+	GetInstalledChaincodePackage(ctx context.Context, args *lb.GetInstalledChaincodePackageArgs) (*lb.GetInstalledChaincodePackageResult, error)
+	// ApproveChaincodeDefinitionForMyOrg endorses approval of chaincode definition by current MSP and sends it to orderer
+	ApproveChaincodeDefinitionForMyOrg(ctx context.Context, channelName string, args *lb.ApproveChaincodeDefinitionForMyOrgArgs) error
+	// QueryApprovedChaincodeDefinition returns chaincode definition approved by current MSP
+	QueryApprovedChaincodeDefinition(ctx context.Context, channelName string, args *lb.QueryApprovedChaincodeDefinitionArgs) (*lb.QueryApprovedChaincodeDefinitionResult, error)
+	// CheckCommitReadiness returns approvals of channel members for chaincode definition
+	CheckCommitReadiness(ctx context.Context, channelName string, args *lb.CheckCommitReadinessArgs) (*lb.CheckCommitReadinessResult, error)
+	// CommitChaincodeDefinition collects endorsements of chaincode definition commit and sends it to orderer
+	CommitChaincodeDefinition(ctx context.Context, channelName string, args *lb.CommitChaincodeDefinitionArgs, opts ...LifecycleCommitOption) error
+	// QueryChaincodeDefinition returns committed chaincode definition
+	QueryChaincodeDefinition(ctx context.Context, channelName string, args *lb.QueryChaincodeDefinitionArgs) (*lb.QueryChaincodeDefinitionResult, error)
+	// QueryChaincodeDefinitions returns list of committed chaincode definitions on channel
+	QueryChaincodeDefinitions(ctx context.Context, channelName string, args *lb.QueryChaincodeDefinitionsArgs) (*lb.QueryChaincodeDefinitionsResult, error)
 }
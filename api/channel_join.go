@@ -0,0 +1,66 @@
+package api
+
+import (
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/pkg/errors"
+)
+
+// JoinOpts are options of joining channel, genesis block is fetched from orderer by default
+type JoinOpts struct {
+	// GenesisBlock is used instead of block fetched from orderer
+	GenesisBlock *common.Block
+	// SnapshotPath is path to ledger snapshot on peer file system, available since fabric v2.3
+	SnapshotPath string
+}
+
+type JoinOpt func(opts *JoinOpts) error
+
+// PeerJoinResult is result of joining channel on peer
+type PeerJoinResult struct {
+	Peer string
+	// AlreadyJoined is true if peer has already joined channel, so join was skipped
+	AlreadyJoined bool
+	Err           error
+}
+
+// WithGenesisBlock allows to join channel using presented genesis block
+func WithGenesisBlock(block *common.Block) JoinOpt {
+	return func(opts *JoinOpts) error {
+		opts.GenesisBlock = block
+		return nil
+	}
+}
+
+// WithGenesisBlockBytes allows to join channel using marshalled genesis block
+func WithGenesisBlockBytes(raw []byte) JoinOpt {
+	return func(opts *JoinOpts) error {
+		block := new(common.Block)
+		if err := proto.Unmarshal(raw, block); err != nil {
+			return errors.Wrap(err, `failed to unmarshal genesis block`)
+		}
+		opts.GenesisBlock = block
+		return nil
+	}
+}
+
+// WithGenesisBlockFile allows to join channel using genesis block file, e.g. created by configtxgen
+func WithGenesisBlockFile(path string) JoinOpt {
+	return func(opts *JoinOpts) error {
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			return errors.Wrap(err, `failed to read genesis block file`)
+		}
+		return WithGenesisBlockBytes(raw)(opts)
+	}
+}
+
+// WithSnapshot allows to join channel by ledger snapshot located on peer file system
+func WithSnapshot(snapshotPath string) JoinOpt {
+	return func(opts *JoinOpts) error {
+		opts.SnapshotPath = snapshotPath
+		return nil
+	}
+}
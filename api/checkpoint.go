@@ -0,0 +1,101 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// EventCheckpoint is position of last delivered chaincode event
+type EventCheckpoint struct {
+	BlockNumber uint64 `json:"block_number"`
+	// TxIndex is index of transaction in block, events of transactions with greater index are not delivered yet
+	TxIndex int `json:"tx_index"`
+}
+
+// CheckpointStore persists positions of checkpointed subscriptions by key
+type CheckpointStore interface {
+	// Load returns saved checkpoint, nil if checkpoint was not saved yet
+	Load(key string) (*EventCheckpoint, error)
+	// Save saves checkpoint by key
+	Save(key string, checkpoint EventCheckpoint) error
+}
+
+// CheckpointSubscription is chaincode events subscription which saves position of events to CheckpointStore.
+// By default position is saved right after event is read from Events, so delivery is at-most-once:
+// event is lost if consumer fails before it is processed. With WithCheckpointAck position is saved by Ack,
+// so delivery is at-least-once: events which are not acknowledged are delivered again after restart
+type CheckpointSubscription interface {
+	EventCCSubscription
+	// Ack saves position of processed event, next event is delivered after ack.
+	// It is used only by subscription created WithCheckpointAck
+	Ack(event *peer.ChaincodeEvent) error
+}
+
+// CheckpointOpts are options of checkpointed subscription
+type CheckpointOpts struct {
+	// Key is key of checkpoint in store, `channel/chaincode` by default
+	Key string
+	// Seek is used if checkpoint is not saved yet, SeekOldest by default.
+	// Stop position of seek is kept after reconnects
+	Seek EventCCSeekOption
+	// RetryDelay is delay before reconnect
+	RetryDelay time.Duration
+	// MaxRetries limits count of consecutive reconnects without delivered blocks, 0 means unlimited
+	MaxRetries int
+	// Ack enables explicit acknowledgement of events by CheckpointSubscription.Ack
+	Ack bool
+	// SaveInterval is count of processed blocks without events after which position is saved,
+	// 0 means position is saved only with events
+	SaveInterval int
+}
+
+type CheckpointOpt func(opts *CheckpointOpts)
+
+// WithCheckpointKey sets key of checkpoint in store
+func WithCheckpointKey(key string) CheckpointOpt {
+	return func(opts *CheckpointOpts) {
+		opts.Key = key
+	}
+}
+
+// WithCheckpointSeek sets seek option used when checkpoint is not saved yet
+func WithCheckpointSeek(seek EventCCSeekOption) CheckpointOpt {
+	return func(opts *CheckpointOpts) {
+		opts.Seek = seek
+	}
+}
+
+// WithCheckpointRetry sets delay before reconnect and max count of consecutive reconnects
+func WithCheckpointRetry(delay time.Duration, maxRetries int) CheckpointOpt {
+	return func(opts *CheckpointOpts) {
+		opts.RetryDelay = delay
+		opts.MaxRetries = maxRetries
+	}
+}
+
+// WithCheckpointAck makes subscription save position of event when consumer acknowledges it
+// instead of when event is read
+func WithCheckpointAck() CheckpointOpt {
+	return func(opts *CheckpointOpts) {
+		opts.Ack = true
+	}
+}
+
+// WithCheckpointSaveInterval sets count of processed blocks without events after which position is saved
+func WithCheckpointSaveInterval(blocks int) CheckpointOpt {
+	return func(opts *CheckpointOpts) {
+		opts.SaveInterval = blocks
+	}
+}
+
+// ErrBlockGap is returned when received block doesn't follow previous one
+type ErrBlockGap struct {
+	Expected uint64
+	Received uint64
+}
+
+func (e ErrBlockGap) Error() string {
+	return fmt.Sprintf("block gap: expected block %d, received %d", e.Expected, e.Received)
+}
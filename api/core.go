@@ -2,18 +2,43 @@ package api
 
 import (
 	"context"
+	"net"
+	"strconv"
 
+	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric/msp"
 )
 
 type Channel interface {
 	// Chaincode returns chaincode instance by chaincode name
 	Chaincode(ctx context.Context, name string) (Chaincode, error)
-	// Joins channel
-	Join(ctx context.Context) error
+	// Join joins channel on first ready peer of current MSP
+	Join(ctx context.Context, opts ...JoinOpt) error
+	// JoinPeers joins channel on every peer of current MSP and returns result of each peer,
+	// peers which have already joined channel are skipped
+	JoinPeers(ctx context.Context, opts ...JoinOpt) ([]PeerJoinResult, error)
+	// ConfigBlock returns latest config block of channel fetched from orderer
+	ConfigBlock(ctx context.Context) (*common.Block, error)
+	// SetAnchorPeers updates anchor peers of organization and waits until config block is committed on peer of current MSP
+	SetAnchorPeers(ctx context.Context, mspID string, anchorPeers []HostPort) error
+	// AddOrganization adds organization defined by MSP directory to channel.
+	// Config update is signed by current identity and presented signers, e.g. admins of other organizations
+	AddOrganization(ctx context.Context, mspID, mspDir string, signers ...msp.SigningIdentity) error
+	// RemoveOrganization removes organization from channel
+	RemoveOrganization(ctx context.Context, mspID string, signers ...msp.SigningIdentity) error
 	// CSCC implements Configuration System Chaincode (CSCC)
 }
 
+// HostPort is network address of peer or orderer
+type HostPort struct {
+	Host string
+	Port int
+}
+
+func (hp HostPort) String() string {
+	return net.JoinHostPort(hp.Host, strconv.Itoa(hp.Port))
+}
+
 type Core interface {
 	// Channel returns channel instance by channel name
 	Channel(name string) Channel
@@ -29,6 +54,8 @@ type Core interface {
 	Chaincode(name string) ChaincodePackage
 	// FabricV2 returns if core works in fabric v2 mode
 	FabricV2() bool
+	// Close closes orderers used by core which implement io.Closer, peer pool is not closed
+	Close() error
 }
 
 // SystemCC describes interface to access Fabric System Chaincodes
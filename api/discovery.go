@@ -15,6 +15,8 @@ type DiscoveryProvider interface {
 // ChaincodeDiscoverer - looking for info about network, channel, chaincode in local configs or gossip
 type ChaincodeDiscoverer interface {
 	Endorsers() []*HostEndpoint
+	// EndorsementLayouts returns combinations of endorsers satisfying chaincode endorsement policy
+	EndorsementLayouts() []EndorsementLayout
 	ChaincodeName() string
 	ChaincodeVersion() string
 
@@ -32,6 +34,9 @@ type LocalPeersDiscoverer interface {
 	Peers() []*HostEndpoint
 }
 
+// EndorsementLayout - MSP id to number of peers of this MSP which endorsements are required
+type EndorsementLayout map[string]int
+
 type HostEndpoint struct {
 	MspID string
 	// each host could have own tls settings
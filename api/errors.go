@@ -5,8 +5,10 @@ import (
 )
 
 const (
-	ErrEmptyConfig         = Error(`empty core configuration`)
-	ErrInvalidPEMStructure = Error(`invalid PEM structure`)
+	ErrEmptyConfig           = Error(`empty core configuration`)
+	ErrInvalidPEMStructure   = Error(`invalid PEM structure`)
+	ErrChaincodeNotFound     = Error(`chaincode is not deployed on channels joined by peer`)
+	ErrChaincodeNotInstalled = Error(`chaincode package is not installed on peer`)
 )
 
 type MultiError struct {
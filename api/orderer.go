@@ -13,3 +13,12 @@ type Orderer interface {
 	// Deliver fetches block from orderer by envelope
 	Deliver(ctx context.Context, envelope *common.Envelope) (*common.Block, error)
 }
+
+// OrdererDeliverClient allows to subscribe on blocks from ordering service
+type OrdererDeliverClient interface {
+	// SubscribeBlock allows to subscribe on blocks of channel. Subscription reconnects to next orderer
+	// on stream failure and resumes from block following the last received one
+	SubscribeBlock(ctx context.Context, channelName string, seekOpt ...EventCCSeekOption) (BlockSubscription, error)
+	// Close finishes subscriptions and closes connections dialed by client
+	Close() error
+}
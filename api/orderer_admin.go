@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+)
+
+// OrdererAdmin describes orderer channel participation API (osnadmin) available since fabric v2.3
+type OrdererAdmin interface {
+	// ListChannels returns channels orderer is member of
+	ListChannels(ctx context.Context) (*OrdererChannelList, error)
+	// ListChannel returns info about orderer membership in channel
+	ListChannel(ctx context.Context, channelName string) (*OrdererChannelInfo, error)
+	// JoinChannel joins orderer to channel using config block, genesis block is used for new channels
+	JoinChannel(ctx context.Context, configBlock *common.Block) (*OrdererChannelInfo, error)
+	// RemoveChannel removes orderer from channel
+	RemoveChannel(ctx context.Context, channelName string) error
+}
+
+// OrdererChannelList is list of orderer channels
+type OrdererChannelList struct {
+	// SystemChannel is nil if orderer works without system channel
+	SystemChannel *OrdererChannelInfoShort  `json:"systemChannel"`
+	Channels      []OrdererChannelInfoShort `json:"channels"`
+}
+
+// OrdererChannelInfoShort contains channel name and url of channel info
+type OrdererChannelInfoShort struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// OrdererChannelInfo is info about orderer membership in channel
+type OrdererChannelInfo struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Status is one of `onboarding`, `active`, `inactive` or `failed`
+	Status string `json:"status"`
+	// ConsensusRelation is one of `consenter`, `follower`, `config-tracker` or `other`
+	ConsensusRelation string `json:"consensusRelation"`
+	Height            uint64 `json:"height"`
+}
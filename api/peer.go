@@ -27,8 +27,24 @@ type Peer interface {
 type PeerProcessor interface {
 	// CreateProposal creates signed proposal for presented cc, function and args using signing identity
 	CreateProposal(chaincodeName string, identity msp.SigningIdentity, fn string, args [][]byte, transArgs TransArgs) (*peer.SignedProposal, ChaincodeTx, error)
-	// Send sends signed proposal to endorsing peers and collects their responses
-	Send(ctx context.Context, proposal *peer.SignedProposal, endorsingMspIDs []string, pool PeerPool) ([]*peer.ProposalResponse, error)
+	// Send sends signed proposal to endorsing peers and collects their responses.
+	// If endorsement layouts are presented, minimal satisfiable layout is used instead of endorsingMspIDs
+	Send(ctx context.Context, proposal *peer.SignedProposal, endorsingMspIDs []string, pool PeerPool, opts ...PeerSendOpt) ([]*peer.ProposalResponse, error)
+}
+
+type PeerSendOpts struct {
+	EndorsementLayouts []EndorsementLayout
+}
+
+type PeerSendOpt func(opts *PeerSendOpts) error
+
+// WithEndorsementLayouts allows to collect endorsements according to one of presented layouts,
+// next layout is tried if endorsement on previous fails
+func WithEndorsementLayouts(layouts ...EndorsementLayout) PeerSendOpt {
+	return func(opts *PeerSendOpts) error {
+		opts.EndorsementLayouts = layouts
+		return nil
+	}
 }
 
 // PeerEndorseError describes peer endorse error
@@ -42,6 +58,43 @@ func (e PeerEndorseError) Error() string {
 	return fmt.Sprintf("failed to endorse: %s (code: %d)", e.Message, e.Status)
 }
 
+// Endorser describes peer which signed endorsement
+type Endorser struct {
+	MspID string
+	// Name is common name of endorser certificate
+	Name string
+}
+
+func (e Endorser) String() string {
+	return fmt.Sprintf("%s (%s)", e.Name, e.MspID)
+}
+
+// DivergedEndorsement describes endorsement which proposal response payload differs from expected one
+type DivergedEndorsement struct {
+	Endorser Endorser
+	// Diff contains differences of rwsets and chaincode responses
+	Diff []string
+}
+
+// ErrEndorsementMismatch occurs when endorsements contain different proposal response payloads,
+// i.e. chaincode is not deterministic
+type ErrEndorsementMismatch struct {
+	// Expected is endorser of payload returned by most of peers
+	Expected Endorser
+	Diverged []DivergedEndorsement
+}
+
+func (e *ErrEndorsementMismatch) Error() string {
+	errStr := fmt.Sprintf("endorsements mismatch, expected payload of %s:\n", e.Expected)
+	for _, d := range e.Diverged {
+		errStr += fmt.Sprintf("%s diverges:\n", d.Endorser)
+		for _, line := range d.Diff {
+			errStr += fmt.Sprintf("\t%s\n", line)
+		}
+	}
+	return errStr
+}
+
 type PeerEndorseOpts struct {
 	Context context.Context
 }
@@ -8,6 +8,7 @@ import (
 	"google.golang.org/grpc/codes"
 
 	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric-protos-go/orderer"
 	"github.com/hyperledger/fabric-protos-go/peer"
 )
@@ -25,6 +26,21 @@ type DeliverClient interface {
 	SubscribeTx(ctx context.Context, channelName string, tx ChaincodeTx, seekOpt ...EventCCSeekOption) (TxSubscription, error)
 	// SubscribeBlock allows to subscribe on block events. Always returns new instance of block subscription
 	SubscribeBlock(ctx context.Context, channelName string, seekOpt ...EventCCSeekOption) (BlockSubscription, error)
+	// SubscribeCCCheckpointed allows to subscribe on chaincode events with position of last delivered event saved to store.
+	// Subscription resumes after saved position, reconnects on GRPCStreamError and SERVICE_UNAVAILABLE status,
+	// gaps in block numbers are reported as ErrBlockGap and terminate subscription.
+	// See CheckpointSubscription for delivery guarantees
+	SubscribeCCCheckpointed(ctx context.Context, channelName string, ccName string, store CheckpointStore, opts ...CheckpointOpt) (CheckpointSubscription, error)
+	// SubscribeFilteredBlock allows to subscribe on filtered blocks, which contain only tx ids, validation codes
+	// and chaincode events without payload. Filtered blocks don't require block read permission
+	SubscribeFilteredBlock(ctx context.Context, channelName string, seekOpt ...EventCCSeekOption) (FilteredBlockSubscription, error)
+	// SubscribeFilteredCC allows to subscribe on chaincode events using filtered blocks, events have no payload
+	SubscribeFilteredCC(ctx context.Context, channelName string, ccName string, seekOpt ...EventCCSeekOption) (EventCCSubscription, error)
+	// SubscribeFilteredTx allows to subscribe on transaction events by id using filtered blocks
+	SubscribeFilteredTx(ctx context.Context, channelName string, tx ChaincodeTx, seekOpt ...EventCCSeekOption) (TxSubscription, error)
+	// SubscribeBlockWithPrivateData allows to subscribe on blocks with private data of collections
+	// which organization of identity is member of
+	SubscribeBlockWithPrivateData(ctx context.Context, channelName string, seekOpt ...EventCCSeekOption) (BlockWithPrivateDataSubscription, error)
 }
 
 type EventCCSeekOption func() (*orderer.SeekPosition, *orderer.SeekPosition)
@@ -82,6 +98,32 @@ type BlockSubscription interface {
 	Close() error
 }
 
+type FilteredBlockSubscription interface {
+	Blocks() <-chan *peer.FilteredBlock
+	Errors() chan error
+	Close() error
+}
+
+// BlockWithPrivateData is block with decoded private data of collections
+type BlockWithPrivateData struct {
+	Block *common.Block
+	// PvtDataCollections is private data keyed by index of tx in block
+	PvtDataCollections map[uint64][]*CollectionPvtData
+}
+
+// CollectionPvtData is private read-write set of collection written by tx
+type CollectionPvtData struct {
+	Namespace  string
+	Collection string
+	RWSet      *kvrwset.KVRWSet
+}
+
+type BlockWithPrivateDataSubscription interface {
+	Blocks() <-chan *BlockWithPrivateData
+	Errors() chan error
+	Close() error
+}
+
 type TxEvent struct {
 	TxId    ChaincodeTx
 	Success bool
@@ -98,6 +140,16 @@ func (e GRPCStreamError) Error() string {
 	return fmt.Sprintf("grpc stream error: %s", e.Err)
 }
 
+// ErrUnexpectedDeliverStatus is returned when peer terminates deliver stream with non-SUCCESS status,
+// e.g. FORBIDDEN, NOT_FOUND or SERVICE_UNAVAILABLE
+type ErrUnexpectedDeliverStatus struct {
+	Status common.Status
+}
+
+func (e ErrUnexpectedDeliverStatus) Error() string {
+	return fmt.Sprintf("unexpected deliver status: %s", e.Status)
+}
+
 type EnvelopeParsingError struct {
 	Err error
 }
@@ -28,6 +28,8 @@ func (e ErrNoReadyPeers) Error() string {
 type PeerPool interface {
 	Add(mspId string, peer Peer, strategy PeerPoolCheckStrategy) error
 	Process(ctx context.Context, mspId string, proposal *peer.SignedProposal) (*peer.ProposalResponse, error)
+	// Peers returns all peers of presented MSP, including not ready ones
+	Peers(mspId string) ([]Peer, error)
 	DeliverClient(mspId string, identity msp.SigningIdentity) (DeliverClient, error)
 	Close() error
 }
@@ -0,0 +1,118 @@
+package ccpackage
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+
+	"github.com/hyperledger/fabric/core/chaincode/persistence"
+	"github.com/hyperledger/fabric/core/chaincode/platforms/golang"
+	"github.com/pkg/errors"
+)
+
+// Connection describes connection.json used by chaincode-as-a-service and external builders
+type Connection struct {
+	Address            string `json:"address"`
+	DialTimeout        string `json:"dial_timeout,omitempty"`
+	TLSRequired        bool   `json:"tls_required"`
+	ClientAuthRequired bool   `json:"client_auth_required,omitempty"`
+	ClientKey          string `json:"client_key,omitempty"`
+	ClientCert         string `json:"client_cert,omitempty"`
+	RootCert           string `json:"root_cert,omitempty"`
+}
+
+// New builds chaincode package from metadata and code package (gzipped tar)
+func New(metadata Metadata, codePackage []byte) (*Package, error) {
+	if err := persistence.ValidateLabel(metadata.Label); err != nil {
+		return nil, err
+	}
+
+	metadataBytes, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to marshal chaincode package metadata into JSON`)
+	}
+
+	raw, err := tarGz([]tarFile{
+		{name: MetadataFile, content: metadataBytes},
+		{name: CodePackageFile, content: codePackage},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to create chaincode package`)
+	}
+
+	return &Package{Metadata: metadata, CodePackage: codePackage, raw: raw}, nil
+}
+
+// NewGo builds chaincode package from Go source code located in GOPATH or module directory
+func NewGo(label, path string) (*Package, error) {
+	platform := &golang.Platform{}
+
+	normalizedPath, err := platform.NormalizePath(path)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to normalize chaincode path`)
+	}
+
+	codePackage, err := platform.GetDeploymentPayload(path)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to get chaincode code package`)
+	}
+
+	return New(Metadata{Path: normalizedPath, Type: TypeGolang, Label: label}, codePackage)
+}
+
+// NewExternal builds chaincode package with connection.json for chaincode-as-a-service or external builder,
+// ccType is used by builder to detect package, i.e. TypeCCaaS or TypeExternal
+func NewExternal(label, ccType string, conn Connection) (*Package, error) {
+	if conn.Address == `` {
+		return nil, errors.New(`chaincode address is required`)
+	}
+
+	connBytes, err := json.Marshal(conn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal %s", ConnectionFile)
+	}
+
+	codePackage, err := tarGz([]tarFile{{name: ConnectionFile, content: connBytes}})
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to create code package`)
+	}
+
+	return New(Metadata{Type: ccType, Label: label}, codePackage)
+}
+
+type tarFile struct {
+	name    string
+	content []byte
+}
+
+func tarGz(files []tarFile) ([]byte, error) {
+	payload := bytes.NewBuffer(nil)
+	gw := gzip.NewWriter(payload)
+	tw := tar.NewWriter(gw)
+
+	for _, file := range files {
+		err := tw.WriteHeader(&tar.Header{
+			Name: file.name,
+			Size: int64(len(file.content)),
+			Mode: 0100644,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to write %s header", file.name)
+		}
+
+		if _, err = tw.Write(file.content); err != nil {
+			return nil, errors.Wrapf(err, "failed to write %s", file.name)
+		}
+	}
+
+	err := tw.Close()
+	if err == nil {
+		err = gw.Close()
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to close tar`)
+	}
+
+	return payload.Bytes(), nil
+}
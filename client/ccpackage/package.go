@@ -0,0 +1,209 @@
+// Package ccpackage allows to build and inspect Fabric v2 lifecycle chaincode packages offline
+package ccpackage
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/chaincode/persistence"
+	"github.com/pkg/errors"
+)
+
+const (
+	MetadataFile    = `metadata.json`
+	CodePackageFile = `code.tar.gz`
+	ConnectionFile  = `connection.json`
+
+	// TypeGolang is type of package with Go chaincode source code
+	TypeGolang = `golang`
+	// TypeCCaaS is type of package for chaincode-as-a-service builder
+	TypeCCaaS = `ccaas`
+	// TypeExternal is type of package for custom external builders
+	TypeExternal = `external`
+)
+
+// Metadata describes contents of metadata.json in chaincode package
+type Metadata struct {
+	Path  string `json:"path"`
+	Type  string `json:"type"`
+	Label string `json:"label"`
+}
+
+// File describes file from code package of chaincode package
+type File struct {
+	Name string
+	Size int64
+	Mode int64
+	Dir  bool
+}
+
+// Package is parsed chaincode package
+type Package struct {
+	Metadata    Metadata
+	CodePackage []byte
+	raw         []byte
+}
+
+// Bytes returns raw chaincode package which is used for InstallChaincode
+func (p *Package) Bytes() []byte {
+	return p.raw
+}
+
+// Hash returns SHA-256 hash of raw chaincode package
+func (p *Package) Hash() []byte {
+	return util.ComputeSHA256(p.raw)
+}
+
+// ID returns package id exactly as peer computes it on install
+func (p *Package) ID() string {
+	return PackageID(p.Metadata.Label, p.raw)
+}
+
+// Files returns list of files inside code package
+func (p *Package) Files() ([]File, error) {
+	files := make([]File, 0)
+	err := walkTarGz(p.CodePackage, func(header *tar.Header, _ io.Reader) error {
+		files = append(files, File{
+			Name: header.Name,
+			Size: header.Size,
+			Mode: header.Mode,
+			Dir:  header.Typeflag == tar.TypeDir,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to read code package`)
+	}
+	return files, nil
+}
+
+// File returns content of file inside code package
+func (p *Package) File(name string) ([]byte, error) {
+	var content []byte
+	err := walkTarGz(p.CodePackage, func(header *tar.Header, r io.Reader) error {
+		if header.Name != name {
+			return nil
+		}
+		var err error
+		content, err = ioutil.ReadAll(r)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to read code package`)
+	}
+	if content == nil {
+		return nil, errors.Errorf("file %s not found in code package", name)
+	}
+	return content, nil
+}
+
+// Connection returns connection.json contents of external chaincode package
+func (p *Package) Connection() (*Connection, error) {
+	connBytes, err := p.File(ConnectionFile)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := new(Connection)
+	if err = json.Unmarshal(connBytes, conn); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal %s", ConnectionFile)
+	}
+	return conn, nil
+}
+
+// WriteFile writes chaincode package to file
+func (p *Package) WriteFile(path string) error {
+	if err := ioutil.WriteFile(path, p.raw, 0644); err != nil {
+		return errors.Wrap(err, `failed to write package file`)
+	}
+	return nil
+}
+
+// PackageID returns package id for label and raw chaincode package
+func PackageID(label string, raw []byte) string {
+	return fmt.Sprintf("%s:%x", label, util.ComputeSHA256(raw))
+}
+
+// Parse parses raw chaincode package
+func Parse(raw []byte) (*Package, error) {
+	var (
+		metadata    *Metadata
+		codePackage []byte
+	)
+
+	err := walkTarGz(raw, func(header *tar.Header, r io.Reader) error {
+		if header.Typeflag != tar.TypeReg {
+			return errors.Errorf("tar entry %s is not a regular file, type %v", header.Name, header.Typeflag)
+		}
+
+		fileBytes, err := ioutil.ReadAll(r)
+		if err != nil {
+			return errors.Wrapf(err, "could not read %s from tar", header.Name)
+		}
+
+		switch header.Name {
+		case MetadataFile:
+			metadata = new(Metadata)
+			if err = json.Unmarshal(fileBytes, metadata); err != nil {
+				return errors.Wrapf(err, "could not unmarshal %s as json", MetadataFile)
+			}
+		case CodePackageFile:
+			codePackage = fileBytes
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if codePackage == nil {
+		return nil, errors.New(`did not find a code package inside the package`)
+	}
+
+	if metadata == nil {
+		return nil, errors.Errorf("did not find any package metadata (missing %s)", MetadataFile)
+	}
+
+	if err = persistence.ValidateLabel(metadata.Label); err != nil {
+		return nil, err
+	}
+
+	return &Package{Metadata: *metadata, CodePackage: codePackage, raw: raw}, nil
+}
+
+// ReadFile reads and parses chaincode package from file
+func ReadFile(path string) (*Package, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to read package file`)
+	}
+	return Parse(raw)
+}
+
+func walkTarGz(source []byte, fn func(header *tar.Header, r io.Reader) error) error {
+	gzReader, err := gzip.NewReader(bytes.NewReader(source))
+	if err != nil {
+		return errors.Wrap(err, `error reading as gzip stream`)
+	}
+
+	tarReader := tar.NewReader(gzReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, `error inspecting next tar header`)
+		}
+
+		if err = fn(header, tarReader); err != nil {
+			return err
+		}
+	}
+}
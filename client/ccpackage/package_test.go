@@ -0,0 +1,101 @@
+package ccpackage_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/chaincode/persistence"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bogatyr285/hlf-sdk-go/client/ccpackage"
+)
+
+type noDBArtifacts struct{}
+
+func (noDBArtifacts) GetDBArtifacts([]byte) ([]byte, error) {
+	return nil, nil
+}
+
+func TestExternalPackage(t *testing.T) {
+	conn := ccpackage.Connection{
+		Address:     `mycc:9999`,
+		DialTimeout: `10s`,
+	}
+
+	pkg, err := ccpackage.NewExternal(`mycc_1.0`, ccpackage.TypeCCaaS, conn)
+	require.NoError(t, err)
+
+	// package must be readable by peer
+	peerPkg, err := persistence.ChaincodePackageParser{MetadataProvider: noDBArtifacts{}}.Parse(pkg.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, `mycc_1.0`, peerPkg.Metadata.Label)
+	require.Equal(t, ccpackage.TypeCCaaS, peerPkg.Metadata.Type)
+
+	// package id must be the same as peer computes on install
+	dir, err := ioutil.TempDir(``, `ccpackage`)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	peerPkgID, err := persistence.NewStore(dir).Save(`mycc_1.0`, pkg.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, peerPkgID, pkg.ID())
+
+	// package read back from file
+	pkgPath := filepath.Join(dir, `mycc.tar.gz`)
+	require.NoError(t, pkg.WriteFile(pkgPath))
+
+	readPkg, err := ccpackage.ReadFile(pkgPath)
+	require.NoError(t, err)
+	require.Equal(t, pkg.ID(), readPkg.ID())
+	require.Equal(t, pkg.Metadata, readPkg.Metadata)
+
+	files, err := readPkg.Files()
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, ccpackage.ConnectionFile, files[0].Name)
+
+	readConn, err := readPkg.Connection()
+	require.NoError(t, err)
+	require.Equal(t, conn, *readConn)
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := ccpackage.Parse([]byte(`not a package`))
+	require.Error(t, err)
+
+	_, err = ccpackage.NewExternal(`invalid label`, ccpackage.TypeCCaaS, ccpackage.Connection{Address: `mycc:9999`})
+	require.Error(t, err)
+}
+
+func TestGoPackage(t *testing.T) {
+	pkg, err := ccpackage.NewGo(`example_cc_1.0`, `github.com/bogatyr285/hlf-sdk-go/samples/example_cc`)
+	require.NoError(t, err)
+	require.Equal(t, ccpackage.TypeGolang, pkg.Metadata.Type)
+
+	peerPkg, err := persistence.ChaincodePackageParser{MetadataProvider: noDBArtifacts{}}.Parse(pkg.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, `example_cc_1.0`, peerPkg.Metadata.Label)
+	require.Equal(t, `golang`, peerPkg.Metadata.Type)
+	require.Equal(t, pkg.Metadata.Path, peerPkg.Metadata.Path)
+	require.Equal(t, pkg.CodePackage, peerPkg.CodePackage)
+
+	dir, err := ioutil.TempDir(``, `ccpackage`)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	peerPkgID, err := persistence.NewStore(dir).Save(`example_cc_1.0`, pkg.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, peerPkgID, pkg.ID())
+	require.Equal(t, ccpackage.PackageID(`example_cc_1.0`, pkg.Bytes()), pkg.ID())
+
+	// code package contains chaincode source
+	files, err := pkg.Files()
+	require.NoError(t, err)
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name)
+	}
+	require.Contains(t, names, `src/github.com/bogatyr285/hlf-sdk-go/samples/example_cc/main.go`)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/client/chaincode/system"
+	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/pkg/errors"
 )
@@ -16,6 +18,8 @@ type Core struct {
 	orderer     api.Orderer
 	dp          api.DiscoveryProvider
 	identity    msp.SigningIdentity
+	fetcher     api.CCFetcher
+	fabricV2    bool
 }
 
 func (c *Core) Invoke(fn string) api.ChaincodeInvokeBuilder {
@@ -26,8 +30,46 @@ func (c *Core) Query(fn string, args ...string) api.ChaincodeQueryBuilder {
 	return NewQueryBuilder(c, c.identity, fn, args...)
 }
 
-func (c *Core) Install(version string) {
-	panic("implement me")
+func (c *Core) Install(ctx context.Context, version string) error {
+	// chaincode path is taken from chaincode deployed on channel
+	deployed, err := deployedSpec(ctx, system.NewSCC(c.peerPool, c.orderer, c.identity, c.fabricV2), c.fabricV2, c.channelName, c.name)
+	if err != nil {
+		return errors.Wrap(err, `failed to get deployed chaincode`)
+	}
+	if deployed.ChaincodeSpec.ChaincodeId.Path == `` {
+		return errors.Wrap(api.ErrChaincodeNotInstalled, `failed to get path of deployed chaincode`)
+	}
+
+	depSpec, err := c.fetcher.Fetch(ctx, &peer.ChaincodeID{
+		Name:    c.name,
+		Path:    deployed.ChaincodeSpec.ChaincodeId.Path,
+		Version: version,
+	})
+	if err != nil {
+		return errors.Wrap(err, `failed to fetch package`)
+	}
+
+	prop, err := installProposal(depSpec, c.identity, c.fabricV2)
+	if err != nil {
+		return err
+	}
+
+	peers, err := c.peerPool.Peers(c.mspId)
+	if err != nil {
+		return errors.Wrap(err, `failed to get peers`)
+	}
+
+	mErr := new(api.MultiError)
+	for _, p := range peers {
+		if _, err = p.Endorse(ctx, prop); err != nil {
+			mErr.Add(errors.Wrap(err, p.Uri()))
+		}
+	}
+
+	if len(mErr.Errors) != 0 {
+		return mErr
+	}
+	return nil
 }
 
 func (c *Core) Subscribe(ctx context.Context) (api.EventCCSubscription, error) {
@@ -38,7 +80,15 @@ func (c *Core) Subscribe(ctx context.Context) (api.EventCCSubscription, error) {
 	return peerDeliver.SubscribeCC(ctx, c.channelName, c.name)
 }
 
-func NewCore(mspId, ccName, channelName string, peerPool api.PeerPool, orderer api.Orderer, dp api.DiscoveryProvider, identity msp.SigningIdentity) *Core {
+func NewCore(
+	mspId, ccName, channelName string,
+	peerPool api.PeerPool,
+	orderer api.Orderer,
+	dp api.DiscoveryProvider,
+	identity msp.SigningIdentity,
+	fetcher api.CCFetcher,
+	fabricV2 bool,
+) *Core {
 	return &Core{
 		mspId:       mspId,
 		name:        ccName,
@@ -47,5 +97,7 @@ func NewCore(mspId, ccName, channelName string, peerPool api.PeerPool, orderer a
 		orderer:     orderer,
 		dp:          dp,
 		identity:    identity,
+		fetcher:     fetcher,
+		fabricV2:    fabricV2,
 	}
 }
@@ -2,6 +2,8 @@ package chaincode
 
 import (
 	"context"
+	"strconv"
+	"strings"
 
 	"github.com/hyperledger/fabric/common/policydsl"
 	"github.com/hyperledger/fabric/protoutil"
@@ -16,14 +18,61 @@ import (
 
 type corePackage struct {
 	ccName   string
+	scc      api.SystemCC
 	lscc     api.LSCC
 	fetcher  api.CCFetcher
 	orderer  api.Orderer
 	identity msp.SigningIdentity
+	fabricV2 bool
 }
 
 func (c *corePackage) Latest(ctx context.Context) (*peer.ChaincodeDeploymentSpec, error) {
-	panic("implement me")
+	channels, err := c.scc.CSCC().GetChannels(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to get channels`)
+	}
+
+	var latest *peer.ChaincodeDeploymentSpec
+
+	// chaincode may be deployed with different versions on channels, the highest one is returned
+	for _, ch := range channels.Channels {
+		depSpec, err := deployedSpec(ctx, c.scc, c.fabricV2, ch.ChannelId, c.ccName)
+		if err != nil {
+			if isNotDeployed(err, c.ccName) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get chaincode on channel %s", ch.ChannelId)
+		}
+
+		if latest == nil || compareVersions(depSpec.ChaincodeSpec.ChaincodeId.Version, latest.ChaincodeSpec.ChaincodeId.Version) > 0 {
+			latest = depSpec
+		}
+	}
+
+	if latest == nil {
+		return nil, api.ErrChaincodeNotFound
+	}
+
+	return latest, nil
+}
+
+// compareVersions compares dot separated versions, numeric parts are compared as numbers, others as strings
+func compareVersions(a, b string) int {
+	aParts, bParts := strings.Split(a, `.`), strings.Split(b, `.`)
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNum, aErr := strconv.ParseUint(aParts[i], 10, 64)
+		bNum, bErr := strconv.ParseUint(bParts[i], 10, 64)
+		switch {
+		case aErr == nil && bErr == nil && aNum != bNum:
+			if aNum > bNum {
+				return 1
+			}
+			return -1
+		case (aErr != nil || bErr != nil) && aParts[i] != bParts[i]:
+			return strings.Compare(aParts[i], bParts[i])
+		}
+	}
+	return len(aParts) - len(bParts)
 }
 
 func (c *corePackage) Install(ctx context.Context, path, version string) error {
@@ -56,14 +105,14 @@ func (c *corePackage) Instantiate(ctx context.Context, channelName, path, versio
 		Version: version,
 	})
 
-	depSpec.ChaincodeSpec.Input = &peer.ChaincodeInput{
-		Args: args,
-	}
-
 	if err != nil {
 		return errors.Wrap(err, `failed to fetch package`)
 	}
 
+	depSpec.ChaincodeSpec.Input = &peer.ChaincodeInput{
+		Args: args,
+	}
+
 	prop, resp, err := c.lscc.Deploy(ctx, channelName, depSpec, ePolicy, api.WithTransientMap(transArgs))
 	if err != nil {
 		return errors.Wrap(err, `failed to deploy chaincode`)
@@ -85,6 +134,6 @@ func (c *corePackage) Instantiate(ctx context.Context, channelName, path, versio
 	return err
 }
 
-func NewCorePackage(ccName string, lscc api.LSCC, fetcher api.CCFetcher, orderer api.Orderer, identity msp.SigningIdentity) api.ChaincodePackage {
-	return &corePackage{ccName: ccName, lscc: lscc, fetcher: fetcher, orderer: orderer, identity: identity}
+func NewCorePackage(ccName string, scc api.SystemCC, fetcher api.CCFetcher, orderer api.Orderer, identity msp.SigningIdentity, fabricV2 bool) api.ChaincodePackage {
+	return &corePackage{ccName: ccName, scc: scc, lscc: scc.LSCC(), fetcher: fetcher, orderer: orderer, identity: identity, fabricV2: fabricV2}
 }
@@ -0,0 +1,175 @@
+package chaincode
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	lsccPkg "github.com/hyperledger/fabric/core/scc/lscc"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/pkg/errors"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/client/ccpackage"
+	peerSDK "github.com/bogatyr285/hlf-sdk-go/peer"
+)
+
+const lsccName = `lscc`
+
+// messages of lscc and _lifecycle responses, which mean that requested chaincode is absent,
+// peer returns them wrapped by other errors, so they are matched as substrings
+const (
+	// lscc: chaincode is not instantiated on channel
+	lsccChaincodeNotFound = `could not find chaincode with name '%s'`
+	// lscc: chaincode is instantiated, but its package is not installed on peer
+	lsccInvalidDeploymentSpec = `invalid deployment spec: `
+	// _lifecycle: chaincode definition is not committed on channel
+	lifecycleNamespaceNotDefined = `namespace %s is not defined`
+	// _lifecycle: committed definition is not approved by MSP of peer
+	lifecycleApprovedNotFound = `could not fetch approved chaincode definition (name: '%s', sequence: '%d')`
+	// _lifecycle: approved package is not installed on peer
+	lifecyclePackageNotFound = `chaincode install package '%s' not found`
+)
+
+// deployedSpec returns deployment spec of chaincode instantiated (fabric v1) or committed (fabric v2) on channel.
+// Code package is presented only if chaincode package is installed on peer of current MSP
+func deployedSpec(ctx context.Context, scc api.SystemCC, fabricV2 bool, channelName, ccName string) (*peer.ChaincodeDeploymentSpec, error) {
+	if fabricV2 {
+		return committedSpec(ctx, scc.Lifecycle(), channelName, ccName)
+	}
+	return instantiatedSpec(ctx, scc.LSCC(), channelName, ccName)
+}
+
+func instantiatedSpec(ctx context.Context, lscc api.LSCC, channelName, ccName string) (*peer.ChaincodeDeploymentSpec, error) {
+	ccData, err := lscc.GetChaincodeData(ctx, channelName, ccName)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to get chaincode data`)
+	}
+
+	depSpec, err := lscc.GetDeploymentSpec(ctx, channelName, ccName)
+	if err != nil {
+		if isPeerMessage(err, lsccInvalidDeploymentSpec) {
+			// chaincode is instantiated, but not installed on peer
+			return newDeploymentSpec(ccData.Name, ccData.Version), nil
+		}
+		return nil, errors.Wrap(err, `failed to get deployment spec`)
+	}
+
+	return depSpec, nil
+}
+
+func committedSpec(ctx context.Context, lc api.Lifecycle, channelName, ccName string) (*peer.ChaincodeDeploymentSpec, error) {
+	definition, err := lc.QueryChaincodeDefinition(ctx, channelName, &lb.QueryChaincodeDefinitionArgs{Name: ccName})
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to get chaincode definition`)
+	}
+
+	depSpec := newDeploymentSpec(ccName, definition.Version)
+
+	// package id is known only if current MSP approved committed definition
+	approved, err := lc.QueryApprovedChaincodeDefinition(ctx, channelName, &lb.QueryApprovedChaincodeDefinitionArgs{
+		Name:     ccName,
+		Sequence: definition.Sequence,
+	})
+	if err != nil {
+		if isPeerMessage(err, fmt.Sprintf(lifecycleApprovedNotFound, ccName, definition.Sequence)) {
+			return depSpec, nil
+		}
+		return nil, errors.Wrap(err, `failed to get approved chaincode definition`)
+	}
+
+	localPackage := approved.GetSource().GetLocalPackage()
+	if localPackage == nil {
+		return depSpec, nil
+	}
+
+	installed, err := lc.GetInstalledChaincodePackage(ctx, &lb.GetInstalledChaincodePackageArgs{PackageId: localPackage.PackageId})
+	if err != nil {
+		if isPeerMessage(err, fmt.Sprintf(lifecyclePackageNotFound, localPackage.PackageId)) {
+			return depSpec, nil
+		}
+		return nil, errors.Wrap(err, `failed to get installed chaincode package`)
+	}
+
+	pkg, err := ccpackage.Parse(installed.ChaincodeInstallPackage)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to parse installed chaincode package`)
+	}
+
+	depSpec.ChaincodeSpec.Type = ccTypeFromPackage(pkg.Metadata.Type)
+	depSpec.ChaincodeSpec.ChaincodeId.Path = pkg.Metadata.Path
+	depSpec.CodePackage = pkg.CodePackage
+
+	return depSpec, nil
+}
+
+// installProposal returns proposal which installs chaincode on peer using lscc (fabric v1) or _lifecycle (fabric v2)
+func installProposal(depSpec *peer.ChaincodeDeploymentSpec, identity msp.SigningIdentity, fabricV2 bool) (*peer.SignedProposal, error) {
+	var (
+		ccName, fn string
+		args       []byte
+		err        error
+	)
+
+	if fabricV2 {
+		ccID := depSpec.ChaincodeSpec.ChaincodeId
+		var pkg *ccpackage.Package
+		pkg, err = ccpackage.New(ccpackage.Metadata{
+			Path:  ccID.Path,
+			Type:  strings.ToLower(depSpec.ChaincodeSpec.Type.String()),
+			Label: fmt.Sprintf("%s_%s", ccID.Name, ccID.Version),
+		}, depSpec.CodePackage)
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to build chaincode package`)
+		}
+
+		ccName, fn = lifecycle.LifecycleNamespace, lifecycle.InstallChaincodeFuncName
+		args, err = proto.Marshal(&lb.InstallChaincodeArgs{ChaincodeInstallPackage: pkg.Bytes()})
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to marshal protobuf`)
+		}
+	} else {
+		ccName, fn = lsccName, lsccPkg.INSTALL
+		args, err = proto.Marshal(depSpec)
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to marshal protobuf`)
+		}
+	}
+
+	prop, _, err := peerSDK.NewProcessor(``).CreateProposal(ccName, identity, fn, [][]byte{args}, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to create proposal`)
+	}
+	return prop, nil
+}
+
+// isNotDeployed returns true if error of lscc or _lifecycle query means that chaincode is not deployed on channel
+func isNotDeployed(err error, ccName string) bool {
+	return isPeerMessage(err, fmt.Sprintf(lsccChaincodeNotFound, ccName)) ||
+		isPeerMessage(err, fmt.Sprintf(lifecycleNamespaceNotDefined, ccName))
+}
+
+// isPeerMessage returns true if error is peer response containing presented message
+func isPeerMessage(err error, msg string) bool {
+	endorseErr, ok := errors.Cause(err).(api.PeerEndorseError)
+	return ok && strings.Contains(endorseErr.Message, msg)
+}
+
+func newDeploymentSpec(ccName, version string) *peer.ChaincodeDeploymentSpec {
+	return &peer.ChaincodeDeploymentSpec{
+		ChaincodeSpec: &peer.ChaincodeSpec{
+			ChaincodeId: &peer.ChaincodeID{Name: ccName, Version: version},
+		},
+	}
+}
+
+func ccTypeFromPackage(pkgType string) peer.ChaincodeSpec_Type {
+	if ccType, ok := peer.ChaincodeSpec_Type_value[strings.ToUpper(pkgType)]; ok {
+		return peer.ChaincodeSpec_Type(ccType)
+	}
+	return peer.ChaincodeSpec_UNDEFINED
+}
@@ -0,0 +1,382 @@
+package chaincode_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	lsccPkg "github.com/hyperledger/fabric/core/scc/lscc"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/client/ccpackage"
+	"github.com/bogatyr285/hlf-sdk-go/client/chaincode"
+	"github.com/bogatyr285/hlf-sdk-go/client/chaincode/system"
+	"github.com/bogatyr285/hlf-sdk-go/crypto"
+	"github.com/bogatyr285/hlf-sdk-go/crypto/ecdsa"
+	"github.com/bogatyr285/hlf-sdk-go/identity"
+)
+
+type sccResult struct {
+	msg proto.Message
+	err error
+}
+
+// sccPool answers system chaincode proposals by `channel/chaincode/function` key,
+// proposals without answer fail as not deployed chaincode
+type sccPool struct {
+	api.PeerPool
+	results map[string]sccResult
+	peers   []api.Peer
+}
+
+func proposalKey(signed *peer.SignedProposal) (string, [][]byte, error) {
+	prop, err := protoutil.UnmarshalProposal(signed.ProposalBytes)
+	if err != nil {
+		return ``, nil, err
+	}
+	header, err := protoutil.UnmarshalHeader(prop.Header)
+	if err != nil {
+		return ``, nil, err
+	}
+	chHeader, err := protoutil.UnmarshalChannelHeader(header.ChannelHeader)
+	if err != nil {
+		return ``, nil, err
+	}
+	propPayload, err := protoutil.UnmarshalChaincodeProposalPayload(prop.Payload)
+	if err != nil {
+		return ``, nil, err
+	}
+	spec := new(peer.ChaincodeInvocationSpec)
+	if err = proto.Unmarshal(propPayload.Input, spec); err != nil {
+		return ``, nil, err
+	}
+	args := spec.ChaincodeSpec.Input.Args
+	return chHeader.ChannelId + `/` + spec.ChaincodeSpec.ChaincodeId.Name + `/` + string(args[0]), args[1:], nil
+}
+
+// responses of peer with system chaincode errors
+var (
+	lsccNotFound          = api.PeerEndorseError{Status: 500, Message: `could not find chaincode with name 'mycc'`}
+	lsccNotInstalled      = api.PeerEndorseError{Status: 500, Message: `invalid deployment spec: open /var/hyperledger/production/chaincodes/mycc.1.0: no such file or directory`}
+	lsccAccessDenied      = api.PeerEndorseError{Status: 500, Message: `access denied for [getdepspec][ch1]: Failed to authorize invocation due to failed ACL check: Failed verifying that proposal's creator satisfies local MSP principal during channelless check policy with policy [Admins]: [The identity is not an admin under this MSP [org1msp]: The identity does not contain OU [ADMIN], MSP: [org1msp]]`}
+	lifecycleNotDefined   = api.PeerEndorseError{Status: 500, Message: `failed to invoke backing implementation of 'QueryChaincodeDefinition': namespace mycc is not defined`}
+	lifecycleNotApproved  = api.PeerEndorseError{Status: 500, Message: `failed to invoke backing implementation of 'QueryApprovedChaincodeDefinition': could not fetch approved chaincode definition (name: 'mycc', sequence: '1') on channel 'ch1'`}
+	lifecycleNotInstalled = api.PeerEndorseError{Status: 500, Message: `failed to invoke backing implementation of 'GetInstalledChaincodePackage': could not load cc install package: chaincode install package 'mycc_1.0:abc' not found`}
+	lifecycleAccessDenied = api.PeerEndorseError{Status: 500, Message: `Failed to authorize invocation due to failed ACL check: Failed deserializing proposal creator during channelless check policy with policy [Admins]: [expected MSP ID org1msp, received org2msp]`}
+)
+
+func (p *sccPool) Process(_ context.Context, _ string, signed *peer.SignedProposal) (*peer.ProposalResponse, error) {
+	key, _, err := proposalKey(signed)
+	if err != nil {
+		return nil, err
+	}
+
+	result, ok := p.results[key]
+	if !ok {
+		return nil, lsccNotFound
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	payload, err := proto.Marshal(result.msg)
+	if err != nil {
+		return nil, err
+	}
+	return &peer.ProposalResponse{Response: &peer.Response{Status: 200, Payload: payload}}, nil
+}
+
+func (p *sccPool) Peers(string) ([]api.Peer, error) {
+	return p.peers, nil
+}
+
+// installPeer records install proposals
+type installPeer struct {
+	api.Peer
+	mx        sync.Mutex
+	installed map[string][]byte
+	err       error
+}
+
+func (p *installPeer) Endorse(_ context.Context, signed *peer.SignedProposal, _ ...api.PeerEndorseOpt) (*peer.ProposalResponse, error) {
+	key, args, err := proposalKey(signed)
+	if err != nil {
+		return nil, err
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	p.mx.Lock()
+	defer p.mx.Unlock()
+	p.installed[key] = args[0]
+	return &peer.ProposalResponse{Response: &peer.Response{Status: 200}}, nil
+}
+
+func (p *installPeer) Uri() string {
+	return `peer0:7051`
+}
+
+// specFetcher returns deployment spec with requested chaincode id
+type specFetcher struct{}
+
+func (specFetcher) Fetch(_ context.Context, id *peer.ChaincodeID) (*peer.ChaincodeDeploymentSpec, error) {
+	return &peer.ChaincodeDeploymentSpec{
+		ChaincodeSpec: &peer.ChaincodeSpec{Type: peer.ChaincodeSpec_GOLANG, ChaincodeId: id},
+		CodePackage:   []byte(`code`),
+	}, nil
+}
+
+func channels(names ...string) sccResult {
+	resp := new(peer.ChannelQueryResponse)
+	for _, name := range names {
+		resp.Channels = append(resp.Channels, &peer.ChannelInfo{ChannelId: name})
+	}
+	return sccResult{msg: resp}
+}
+
+func TestCorePackage_Latest(t *testing.T) {
+	cs, err := crypto.GetSuite(ecdsa.Module, ecdsa.DefaultOpts)
+	require.NoError(t, err)
+	id, err := identity.NewMSPIdentityFromPath(`org1msp`, `./testdata/msp`)
+	require.NoError(t, err)
+	signer := id.GetSigningIdentity(cs)
+
+	getChannels := `/cscc/GetChannels`
+	lsccData := func(channel string) string { return channel + `/lscc/` + lsccPkg.GETCCDATA }
+	lsccSpec := func(channel string) string { return channel + `/lscc/` + lsccPkg.GETDEPSPEC }
+	definition := func(channel string) string {
+		return channel + `/_lifecycle/` + lifecycle.QueryChaincodeDefinitionFuncName
+	}
+	approved := func(channel string) string {
+		return channel + `/_lifecycle/` + lifecycle.QueryApprovedChaincodeDefinitionFuncName
+	}
+	approvedPackage := sccResult{msg: &lb.QueryApprovedChaincodeDefinitionResult{
+		Source: &lb.ChaincodeSource{Type: &lb.ChaincodeSource_LocalPackage{
+			LocalPackage: &lb.ChaincodeSource_Local{PackageId: `mycc_1.0:abc`}}}}}
+	installedPackage := `/_lifecycle/` + system.GetInstalledChaincodePackageFuncName
+
+	for _, tc := range []struct {
+		name       string
+		fabricV2   bool
+		results    map[string]sccResult
+		expVersion string
+		expPath    string
+		expErr     string
+	}{
+		{
+			name: `fabric v1`,
+			results: map[string]sccResult{
+				getChannels:     channels(`ch1`, `ch2`),
+				lsccData(`ch1`): {msg: &ccprovider.ChaincodeData{Name: `mycc`, Version: `1.0`}},
+				lsccSpec(`ch1`): {msg: &peer.ChaincodeDeploymentSpec{ChaincodeSpec: &peer.ChaincodeSpec{
+					ChaincodeId: &peer.ChaincodeID{Name: `mycc`, Version: `1.0`, Path: `path/mycc`}}}},
+			},
+			expVersion: `1.0`,
+			expPath:    `path/mycc`,
+		},
+		{
+			name:     `fabric v2`,
+			fabricV2: true,
+			results: map[string]sccResult{
+				getChannels:       channels(`ch1`, `ch2`),
+				definition(`ch1`): {msg: &lb.QueryChaincodeDefinitionResult{Version: `2.0`, Sequence: 1}},
+				definition(`ch2`): {err: lifecycleNotDefined},
+				approved(`ch1`):   {err: lifecycleNotApproved},
+			},
+			expVersion: `2.0`,
+		},
+		{
+			name: `fabric v1 not installed`,
+			results: map[string]sccResult{
+				getChannels:     channels(`ch1`),
+				lsccData(`ch1`): {msg: &ccprovider.ChaincodeData{Name: `mycc`, Version: `1.0`}},
+				lsccSpec(`ch1`): {err: lsccNotInstalled},
+			},
+			expVersion: `1.0`,
+		},
+		{
+			name:     `fabric v2 not installed`,
+			fabricV2: true,
+			results: map[string]sccResult{
+				getChannels:       channels(`ch1`),
+				definition(`ch1`): {msg: &lb.QueryChaincodeDefinitionResult{Version: `1.0`, Sequence: 1}},
+				approved(`ch1`):   approvedPackage,
+				installedPackage:  {err: lifecycleNotInstalled},
+			},
+			expVersion: `1.0`,
+		},
+		{
+			name: `different versions`,
+			results: map[string]sccResult{
+				getChannels:     channels(`ch1`, `ch2`, `ch3`),
+				lsccData(`ch1`): {msg: &ccprovider.ChaincodeData{Name: `mycc`, Version: `1.9`}},
+				lsccSpec(`ch1`): {err: lsccNotInstalled},
+				lsccData(`ch2`): {msg: &ccprovider.ChaincodeData{Name: `mycc`, Version: `1.10`}},
+				lsccSpec(`ch2`): {err: lsccNotInstalled},
+				lsccData(`ch3`): {msg: &ccprovider.ChaincodeData{Name: `mycc`, Version: `1.2`}},
+				lsccSpec(`ch3`): {err: lsccNotInstalled},
+			},
+			expVersion: `1.10`,
+		},
+		{
+			name: `deployment spec error is not hidden`,
+			results: map[string]sccResult{
+				getChannels:     channels(`ch1`),
+				lsccData(`ch1`): {msg: &ccprovider.ChaincodeData{Name: `mycc`, Version: `1.0`}},
+				lsccSpec(`ch1`): {err: lsccAccessDenied},
+			},
+			expErr: `failed to get chaincode on channel ch1: failed to get deployment spec`,
+		},
+		{
+			name:     `approved definition error is not hidden`,
+			fabricV2: true,
+			results: map[string]sccResult{
+				getChannels:       channels(`ch1`),
+				definition(`ch1`): {msg: &lb.QueryChaincodeDefinitionResult{Version: `1.0`, Sequence: 1}},
+				approved(`ch1`):   {err: lifecycleAccessDenied},
+			},
+			expErr: `failed to get chaincode on channel ch1: failed to get approved chaincode definition`,
+		},
+		{
+			name:     `installed package error is not hidden`,
+			fabricV2: true,
+			results: map[string]sccResult{
+				getChannels:       channels(`ch1`),
+				definition(`ch1`): {msg: &lb.QueryChaincodeDefinitionResult{Version: `1.0`, Sequence: 1}},
+				approved(`ch1`):   approvedPackage,
+				installedPackage:  {err: errors.New(`connection refused`)},
+			},
+			expErr: `failed to get chaincode on channel ch1: failed to get installed chaincode package`,
+		},
+		{
+			name: `other chaincode not found is not hidden`,
+			results: map[string]sccResult{
+				getChannels:     channels(`ch1`),
+				lsccData(`ch1`): {err: api.PeerEndorseError{Status: 500, Message: `could not find chaincode with name 'othercc'`}},
+			},
+			expErr: `failed to get chaincode on channel ch1`,
+		},
+		{
+			name:    `not deployed`,
+			results: map[string]sccResult{getChannels: channels(`ch1`)},
+			expErr:  api.ErrChaincodeNotFound.Error(),
+		},
+		{
+			name: `query error is not hidden`,
+			results: map[string]sccResult{
+				getChannels:     channels(`ch1`),
+				lsccData(`ch1`): {err: errors.New(`access denied`)},
+			},
+			expErr: `failed to get chaincode on channel ch1: failed to get chaincode data: failed to get chaincode data: failed to endorse proposal: access denied`,
+		},
+		{
+			name:    `channels error`,
+			results: map[string]sccResult{getChannels: {err: errors.New(`connection refused`)}},
+			expErr:  `failed to get channels`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pool := &sccPool{results: tc.results}
+			pkg := chaincode.NewCorePackage(`mycc`, system.NewSCC(pool, nil, signer, tc.fabricV2), nil, nil, signer, tc.fabricV2)
+
+			spec, err := pkg.Latest(context.Background())
+			if tc.expErr != `` {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expVersion, spec.ChaincodeSpec.ChaincodeId.Version)
+			require.Equal(t, tc.expPath, spec.ChaincodeSpec.ChaincodeId.Path)
+		})
+	}
+}
+
+func TestCore_Install(t *testing.T) {
+	cs, err := crypto.GetSuite(ecdsa.Module, ecdsa.DefaultOpts)
+	require.NoError(t, err)
+	id, err := identity.NewMSPIdentityFromPath(`org1msp`, `./testdata/msp`)
+	require.NoError(t, err)
+	signer := id.GetSigningIdentity(cs)
+
+	installedPkg, err := ccpackage.New(ccpackage.Metadata{Path: `path/mycc`, Type: ccpackage.TypeGolang, Label: `mycc_1.0`}, []byte(`code`))
+	require.NoError(t, err)
+
+	t.Run(`fabric v1`, func(t *testing.T) {
+		p := &installPeer{installed: make(map[string][]byte)}
+		pool := &sccPool{peers: []api.Peer{p}, results: map[string]sccResult{
+			`ch1/lscc/` + lsccPkg.GETCCDATA: {msg: &ccprovider.ChaincodeData{Name: `mycc`, Version: `1.0`}},
+			`ch1/lscc/` + lsccPkg.GETDEPSPEC: {msg: &peer.ChaincodeDeploymentSpec{ChaincodeSpec: &peer.ChaincodeSpec{
+				ChaincodeId: &peer.ChaincodeID{Name: `mycc`, Version: `1.0`, Path: `path/mycc`}}}},
+		}}
+		core := chaincode.NewCore(`org1msp`, `mycc`, `ch1`, pool, nil, nil, signer, specFetcher{}, false)
+		require.NoError(t, core.Install(context.Background(), `2.0`))
+
+		spec := new(peer.ChaincodeDeploymentSpec)
+		require.NoError(t, proto.Unmarshal(p.installed[`/lscc/`+lsccPkg.INSTALL], spec))
+		require.Equal(t, &peer.ChaincodeID{Name: `mycc`, Version: `2.0`, Path: `path/mycc`}, spec.ChaincodeSpec.ChaincodeId)
+	})
+
+	t.Run(`fabric v2`, func(t *testing.T) {
+		p := &installPeer{installed: make(map[string][]byte)}
+		pool := &sccPool{peers: []api.Peer{p}, results: map[string]sccResult{
+			`ch1/_lifecycle/` + lifecycle.QueryChaincodeDefinitionFuncName: {msg: &lb.QueryChaincodeDefinitionResult{Version: `1.0`, Sequence: 1}},
+			`ch1/_lifecycle/` + lifecycle.QueryApprovedChaincodeDefinitionFuncName: {msg: &lb.QueryApprovedChaincodeDefinitionResult{
+				Source: &lb.ChaincodeSource{Type: &lb.ChaincodeSource_LocalPackage{
+					LocalPackage: &lb.ChaincodeSource_Local{PackageId: installedPkg.ID()}}}}},
+			`/_lifecycle/` + system.GetInstalledChaincodePackageFuncName: {msg: &lb.GetInstalledChaincodePackageResult{
+				ChaincodeInstallPackage: installedPkg.Bytes()}},
+		}}
+		core := chaincode.NewCore(`org1msp`, `mycc`, `ch1`, pool, nil, nil, signer, specFetcher{}, true)
+		require.NoError(t, core.Install(context.Background(), `2.0`))
+
+		args := new(lb.InstallChaincodeArgs)
+		require.NoError(t, proto.Unmarshal(p.installed[`/_lifecycle/`+lifecycle.InstallChaincodeFuncName], args))
+		pkg, err := ccpackage.Parse(args.ChaincodeInstallPackage)
+		require.NoError(t, err)
+		require.Equal(t, ccpackage.Metadata{Path: `path/mycc`, Type: ccpackage.TypeGolang, Label: `mycc_2.0`}, pkg.Metadata)
+	})
+
+	t.Run(`not deployed`, func(t *testing.T) {
+		p := &installPeer{installed: make(map[string][]byte)}
+		core := chaincode.NewCore(`org1msp`, `mycc`, `ch1`, &sccPool{peers: []api.Peer{p}}, nil, nil, signer, specFetcher{}, false)
+		require.Error(t, core.Install(context.Background(), `2.0`))
+		require.Empty(t, p.installed)
+	})
+
+	t.Run(`not installed`, func(t *testing.T) {
+		p := &installPeer{installed: make(map[string][]byte)}
+		pool := &sccPool{peers: []api.Peer{p}, results: map[string]sccResult{
+			`ch1/lscc/` + lsccPkg.GETCCDATA:  {msg: &ccprovider.ChaincodeData{Name: `mycc`, Version: `1.0`}},
+			`ch1/lscc/` + lsccPkg.GETDEPSPEC: {err: lsccNotInstalled},
+		}}
+		core := chaincode.NewCore(`org1msp`, `mycc`, `ch1`, pool, nil, nil, signer, specFetcher{}, false)
+		err := core.Install(context.Background(), `2.0`)
+		require.Error(t, err)
+		require.Equal(t, api.ErrChaincodeNotInstalled, errors.Cause(err))
+		require.Empty(t, p.installed)
+	})
+
+	t.Run(`endorse error`, func(t *testing.T) {
+		p := &installPeer{installed: make(map[string][]byte), err: errors.New(`install failed`)}
+		pool := &sccPool{peers: []api.Peer{p}, results: map[string]sccResult{
+			`ch1/lscc/` + lsccPkg.GETCCDATA: {msg: &ccprovider.ChaincodeData{Name: `mycc`, Version: `1.0`}},
+			`ch1/lscc/` + lsccPkg.GETDEPSPEC: {msg: &peer.ChaincodeDeploymentSpec{ChaincodeSpec: &peer.ChaincodeSpec{
+				ChaincodeId: &peer.ChaincodeID{Name: `mycc`, Version: `1.0`, Path: `path/mycc`}}}},
+		}}
+		core := chaincode.NewCore(`org1msp`, `mycc`, `ch1`, pool, nil, nil, signer, specFetcher{}, false)
+		err := core.Install(context.Background(), `2.0`)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `peer0:7051: install failed`)
+	})
+}
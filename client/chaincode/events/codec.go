@@ -0,0 +1,36 @@
+package events
+
+import (
+	"encoding/json"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// Codec decodes chaincode event payload into value
+type Codec interface {
+	Unmarshal(data []byte, v interface{}) error
+}
+
+var (
+	// JSON decodes payload with encoding/json
+	JSON Codec = jsonCodec{}
+	// Proto decodes payload as protobuf message, value must implement proto.Message
+	Proto Codec = protoCodec{}
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+type protoCodec struct{}
+
+func (protoCodec) Unmarshal(data []byte, v interface{}) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return errors.Errorf("%T is not proto message", v)
+	}
+	return proto.Unmarshal(data, msg)
+}
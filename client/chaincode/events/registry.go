@@ -0,0 +1,98 @@
+package events
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrEventNotRegistered = errors.New(`event is not registered`)
+)
+
+// ErrDecode is returned when payload of chaincode event can't be decoded
+type ErrDecode struct {
+	Event *peer.ChaincodeEvent
+	Err   error
+}
+
+func (e *ErrDecode) Error() string {
+	return fmt.Sprintf("failed to decode event %s of tx %s: %s", e.Event.EventName, e.Event.TxId, e.Err)
+}
+
+// Registry maps chaincode event names to Go types and codecs
+type Registry struct {
+	mx    sync.RWMutex
+	types map[string]registered
+}
+
+type registered struct {
+	typ   reflect.Type
+	codec Codec
+}
+
+func NewRegistry() *Registry {
+	return &Registry{types: make(map[string]registered)}
+}
+
+// Register maps event name to type of prototype, e.g. Register(`transfer`, Transfer{}, JSON).
+// Decoded values are pointers to new values of prototype type
+func (r *Registry) Register(eventName string, prototype interface{}, codec Codec) error {
+	typ := reflect.TypeOf(prototype)
+	if typ == nil {
+		return errors.New(`prototype is nil`)
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if codec == Proto {
+		if _, ok := reflect.New(typ).Interface().(proto.Message); !ok {
+			return errors.Errorf("%s is not proto message", typ)
+		}
+	}
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.types[eventName] = registered{typ: typ, codec: codec}
+	return nil
+}
+
+// MustRegister is Register which panics on error
+func (r *Registry) MustRegister(eventName string, prototype interface{}, codec Codec) *Registry {
+	if err := r.Register(eventName, prototype, codec); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Decode decodes event payload into new value of registered type
+func (r *Registry) Decode(event *peer.ChaincodeEvent) (interface{}, error) {
+	r.mx.RLock()
+	reg, ok := r.types[event.EventName]
+	r.mx.RUnlock()
+
+	if !ok {
+		return nil, &ErrDecode{Event: event, Err: ErrEventNotRegistered}
+	}
+
+	value := reflect.New(reg.typ).Interface()
+	if err := reg.codec.Unmarshal(event.Payload, value); err != nil {
+		return nil, &ErrDecode{Event: event, Err: err}
+	}
+	return value, nil
+}
+
+// Registered returns true if event name is registered
+func (r *Registry) Registered(eventName string) bool {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	_, ok := r.types[eventName]
+	return ok
+}
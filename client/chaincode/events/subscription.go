@@ -0,0 +1,218 @@
+package events
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+)
+
+const (
+	// DefaultErrorsBuffer is count of errors queued for consumer
+	DefaultErrorsBuffer = 16
+)
+
+// Event is chaincode event with decoded payload
+type Event struct {
+	TxID        string
+	ChaincodeID string
+	EventName   string
+	// Value is pointer to value of type registered for event name
+	Value interface{}
+	Raw   *peer.ChaincodeEvent
+}
+
+// Subscription decodes events of chaincode event subscription using registry.
+// Events which can't be decoded are sent to error channel as *ErrDecode and subscription continues,
+// error of underlying subscription is sent to error channel and finishes subscription.
+// Error channel is buffered and by default never blocks events: decode errors are dropped when buffer is full,
+// so consumer may read only events. Count of dropped errors is returned by Dropped,
+// WithBlockingErrors makes subscription wait until consumer reads error instead
+type Subscription struct {
+	// dropped is accessed atomically, first in struct for 64-bit alignment
+	dropped uint64
+
+	sub            api.EventCCSubscription
+	registry       *Registry
+	strict         bool
+	errorsBuffer   int
+	blockingErrors bool
+
+	events chan *Event
+	errors chan error
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+type SubscriptionOpt func(s *Subscription)
+
+// WithStrict sends events without registered type to error channel, by default they are skipped
+func WithStrict() SubscriptionOpt {
+	return func(s *Subscription) {
+		s.strict = true
+	}
+}
+
+// WithErrorsBuffer sets count of errors queued for consumer, buffer holds at least one error
+func WithErrorsBuffer(size int) SubscriptionOpt {
+	return func(s *Subscription) {
+		s.errorsBuffer = size
+	}
+}
+
+// WithBlockingErrors makes subscription wait until consumer reads error when errors buffer is full,
+// so errors are never dropped, but consumer must read both events and errors
+func WithBlockingErrors() SubscriptionOpt {
+	return func(s *Subscription) {
+		s.blockingErrors = true
+	}
+}
+
+// NewSubscription returns typed subscription wrapping chaincode event subscription
+func NewSubscription(sub api.EventCCSubscription, registry *Registry, opts ...SubscriptionOpt) *Subscription {
+	s := &Subscription{
+		sub:          sub,
+		registry:     registry,
+		errorsBuffer: DefaultErrorsBuffer,
+		events:       make(chan *Event),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.errorsBuffer < 1 {
+		s.errorsBuffer = 1
+	}
+	s.errors = make(chan error, s.errorsBuffer)
+
+	go s.handle()
+	return s
+}
+
+// Events returns decoded events, channel is closed when subscription is finished
+func (s *Subscription) Events() <-chan *Event {
+	return s.events
+}
+
+// Errors returns decode errors and error of underlying subscription, channel is closed when subscription is finished
+func (s *Subscription) Errors() <-chan error {
+	return s.errors
+}
+
+// Dropped returns count of errors dropped because errors buffer was full
+func (s *Subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Close closes underlying subscription
+func (s *Subscription) Close() error {
+	var err error
+	s.once.Do(func() {
+		close(s.stop)
+		err = s.sub.Close()
+		<-s.done
+	})
+	return err
+}
+
+func (s *Subscription) handle() {
+	defer close(s.done)
+	defer close(s.errors)
+	defer close(s.events)
+
+	for {
+		select {
+		case ev, ok := <-s.sub.Events():
+			if !ok {
+				// underlying subscription may send error which finished it right before closing events
+				select {
+				case err, ok := <-s.sub.Errors():
+					if ok && err != nil {
+						s.reportFinal(err)
+					}
+				default:
+				}
+				return
+			}
+			if !s.strict && !s.registry.Registered(ev.EventName) {
+				continue
+			}
+
+			value, err := s.registry.Decode(ev)
+			if err != nil {
+				if !s.report(err) {
+					return
+				}
+				continue
+			}
+
+			event := &Event{
+				TxID:        ev.TxId,
+				ChaincodeID: ev.ChaincodeId,
+				EventName:   ev.EventName,
+				Value:       value,
+				Raw:         ev,
+			}
+			select {
+			case s.events <- event:
+			case <-s.stop:
+				return
+			}
+
+		case err, ok := <-s.sub.Errors():
+			if ok && err != nil {
+				s.reportFinal(err)
+			}
+			return
+
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// report sends error if buffer has room, otherwise error is dropped or sending waits for consumer
+// in blocking mode. Returns false if subscription is closed while waiting
+func (s *Subscription) report(err error) bool {
+	if s.blockingErrors {
+		select {
+		case s.errors <- err:
+			return true
+		case <-s.stop:
+			return false
+		}
+	}
+
+	select {
+	case s.errors <- err:
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+	}
+	return true
+}
+
+// reportFinal sends error which finishes subscription, oldest queued error is dropped if buffer is full
+// and errors are not blocking
+func (s *Subscription) reportFinal(err error) {
+	if s.blockingErrors {
+		s.report(err)
+		return
+	}
+
+	for {
+		select {
+		case s.errors <- err:
+			return
+		default:
+		}
+		select {
+		case <-s.errors:
+			atomic.AddUint64(&s.dropped, 1)
+		default:
+		}
+	}
+}
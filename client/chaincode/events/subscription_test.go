@@ -0,0 +1,177 @@
+package events_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bogatyr285/hlf-sdk-go/client/chaincode/events"
+)
+
+type transfer struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount int    `json:"amount"`
+}
+
+type mockSubscription struct {
+	events chan *peer.ChaincodeEvent
+	errors chan error
+}
+
+func (m *mockSubscription) Events() chan *peer.ChaincodeEvent {
+	return m.events
+}
+
+func (m *mockSubscription) Errors() chan error {
+	return m.errors
+}
+
+func (m *mockSubscription) Close() error {
+	return nil
+}
+
+func TestSubscription(t *testing.T) {
+	registry := events.NewRegistry().
+		MustRegister(`transfer`, transfer{}, events.JSON).
+		MustRegister(`proposal`, &peer.ChaincodeProposalPayload{}, events.Proto)
+
+	payload, err := json.Marshal(transfer{From: `a`, To: `b`, Amount: 10})
+	require.NoError(t, err)
+	pbPayload, err := proto.Marshal(&peer.ChaincodeProposalPayload{Input: []byte(`input`)})
+	require.NoError(t, err)
+
+	mock := &mockSubscription{events: make(chan *peer.ChaincodeEvent, 4), errors: make(chan error, 1)}
+	mock.events <- &peer.ChaincodeEvent{TxId: `tx-1`, EventName: `transfer`, Payload: []byte(`{broken`)}
+	mock.events <- &peer.ChaincodeEvent{TxId: `tx-2`, EventName: `unknown`, Payload: []byte(`data`)}
+	mock.events <- &peer.ChaincodeEvent{TxId: `tx-3`, EventName: `transfer`, Payload: payload}
+	mock.events <- &peer.ChaincodeEvent{TxId: `tx-4`, EventName: `proposal`, Payload: pbPayload}
+	close(mock.events)
+
+	sub := events.NewSubscription(mock, registry)
+	defer sub.Close()
+
+	timeout := time.After(5 * time.Second)
+
+	// undecodable payload is reported without finishing subscription, unknown event is skipped
+	select {
+	case err := <-sub.Errors():
+		decodeErr, ok := err.(*events.ErrDecode)
+		require.True(t, ok)
+		require.Equal(t, `tx-1`, decodeErr.Event.TxId)
+	case <-timeout:
+		t.Fatal(`decode error is not received`)
+	}
+
+	var received []*events.Event
+	for event := range sub.Events() {
+		received = append(received, event)
+	}
+	require.Len(t, received, 2)
+	require.Equal(t, &transfer{From: `a`, To: `b`, Amount: 10}, received[0].Value)
+	require.Equal(t, `input`, string(received[1].Value.(*peer.ChaincodeProposalPayload).Input))
+}
+
+func TestSubscription_EventsOnly(t *testing.T) {
+	registry := events.NewRegistry().MustRegister(`transfer`, transfer{}, events.JSON)
+
+	payload, err := json.Marshal(transfer{From: `a`, To: `b`, Amount: 10})
+	require.NoError(t, err)
+
+	mock := &mockSubscription{events: make(chan *peer.ChaincodeEvent), errors: make(chan error, 1)}
+	go func() {
+		// decode errors overflow errors buffer
+		for i := 0; i < 10; i++ {
+			mock.events <- &peer.ChaincodeEvent{TxId: fmt.Sprintf("broken-%d", i), EventName: `transfer`, Payload: []byte(`{broken`)}
+			mock.events <- &peer.ChaincodeEvent{TxId: fmt.Sprintf("tx-%d", i), EventName: `transfer`, Payload: payload}
+		}
+		mock.errors <- errors.New(`stream failed`)
+	}()
+
+	sub := events.NewSubscription(mock, registry, events.WithErrorsBuffer(2))
+	defer sub.Close()
+
+	// consumer reads only events, subscription is not blocked by unread errors
+	var received []string
+	timeout := time.After(5 * time.Second)
+	for len(received) < 10 {
+		select {
+		case event, ok := <-sub.Events():
+			require.True(t, ok)
+			received = append(received, event.TxID)
+		case <-timeout:
+			t.Fatal(`events are not received`)
+		}
+	}
+	require.Equal(t, `tx-9`, received[9])
+	_, ok := <-sub.Events()
+	require.False(t, ok)
+
+	// error of underlying subscription is kept, some decode errors are dropped
+	var errs []error
+	for err := range sub.Errors() {
+		errs = append(errs, err)
+	}
+	require.Len(t, errs, 2)
+	require.EqualError(t, errs[1], `stream failed`)
+	require.Equal(t, uint64(9), sub.Dropped())
+}
+
+func TestSubscription_ErrorBeforeClose(t *testing.T) {
+	registry := events.NewRegistry()
+
+	// events and errors are both ready when handled, error must not be lost whichever is selected first
+	for i := 0; i < 50; i++ {
+		mock := &mockSubscription{events: make(chan *peer.ChaincodeEvent), errors: make(chan error, 1)}
+		mock.errors <- errors.New(`block gap`)
+		close(mock.events)
+
+		sub := events.NewSubscription(mock, registry)
+		_, ok := <-sub.Events()
+		require.False(t, ok)
+		require.EqualError(t, <-sub.Errors(), `block gap`)
+		require.NoError(t, sub.Close())
+	}
+}
+
+func TestSubscription_BlockingErrors(t *testing.T) {
+	registry := events.NewRegistry().MustRegister(`transfer`, transfer{}, events.JSON)
+
+	mock := &mockSubscription{events: make(chan *peer.ChaincodeEvent, 4), errors: make(chan error, 1)}
+	for i := 0; i < 3; i++ {
+		mock.events <- &peer.ChaincodeEvent{TxId: fmt.Sprintf("broken-%d", i), EventName: `transfer`, Payload: []byte(`{broken`)}
+	}
+	mock.events <- &peer.ChaincodeEvent{TxId: `tx`, EventName: `transfer`, Payload: []byte(`{}`)}
+	close(mock.events)
+
+	sub := events.NewSubscription(mock, registry, events.WithErrorsBuffer(1), events.WithBlockingErrors())
+	defer sub.Close()
+
+	// every decode error is delivered although buffer holds only one
+	for i := 0; i < 3; i++ {
+		select {
+		case err := <-sub.Errors():
+			require.Equal(t, fmt.Sprintf("broken-%d", i), err.(*events.ErrDecode).Event.TxId)
+		case <-time.After(5 * time.Second):
+			t.Fatal(`decode error is not received`)
+		}
+	}
+	event, ok := <-sub.Events()
+	require.True(t, ok)
+	require.Equal(t, `tx`, event.TxID)
+	require.Equal(t, uint64(0), sub.Dropped())
+}
+
+func TestRegistry_Decode(t *testing.T) {
+	registry := events.NewRegistry()
+	require.Error(t, registry.Register(`transfer`, transfer{}, events.Proto))
+
+	_, err := registry.Decode(&peer.ChaincodeEvent{EventName: `transfer`})
+	require.Equal(t, events.ErrEventNotRegistered, err.(*events.ErrDecode).Err)
+}
@@ -6,14 +6,13 @@ import (
 	"encoding/json"
 	"fmt"
 
-	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/common"
 	fabricPeer "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/msp"
-	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 
 	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/client/chaincode/offline"
 	"github.com/bogatyr285/hlf-sdk-go/client/chaincode/txwaiter"
 	"github.com/bogatyr285/hlf-sdk-go/peer"
 )
@@ -110,14 +109,22 @@ func (b *invokeBuilder) Transient(args api.TransArgs) api.ChaincodeInvokeBuilder
 }
 
 func (b *invokeBuilder) getTransaction(proposal *fabricPeer.SignedProposal, peerResponses []*fabricPeer.ProposalResponse) (*common.Envelope, error) {
+	prop, err := offline.ParseProposal(proposal.ProposalBytes)
+	if err != nil {
+		return nil, err
+	}
 
-	prop := new(fabricPeer.Proposal)
+	tx, err := offline.NewTransaction(prop, peerResponses...)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := proto.Unmarshal(proposal.ProposalBytes, prop); err != nil {
-		return nil, errors.Wrap(err, `failed to unmarshal `)
+	signature, err := b.identity.Sign(tx.Bytes())
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to sign transaction`)
 	}
 
-	return protoutil.CreateSignedTx(prop, b.identity, peerResponses...)
+	return tx.Attach(signature).Envelope(), nil
 }
 
 func (b *invokeBuilder) ArgJSON(in ...interface{}) api.ChaincodeInvokeBuilder {
@@ -171,11 +178,22 @@ func (b *invokeBuilder) Do(ctx context.Context, options ...api.DoOption) (*fabri
 		return nil, ``, errors.Wrap(err, `failed to get signed proposal`)
 	}
 
-	peerResponses, err := b.processor.Send(ctx, proposal, endorsingMspIDs, b.peerPool)
+	peerResponses, err := b.processor.Send(ctx, proposal, endorsingMspIDs, b.peerPool,
+		api.WithEndorsementLayouts(ccd.EndorsementLayouts()...))
 	if err != nil {
 		return nil, tx, errors.Wrap(err, `failed to collect peer responses`)
 	}
 
+	if err = peer.CheckEndorsements(peerResponses); err != nil {
+		return nil, tx, err
+	}
+
+	if doOpts.EndorsementVerifier != nil {
+		if err = doOpts.EndorsementVerifier.Verify(ctx, b.ccCore.channelName, peerResponses); err != nil {
+			return nil, tx, errors.Wrap(err, `failed to verify endorsements`)
+		}
+	}
+
 	envelope, err := b.getTransaction(proposal, peerResponses)
 	if err != nil {
 		return nil, tx, errors.Wrap(err, `failed to get envelope`)
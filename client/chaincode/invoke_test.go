@@ -105,11 +105,35 @@ func (m *mockDeliverClient) SubscribeBlock(ctx context.Context, channelName stri
 	return nil, nil
 }
 
+func (m *mockDeliverClient) SubscribeCCCheckpointed(ctx context.Context, channelName string, ccName string, store api.CheckpointStore, opts ...api.CheckpointOpt) (api.CheckpointSubscription, error) {
+	return nil, nil
+}
+
+func (m *mockDeliverClient) SubscribeFilteredBlock(ctx context.Context, channelName string, seekOpt ...api.EventCCSeekOption) (api.FilteredBlockSubscription, error) {
+	return nil, nil
+}
+
+func (m *mockDeliverClient) SubscribeFilteredCC(ctx context.Context, channelName string, ccName string, seekOpt ...api.EventCCSeekOption) (api.EventCCSubscription, error) {
+	return nil, nil
+}
+
+func (m *mockDeliverClient) SubscribeFilteredTx(ctx context.Context, channelName string, tx api.ChaincodeTx, seekOpt ...api.EventCCSeekOption) (api.TxSubscription, error) {
+	return nil, nil
+}
+
+func (m *mockDeliverClient) SubscribeBlockWithPrivateData(ctx context.Context, channelName string, seekOpt ...api.EventCCSeekOption) (api.BlockWithPrivateDataSubscription, error) {
+	return nil, nil
+}
+
 // simple mock peer
 type mockPeer struct {
 	deliver      *mockDeliverClient
 	endorser     msp.SigningIdentity
 	checkEndorse map[string]int
+	// channel name to endorsement error
+	endorseErr map[string]error
+	// channel name to chaincode response payload
+	respPayload map[string]string
 }
 
 // Endorse mock echo answer from peer
@@ -130,10 +154,17 @@ func (p *mockPeer) Endorse(ctx context.Context, proposal *peer.SignedProposal, o
 
 	p.checkEndorse[chheader.ChannelId+`/`+chheader.TxId]++
 
+	if err = p.endorseErr[chheader.ChannelId]; err != nil {
+		return nil, err
+	}
+
 	peerResp := &peer.Response{
 		Status:  200,
 		Payload: []byte(`{"message": "OK"}`),
 	}
+	if payload, ok := p.respPayload[chheader.ChannelId]; ok {
+		peerResp.Payload = []byte(payload)
+	}
 
 	result := []byte(``)
 	event := []byte(nil)
@@ -247,12 +278,18 @@ func TestInvokeBuilder_Do(t *testing.T) {
 			deliver:      newMockDeliverClient(channelConfigPeer1And2),
 			endorser:     org2mspID.GetSigningIdentity(cryptoSuite),
 			checkEndorse: make(map[string]int),
+			endorseErr: map[string]error{
+				"fallback-layout-network": errors.New(`BOOM`),
+			},
 		}
 
 		peerOrg3 = &mockPeer{
 			deliver:      newMockDeliverClient(channelConfigPeer3),
 			endorser:     org3mspID.GetSigningIdentity(cryptoSuite),
 			checkEndorse: make(map[string]int),
+			respPayload: map[string]string{
+				"mismatch-network": `{"message": "NOT OK"}`,
+			},
 		}
 
 		peerResolver = map[string]*mockPeer{
@@ -298,6 +335,16 @@ func TestInvokeBuilder_Do(t *testing.T) {
 		return nil
 	}
 
+	var checkNotEndorsed = func(channelName, txid string, orgs ...string) error {
+		key := channelName + `/` + txid
+		for _, org := range orgs {
+			if v := peerResolver[org].checkEndorse[key]; v != 0 {
+				return fmt.Errorf("unexpected endorse was called on peer %s/%s/%d", org, key, v)
+			}
+		}
+		return nil
+	}
+
 	var checkTxWaiterCount = func(channelName, txid string, orgs ...string) error {
 		var strErrs []string
 		key := channelName + `/` + txid
@@ -325,6 +372,7 @@ func TestInvokeBuilder_Do(t *testing.T) {
 		channel                string
 		chaincode              string
 		checkEndorseCalled     []string
+		checkEndorseNotCalled  []string
 		checkDeliverByTxCalled []string
 		expErr                 error
 	}{
@@ -397,6 +445,34 @@ func TestInvokeBuilder_Do(t *testing.T) {
 			checkDeliverByTxCalled: []string{},
 			expErr:                 errors.New("next errors occurred:\nfailed to subscribe on tx event: BOOM\nfailed to subscribe on tx event: BOOM\nfailed to subscribe on tx event: BOOM\n"),
 		},
+		{
+			name:                   `success with minimal endorsement layout`,
+			channel:                `minimal-layout-network`,
+			chaincode:              `my-chaincode`,
+			opts:                   []api.DoOption{chaincode.WithTxWaiter(txwaiter.Self)},
+			checkEndorseCalled:     []string{`org1msp`},
+			checkEndorseNotCalled:  []string{`org2msp`, `org3msp`},
+			checkDeliverByTxCalled: []string{`org1msp`},
+		},
+		{
+			name:                   `success with fallback endorsement layout`,
+			channel:                `fallback-layout-network`,
+			chaincode:              `my-chaincode`,
+			opts:                   []api.DoOption{chaincode.WithTxWaiter(txwaiter.Self)},
+			checkEndorseCalled:     []string{`org1msp`, `org2msp`, `org3msp`},
+			checkDeliverByTxCalled: []string{`org1msp`},
+		},
+		{
+			name:                   `fail on endorsements mismatch`,
+			channel:                `mismatch-network`,
+			chaincode:              `my-chaincode`,
+			opts:                   []api.DoOption{chaincode.WithTxWaiter(txwaiter.Self)},
+			checkEndorseCalled:     []string{`org1msp`, `org2msp`, `org3msp`},
+			checkDeliverByTxCalled: []string{},
+			expErr: errors.New("endorsements mismatch, expected payload of peer0.org1.example.com (org1msp):\n" +
+				"peer0.org1.example.com (org3msp) diverges:\n" +
+				"\tresponse payload: " + `"{\"message\": \"OK\"}" != "{\"message\": \"NOT OK\"}"` + "\n"),
+		},
 	} {
 		t.Run(tc.name, func(tt *testing.T) {
 			_, txid, err := invoker.Invoke(
@@ -418,6 +494,11 @@ func TestInvokeBuilder_Do(t *testing.T) {
 					t.Errorf("checkEndorseCalled: Unexpected error: %s", err)
 				}
 			}
+			if len(tc.checkEndorseNotCalled) != 0 {
+				if err = checkNotEndorsed(tc.channel, string(txid), tc.checkEndorseNotCalled...); err != nil {
+					t.Errorf("checkEndorseNotCalled: Unexpected error: %s", err)
+				}
+			}
 			if len(tc.checkDeliverByTxCalled) != 0 {
 				if err = checkTxWaiterCount(tc.channel, string(txid), tc.checkDeliverByTxCalled...); err != nil {
 					t.Errorf("checkDeliverByTxCalled: Unexpected error: %s", err)
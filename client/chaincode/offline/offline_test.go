@@ -0,0 +1,139 @@
+package offline_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/api/config"
+	"github.com/bogatyr285/hlf-sdk-go/client/chaincode/offline"
+	"github.com/bogatyr285/hlf-sdk-go/crypto"
+	"github.com/bogatyr285/hlf-sdk-go/crypto/ecdsa"
+	"github.com/bogatyr285/hlf-sdk-go/identity"
+	"github.com/bogatyr285/hlf-sdk-go/logger"
+	"github.com/bogatyr285/hlf-sdk-go/peer/pool"
+)
+
+type mockPeer struct {
+	api.Peer
+	endorser msp.SigningIdentity
+}
+
+func (p *mockPeer) Endorse(_ context.Context, proposal *peer.SignedProposal, _ ...api.PeerEndorseOpt) (*peer.ProposalResponse, error) {
+	prop := new(peer.Proposal)
+	if err := proto.Unmarshal(proposal.ProposalBytes, prop); err != nil {
+		return nil, err
+	}
+
+	return protoutil.CreateProposalResponse(prop.Header, prop.Payload,
+		&peer.Response{Status: 200, Payload: []byte(`OK`)}, nil, nil,
+		&peer.ChaincodeID{Name: `mycc`}, p.endorser)
+}
+
+func (p *mockPeer) Uri() string {
+	return `localhost:7051`
+}
+
+func (p *mockPeer) Conn() *grpc.ClientConn {
+	return nil
+}
+
+type mockOrderer struct {
+	api.Orderer
+	envelope *common.Envelope
+}
+
+func (o *mockOrderer) Broadcast(_ context.Context, envelope *common.Envelope) (*orderer.BroadcastResponse, error) {
+	o.envelope = envelope
+	return &orderer.BroadcastResponse{Status: common.Status_SUCCESS}, nil
+}
+
+func alive(_ context.Context, _ api.Peer, alive chan bool) {
+	alive <- true
+}
+
+func TestOfflineInvoke(t *testing.T) {
+	cs, err := crypto.GetSuite(ecdsa.Module, ecdsa.DefaultOpts)
+	require.NoError(t, err)
+
+	mspID, err := identity.NewMSPIdentityFromPath(`org1msp`, `../testdata/msp`)
+	require.NoError(t, err)
+	// signer represents external signing service
+	signer := mspID.GetSigningIdentity(cs)
+
+	creator, err := signer.Serialize()
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir(``, `offline`)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// build unsigned proposal and pass it to signer via file
+	prop, err := offline.NewProposal(creator, `channel`, `mycc`, `invoke`, [][]byte{[]byte(`arg`)}, nil)
+	require.NoError(t, err)
+	require.NoError(t, prop.WriteFile(filepath.Join(dir, `proposal`)))
+
+	propBytes, err := ioutil.ReadFile(filepath.Join(dir, `proposal`))
+	require.NoError(t, err)
+	propSig, err := signer.Sign(propBytes)
+	require.NoError(t, err)
+
+	parsedProp, err := offline.ParseProposal(propBytes)
+	require.NoError(t, err)
+	require.Equal(t, prop.TxID(), parsedProp.TxID())
+
+	signedProp := parsedProp.Attach(propSig)
+	signedPropBytes, err := signedProp.Bytes()
+	require.NoError(t, err)
+	signedProp, err = offline.ParseSignedProposal(signedPropBytes)
+	require.NoError(t, err)
+
+	// endorse
+	peerPool := pool.New(context.Background(), logger.DefaultLogger, config.PoolConfig{})
+	require.NoError(t, peerPool.Add(`org1msp`, &mockPeer{endorser: signer}, alive))
+
+	responses, err := signedProp.Endorse(context.Background(), []string{`org1msp`}, peerPool)
+	require.NoError(t, err)
+	require.Len(t, responses, 1)
+
+	// build unsigned transaction and sign it
+	tx, err := offline.NewTransaction(signedProp.Proposal(), responses...)
+	require.NoError(t, err)
+	require.Equal(t, prop.TxID(), tx.TxID())
+
+	tx, err = offline.ParseTransaction(tx.Bytes())
+	require.NoError(t, err)
+	txSig, err := signer.Sign(tx.Bytes())
+	require.NoError(t, err)
+
+	signedTx := tx.Attach(txSig)
+	require.NoError(t, signedTx.WriteFile(filepath.Join(dir, `tx`)))
+
+	txBytes, err := ioutil.ReadFile(filepath.Join(dir, `tx`))
+	require.NoError(t, err)
+	signedTx, err = offline.ParseSignedTransaction(txBytes)
+	require.NoError(t, err)
+	require.Equal(t, prop.TxID(), signedTx.TxID())
+
+	// broadcast
+	ord := &mockOrderer{}
+	_, err = signedTx.Broadcast(context.Background(), ord)
+	require.NoError(t, err)
+	require.NoError(t, signer.Verify(ord.envelope.Payload, ord.envelope.Signature))
+
+	txID, err := protoutil.GetOrComputeTxIDFromEnvelope(txBytes)
+	require.NoError(t, err)
+	require.Equal(t, string(prop.TxID()), txID)
+}
@@ -0,0 +1,155 @@
+// Package offline splits chaincode invoke into steps allowing to sign proposal and transaction outside of SDK,
+// i.e. by external signing service or on air-gapped host. Every intermediate artifact is serializable.
+package offline
+
+import (
+	"context"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	peerSDK "github.com/bogatyr285/hlf-sdk-go/peer"
+)
+
+// Proposal is unsigned chaincode invocation proposal
+type Proposal struct {
+	proposal *peer.Proposal
+	raw      []byte
+	txID     api.ChaincodeTx
+}
+
+// NewProposal creates unsigned proposal for serialized creator identity
+func NewProposal(creator []byte, channelName, ccName, fn string, args [][]byte, transArgs api.TransArgs) (*Proposal, error) {
+	prop, _, err := peerSDK.NewProposal(channelName, ccName, creator, fn, args, transArgs)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to create proposal`)
+	}
+
+	raw, err := proto.Marshal(prop)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to marshal proposal`)
+	}
+
+	return newProposal(raw)
+}
+
+// ParseProposal parses unsigned proposal from bytes
+func ParseProposal(raw []byte) (*Proposal, error) {
+	return newProposal(raw)
+}
+
+func newProposal(raw []byte) (*Proposal, error) {
+	prop := new(peer.Proposal)
+	if err := proto.Unmarshal(raw, prop); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal proposal`)
+	}
+
+	txID, err := proposalTxID(prop)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Proposal{proposal: prop, raw: raw, txID: txID}, nil
+}
+
+// Bytes returns proposal bytes which must be signed by creator
+func (p *Proposal) Bytes() []byte {
+	return p.raw
+}
+
+// TxID returns transaction id of proposal
+func (p *Proposal) TxID() api.ChaincodeTx {
+	return p.txID
+}
+
+// WriteFile writes proposal bytes to file
+func (p *Proposal) WriteFile(path string) error {
+	return writeFile(path, p.raw)
+}
+
+// Attach returns proposal signed with presented signature of proposal bytes
+func (p *Proposal) Attach(signature []byte) *SignedProposal {
+	return &SignedProposal{
+		signed:   &peer.SignedProposal{ProposalBytes: p.raw, Signature: signature},
+		proposal: p,
+	}
+}
+
+// SignedProposal is proposal with attached creator signature, ready for endorsement
+type SignedProposal struct {
+	signed   *peer.SignedProposal
+	proposal *Proposal
+}
+
+// ParseSignedProposal parses signed proposal from bytes
+func ParseSignedProposal(raw []byte) (*SignedProposal, error) {
+	signed := new(peer.SignedProposal)
+	if err := proto.Unmarshal(raw, signed); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal signed proposal`)
+	}
+
+	prop, err := newProposal(signed.ProposalBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignedProposal{signed: signed, proposal: prop}, nil
+}
+
+// Bytes returns marshalled signed proposal
+func (p *SignedProposal) Bytes() ([]byte, error) {
+	return proto.Marshal(p.signed)
+}
+
+// WriteFile writes marshalled signed proposal to file
+func (p *SignedProposal) WriteFile(path string) error {
+	raw, err := p.Bytes()
+	if err != nil {
+		return errors.Wrap(err, `failed to marshal signed proposal`)
+	}
+	return writeFile(path, raw)
+}
+
+// Proposal returns unsigned proposal
+func (p *SignedProposal) Proposal() *Proposal {
+	return p.proposal
+}
+
+// Endorse sends signed proposal to endorsing peers and checks that endorsements are consistent
+func (p *SignedProposal) Endorse(ctx context.Context, endorsingMspIDs []string, pool api.PeerPool, opts ...api.PeerSendOpt) ([]*peer.ProposalResponse, error) {
+	responses, err := peerSDK.NewProcessor(``).Send(ctx, p.signed, endorsingMspIDs, pool, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to collect peer responses`)
+	}
+
+	if err = peerSDK.CheckEndorsements(responses); err != nil {
+		return nil, err
+	}
+
+	return responses, nil
+}
+
+func proposalTxID(prop *peer.Proposal) (api.ChaincodeTx, error) {
+	header, err := protoutil.UnmarshalHeader(prop.Header)
+	if err != nil {
+		return ``, errors.Wrap(err, `failed to unmarshal proposal header`)
+	}
+
+	chHeader, err := protoutil.UnmarshalChannelHeader(header.ChannelHeader)
+	if err != nil {
+		return ``, errors.Wrap(err, `failed to unmarshal channel header`)
+	}
+
+	return api.ChaincodeTx(chHeader.TxId), nil
+}
+
+func writeFile(path string, raw []byte) error {
+	if err := ioutil.WriteFile(path, raw, 0644); err != nil {
+		return errors.Wrap(err, `failed to write file`)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package offline
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+)
+
+// Transaction is unsigned transaction payload built from proposal and endorsements
+type Transaction struct {
+	raw  []byte
+	txID api.ChaincodeTx
+}
+
+// detachedSigner allows to assemble transaction envelope without signature
+type detachedSigner struct {
+	creator []byte
+}
+
+func (s detachedSigner) Sign([]byte) ([]byte, error) {
+	return nil, nil
+}
+
+func (s detachedSigner) Serialize() ([]byte, error) {
+	return s.creator, nil
+}
+
+// NewTransaction creates unsigned transaction payload from proposal and endorsements of peers
+func NewTransaction(proposal *Proposal, responses ...*peer.ProposalResponse) (*Transaction, error) {
+	header, err := protoutil.UnmarshalHeader(proposal.proposal.Header)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal proposal header`)
+	}
+
+	sigHeader, err := protoutil.UnmarshalSignatureHeader(header.SignatureHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal signature header`)
+	}
+
+	env, err := protoutil.CreateSignedTx(proposal.proposal, detachedSigner{creator: sigHeader.Creator}, responses...)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to create transaction`)
+	}
+
+	return &Transaction{raw: env.Payload, txID: proposal.txID}, nil
+}
+
+// ParseTransaction parses unsigned transaction payload from bytes
+func ParseTransaction(raw []byte) (*Transaction, error) {
+	txID, err := payloadTxID(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{raw: raw, txID: txID}, nil
+}
+
+// Bytes returns transaction payload bytes which must be signed by creator
+func (t *Transaction) Bytes() []byte {
+	return t.raw
+}
+
+// TxID returns transaction id
+func (t *Transaction) TxID() api.ChaincodeTx {
+	return t.txID
+}
+
+// WriteFile writes transaction payload bytes to file
+func (t *Transaction) WriteFile(path string) error {
+	return writeFile(path, t.raw)
+}
+
+// Attach returns transaction envelope signed with presented signature of payload bytes
+func (t *Transaction) Attach(signature []byte) *SignedTransaction {
+	return &SignedTransaction{
+		envelope: &common.Envelope{Payload: t.raw, Signature: signature},
+		txID:     t.txID,
+	}
+}
+
+// SignedTransaction is transaction envelope with attached creator signature, ready for broadcast
+type SignedTransaction struct {
+	envelope *common.Envelope
+	txID     api.ChaincodeTx
+}
+
+// ParseSignedTransaction parses signed transaction envelope from bytes
+func ParseSignedTransaction(raw []byte) (*SignedTransaction, error) {
+	env := new(common.Envelope)
+	if err := proto.Unmarshal(raw, env); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal envelope`)
+	}
+
+	txID, err := payloadTxID(env.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignedTransaction{envelope: env, txID: txID}, nil
+}
+
+// Bytes returns marshalled transaction envelope
+func (t *SignedTransaction) Bytes() ([]byte, error) {
+	return proto.Marshal(t.envelope)
+}
+
+// WriteFile writes marshalled transaction envelope to file
+func (t *SignedTransaction) WriteFile(path string) error {
+	raw, err := t.Bytes()
+	if err != nil {
+		return errors.Wrap(err, `failed to marshal envelope`)
+	}
+	return writeFile(path, raw)
+}
+
+// TxID returns transaction id
+func (t *SignedTransaction) TxID() api.ChaincodeTx {
+	return t.txID
+}
+
+// Envelope returns signed transaction envelope
+func (t *SignedTransaction) Envelope() *common.Envelope {
+	return t.envelope
+}
+
+// Broadcast sends signed transaction to orderer
+func (t *SignedTransaction) Broadcast(ctx context.Context, ord api.Orderer) (*orderer.BroadcastResponse, error) {
+	resp, err := ord.Broadcast(ctx, t.envelope)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to get orderer response`)
+	}
+	return resp, nil
+}
+
+func payloadTxID(raw []byte) (api.ChaincodeTx, error) {
+	payload, err := protoutil.UnmarshalPayload(raw)
+	if err != nil {
+		return ``, errors.Wrap(err, `failed to unmarshal payload`)
+	}
+
+	if payload.Header == nil {
+		return ``, errors.New(`empty payload header`)
+	}
+
+	chHeader, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return ``, errors.Wrap(err, `failed to unmarshal channel header`)
+	}
+
+	return api.ChaincodeTx(chHeader.TxId), nil
+}
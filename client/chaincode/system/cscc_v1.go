@@ -21,6 +21,10 @@ const (
 	GetConfigTree  string = `GetConfigTree`
 )
 
+var (
+	ErrJoinBySnapshotNotSupported = errors.New(`join by snapshot is not supported by fabric v1`)
+)
+
 type csccV1 struct {
 	peerPool  api.PeerPool
 	identity  msp.SigningIdentity
@@ -37,6 +41,10 @@ func (c *csccV1) JoinChain(ctx context.Context, channelName string, genesisBlock
 	return err
 }
 
+func (c *csccV1) JoinChainBySnapshot(context.Context, string) error {
+	return ErrJoinBySnapshotNotSupported
+}
+
 func (c *csccV1) GetConfigBlock(ctx context.Context, channelName string) (*common.Block, error) {
 	resp, err := c.endorse(ctx, GetConfigBlock, channelName)
 	if err != nil {
@@ -17,9 +17,15 @@ type csccV2 struct {
 
 // These are function names from Invoke first parameter
 const (
-	GetChannelConfig string = "GetChannelConfig"
+	GetChannelConfig    string = "GetChannelConfig"
+	JoinChainBySnapshot string = "JoinChainBySnapshot"
 )
 
+func (c csccV2) JoinChainBySnapshot(ctx context.Context, snapshotPath string) error {
+	_, err := c.endorse(ctx, JoinChainBySnapshot, snapshotPath)
+	return err
+}
+
 func (c csccV2) GetChannelConfig(ctx context.Context, channelName string) (*common.Config, error) {
 	resp, err := c.endorse(ctx, GetChannelConfig, channelName)
 	if err != nil {
@@ -2,48 +2,224 @@ package system
 
 import (
 	"context"
+	"sort"
 
 	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/client/chaincode/txwaiter"
 	peerSDK "github.com/bogatyr285/hlf-sdk-go/peer"
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/peer"
 	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
-	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
 	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 )
 
+// GetInstalledChaincodePackageFuncName is the chaincode function name used to get installed chaincode package,
+// fabric doesn't export constant for it
+const GetInstalledChaincodePackageFuncName = `GetInstalledChaincodePackage`
+
 type lifecycleCC struct {
-	peerPool  api.PeerPool
-	identity  msp.SigningIdentity
-	processor api.PeerProcessor
+	peerPool api.PeerPool
+	orderer  api.Orderer
+	identity msp.SigningIdentity
 }
 
 func (c *lifecycleCC) QueryInstalledChaincodes(ctx context.Context) (*lb.QueryInstalledChaincodesResult, error) {
-	resp, err := c.endorse(ctx, lifecycle.QueryInstalledChaincodesFuncName, ``)
-	if err != nil {
-		return nil, err
-	}
 	ccData := new(lb.QueryInstalledChaincodesResult)
-	if err = proto.Unmarshal(resp, ccData); err != nil {
-		return nil, errors.Wrap(err, `failed to unmarshal protobuf`)
+	if err := c.query(ctx, ``, lifecycle.QueryInstalledChaincodesFuncName, &lb.QueryInstalledChaincodesArgs{}, ccData); err != nil {
+		return nil, errors.Wrap(err, `failed to query installed chaincodes`)
 	}
 	return ccData, nil
 }
 
-func (c *lifecycleCC) endorse(ctx context.Context, fn string, args ...string) ([]byte, error) {
-	prop, _, err := c.processor.CreateProposal(lifecycleName, c.identity, fn, util.ToChaincodeArgs(args...), nil)
+func (c *lifecycleCC) InstallChaincode(ctx context.Context, args *lb.InstallChaincodeArgs) (*lb.InstallChaincodeResult, error) {
+	result := new(lb.InstallChaincodeResult)
+	if err := c.query(ctx, ``, lifecycle.InstallChaincodeFuncName, args, result); err != nil {
+		return nil, errors.Wrap(err, `failed to install chaincode`)
+	}
+	return result, nil
+}
+
+func (c *lifecycleCC) GetInstalledChaincodePackage(ctx context.Context, args *lb.GetInstalledChaincodePackageArgs) (*lb.GetInstalledChaincodePackageResult, error) {
+	result := new(lb.GetInstalledChaincodePackageResult)
+	if err := c.query(ctx, ``, GetInstalledChaincodePackageFuncName, args, result); err != nil {
+		return nil, errors.Wrap(err, `failed to get installed chaincode package`)
+	}
+	return result, nil
+}
+
+func (c *lifecycleCC) ApproveChaincodeDefinitionForMyOrg(ctx context.Context, channelName string, args *lb.ApproveChaincodeDefinitionForMyOrgArgs) error {
+	// approval is endorsed only by peers of current MSP
+	if err := c.invoke(ctx, channelName, lifecycle.ApproveChaincodeDefinitionForMyOrgFuncName, args, []string{c.identity.GetMSPIdentifier()}); err != nil {
+		return errors.Wrap(err, `failed to approve chaincode definition`)
+	}
+	return nil
+}
+
+func (c *lifecycleCC) QueryApprovedChaincodeDefinition(ctx context.Context, channelName string, args *lb.QueryApprovedChaincodeDefinitionArgs) (*lb.QueryApprovedChaincodeDefinitionResult, error) {
+	result := new(lb.QueryApprovedChaincodeDefinitionResult)
+	if err := c.query(ctx, channelName, lifecycle.QueryApprovedChaincodeDefinitionFuncName, args, result); err != nil {
+		return nil, errors.Wrap(err, `failed to query approved chaincode definition`)
+	}
+	return result, nil
+}
+
+func (c *lifecycleCC) CheckCommitReadiness(ctx context.Context, channelName string, args *lb.CheckCommitReadinessArgs) (*lb.CheckCommitReadinessResult, error) {
+	result := new(lb.CheckCommitReadinessResult)
+	if err := c.query(ctx, channelName, lifecycle.CheckCommitReadinessFuncName, args, result); err != nil {
+		return nil, errors.Wrap(err, `failed to check commit readiness`)
+	}
+	return result, nil
+}
+
+func (c *lifecycleCC) CommitChaincodeDefinition(ctx context.Context, channelName string, args *lb.CommitChaincodeDefinitionArgs, opts ...api.LifecycleCommitOption) error {
+	var commitOpts api.LifecycleCommitOptions
+
+	for _, opt := range opts {
+		if err := opt(&commitOpts); err != nil {
+			return errors.Wrap(err, `failed to apply LifecycleCommitOption`)
+		}
+	}
+
+	endorsingMspIDs := commitOpts.EndorsingMspIDs
+
+	// by default commit is endorsed by all MSPs which already approved definition
+	if len(endorsingMspIDs) == 0 {
+		readiness, err := c.CheckCommitReadiness(ctx, channelName, &lb.CheckCommitReadinessArgs{
+			Sequence:            args.Sequence,
+			Name:                args.Name,
+			Version:             args.Version,
+			EndorsementPlugin:   args.EndorsementPlugin,
+			ValidationPlugin:    args.ValidationPlugin,
+			ValidationParameter: args.ValidationParameter,
+			Collections:         args.Collections,
+			InitRequired:        args.InitRequired,
+		})
+		if err != nil {
+			return err
+		}
+
+		var approvedMspIDs []string
+		for mspID, approved := range readiness.Approvals {
+			if approved {
+				approvedMspIDs = append(approvedMspIDs, mspID)
+			}
+		}
+		if len(approvedMspIDs) == 0 {
+			return errors.New(`no MSPs approved chaincode definition`)
+		}
+		sort.Strings(approvedMspIDs)
+
+		// MSPs without peers in pool can't endorse commit, so they are skipped
+		for _, mspID := range approvedMspIDs {
+			if peers, err := c.peerPool.Peers(mspID); err == nil && len(peers) > 0 {
+				endorsingMspIDs = append(endorsingMspIDs, mspID)
+			}
+		}
+		if len(endorsingMspIDs) == 0 {
+			return errors.Errorf("no peers in pool for MSPs which approved chaincode definition: %v", approvedMspIDs)
+		}
+	}
+
+	if err := c.invoke(ctx, channelName, lifecycle.CommitChaincodeDefinitionFuncName, args, endorsingMspIDs); err != nil {
+		return errors.Wrap(err, `failed to commit chaincode definition`)
+	}
+	return nil
+}
+
+func (c *lifecycleCC) QueryChaincodeDefinition(ctx context.Context, channelName string, args *lb.QueryChaincodeDefinitionArgs) (*lb.QueryChaincodeDefinitionResult, error) {
+	result := new(lb.QueryChaincodeDefinitionResult)
+	if err := c.query(ctx, channelName, lifecycle.QueryChaincodeDefinitionFuncName, args, result); err != nil {
+		return nil, errors.Wrap(err, `failed to query chaincode definition`)
+	}
+	return result, nil
+}
+
+func (c *lifecycleCC) QueryChaincodeDefinitions(ctx context.Context, channelName string, args *lb.QueryChaincodeDefinitionsArgs) (*lb.QueryChaincodeDefinitionsResult, error) {
+	result := new(lb.QueryChaincodeDefinitionsResult)
+	if err := c.query(ctx, channelName, lifecycle.QueryChaincodeDefinitionsFuncName, args, result); err != nil {
+		return nil, errors.Wrap(err, `failed to query chaincode definitions`)
+	}
+	return result, nil
+}
+
+// query endorses proposal on peer of current MSP and unmarshals response payload to out
+func (c *lifecycleCC) query(ctx context.Context, channelName string, fn string, args proto.Message, out proto.Message) error {
+	prop, _, err := c.createProposal(channelName, fn, args)
 	if err != nil {
-		return nil, errors.Wrap(err, `failed to create proposal`)
+		return err
 	}
 
 	resp, err := c.peerPool.Process(ctx, c.identity.GetMSPIdentifier(), prop)
 	if err != nil {
-		return nil, errors.Wrap(err, `failed to endorse proposal`)
+		return errors.Wrap(err, `failed to endorse proposal`)
+	}
+
+	if err = proto.Unmarshal(resp.Response.Payload, out); err != nil {
+		return errors.Wrap(err, `failed to unmarshal protobuf`)
+	}
+	return nil
+}
+
+// invoke collects endorsements from peers of presented MSPs, sends transaction to orderer and waits for its commit
+func (c *lifecycleCC) invoke(ctx context.Context, channelName string, fn string, args proto.Message, endorsingMspIDs []string) error {
+	if c.orderer == nil {
+		return errors.New(`orderer is not defined`)
+	}
+
+	prop, tx, err := c.createProposal(channelName, fn, args)
+	if err != nil {
+		return err
+	}
+
+	processor := peerSDK.NewProcessor(channelName)
+	responses, err := processor.Send(ctx, prop, endorsingMspIDs, c.peerPool)
+	if err != nil {
+		return errors.Wrap(err, `failed to collect peer responses`)
+	}
+
+	if err = peerSDK.CheckEndorsements(responses); err != nil {
+		return err
+	}
+
+	peerProp := new(peer.Proposal)
+	if err = proto.Unmarshal(prop.ProposalBytes, peerProp); err != nil {
+		return errors.Wrap(err, `failed to unmarshal proposal`)
+	}
+
+	env, err := protoutil.CreateSignedTx(peerProp, c.identity, responses...)
+	if err != nil {
+		return errors.Wrap(err, `could not assemble transaction`)
+	}
+
+	if _, err = c.orderer.Broadcast(ctx, env); err != nil {
+		return errors.Wrap(err, `failed to get orderer response`)
+	}
+
+	waiter, err := txwaiter.Self(&api.DoOptions{Identity: c.identity, Pool: c.peerPool})
+	if err != nil {
+		return errors.Wrap(err, `failed to initialize tx waiter`)
+	}
+
+	return waiter.Wait(ctx, channelName, tx)
+}
+
+func (c *lifecycleCC) createProposal(channelName string, fn string, args proto.Message) (*peer.SignedProposal, api.ChaincodeTx, error) {
+	argsBytes, err := proto.Marshal(args)
+	if err != nil {
+		return nil, ``, errors.Wrap(err, `failed to marshal arguments`)
+	}
+
+	processor := peerSDK.NewProcessor(channelName)
+	prop, tx, err := processor.CreateProposal(lifecycleName, c.identity, fn, [][]byte{argsBytes}, nil)
+	if err != nil {
+		return nil, ``, errors.Wrap(err, `failed to create proposal`)
 	}
-	return resp.Response.Payload, nil
+	return prop, tx, nil
 }
 
-func NewLifecycle(peerPool api.PeerPool, identity msp.SigningIdentity) api.Lifecycle {
-	return &lifecycleCC{peerPool: peerPool, identity: identity, processor: peerSDK.NewProcessor(``)}
+func NewLifecycle(peerPool api.PeerPool, orderer api.Orderer, identity msp.SigningIdentity) api.Lifecycle {
+	return &lifecycleCC{peerPool: peerPool, orderer: orderer, identity: identity}
 }
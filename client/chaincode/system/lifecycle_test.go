@@ -0,0 +1,222 @@
+package system_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/client/chaincode/system"
+	"github.com/bogatyr285/hlf-sdk-go/crypto"
+	"github.com/bogatyr285/hlf-sdk-go/crypto/ecdsa"
+	"github.com/bogatyr285/hlf-sdk-go/identity"
+)
+
+func signingIdentity(t *testing.T, mspID string) msp.SigningIdentity {
+	cs, err := crypto.GetSuite(ecdsa.Module, ecdsa.DefaultOpts)
+	require.NoError(t, err)
+	id, err := identity.NewMSPIdentityFromPath(mspID, `../testdata/msp`)
+	require.NoError(t, err)
+	return id.GetSigningIdentity(cs)
+}
+
+// mockPool endorses proposals with peer per MSP, responses are chosen by _lifecycle function name
+type mockPool struct {
+	api.PeerPool
+	mx        sync.Mutex
+	peers     map[string]*mockPeer
+	responses map[string]proto.Message
+	// function name to MSPs which endorsed it
+	endorsed map[string][]string
+}
+
+func newMockPool(t *testing.T, responses map[string]proto.Message, mspIDs ...string) *mockPool {
+	p := &mockPool{peers: make(map[string]*mockPeer), responses: responses, endorsed: make(map[string][]string)}
+	for _, mspID := range mspIDs {
+		p.peers[mspID] = &mockPeer{pool: p, mspID: mspID, endorser: signingIdentity(t, mspID)}
+	}
+	return p
+}
+
+func (p *mockPool) Process(ctx context.Context, mspID string, proposal *peer.SignedProposal) (*peer.ProposalResponse, error) {
+	mockPeer, ok := p.peers[mspID]
+	if !ok {
+		return nil, api.ErrMSPNotFound
+	}
+	return mockPeer.Endorse(ctx, proposal)
+}
+
+func (p *mockPool) Peers(mspID string) ([]api.Peer, error) {
+	mockPeer, ok := p.peers[mspID]
+	if !ok {
+		return nil, api.ErrMSPNotFound
+	}
+	return []api.Peer{mockPeer}, nil
+}
+
+func (p *mockPool) DeliverClient(mspID string, _ msp.SigningIdentity) (api.DeliverClient, error) {
+	if _, ok := p.peers[mspID]; !ok {
+		return nil, api.ErrMSPNotFound
+	}
+	return &mockDeliver{}, nil
+}
+
+type mockPeer struct {
+	api.Peer
+	pool     *mockPool
+	mspID    string
+	endorser msp.SigningIdentity
+}
+
+func (p *mockPeer) Endorse(_ context.Context, signed *peer.SignedProposal, _ ...api.PeerEndorseOpt) (*peer.ProposalResponse, error) {
+	prop, err := protoutil.UnmarshalProposal(signed.ProposalBytes)
+	if err != nil {
+		return nil, err
+	}
+	propPayload, err := protoutil.UnmarshalChaincodeProposalPayload(prop.Payload)
+	if err != nil {
+		return nil, err
+	}
+	spec := new(peer.ChaincodeInvocationSpec)
+	if err = proto.Unmarshal(propPayload.Input, spec); err != nil {
+		return nil, err
+	}
+	fn := string(spec.ChaincodeSpec.Input.Args[0])
+
+	p.pool.mx.Lock()
+	p.pool.endorsed[fn] = append(p.pool.endorsed[fn], p.mspID)
+	p.pool.mx.Unlock()
+
+	var payload []byte
+	if msg, ok := p.pool.responses[fn]; ok {
+		if payload, err = proto.Marshal(msg); err != nil {
+			return nil, err
+		}
+	}
+
+	response := &peer.Response{Status: 200, Payload: payload}
+	resp, err := protoutil.CreateProposalResponse(prop.Header, prop.Payload, response,
+		nil, nil, &peer.ChaincodeID{Name: `_lifecycle`}, p.endorser)
+	if err != nil {
+		return nil, err
+	}
+	resp.Response = response
+	return resp, nil
+}
+
+type mockDeliver struct {
+	api.DeliverClient
+}
+
+func (d *mockDeliver) SubscribeTx(context.Context, string, api.ChaincodeTx, ...api.EventCCSeekOption) (api.TxSubscription, error) {
+	return &mockTxSubscription{}, nil
+}
+
+type mockTxSubscription struct{}
+
+func (s *mockTxSubscription) Result() (peer.TxValidationCode, error) {
+	return peer.TxValidationCode_VALID, nil
+}
+
+func (s *mockTxSubscription) Close() error {
+	return nil
+}
+
+type mockOrderer struct {
+	mx        sync.Mutex
+	envelopes []*common.Envelope
+}
+
+func (o *mockOrderer) Broadcast(_ context.Context, envelope *common.Envelope) (*orderer.BroadcastResponse, error) {
+	o.mx.Lock()
+	defer o.mx.Unlock()
+	o.envelopes = append(o.envelopes, envelope)
+	return &orderer.BroadcastResponse{Status: common.Status_SUCCESS}, nil
+}
+
+func (o *mockOrderer) Deliver(context.Context, *common.Envelope) (*common.Block, error) {
+	return nil, nil
+}
+
+func TestLifecycle(t *testing.T) {
+	responses := map[string]proto.Message{
+		lifecycle.InstallChaincodeFuncName: &lb.InstallChaincodeResult{PackageId: `cc_1.0:hash`, Label: `cc_1.0`},
+		lifecycle.CheckCommitReadinessFuncName: &lb.CheckCommitReadinessResult{
+			Approvals: map[string]bool{`org1msp`: true, `org2msp`: true, `org3msp`: true, `org4msp`: false}},
+		lifecycle.QueryChaincodeDefinitionFuncName: &lb.QueryChaincodeDefinitionResult{Sequence: 1, Version: `1.0`},
+	}
+	ctx := context.Background()
+
+	t.Run(`install and query`, func(t *testing.T) {
+		pool := newMockPool(t, responses, `org1msp`)
+		lc := system.NewLifecycle(pool, &mockOrderer{}, signingIdentity(t, `org1msp`))
+
+		installed, err := lc.InstallChaincode(ctx, &lb.InstallChaincodeArgs{ChaincodeInstallPackage: []byte(`package`)})
+		require.NoError(t, err)
+		require.Equal(t, `cc_1.0:hash`, installed.PackageId)
+
+		definition, err := lc.QueryChaincodeDefinition(ctx, `channel`, &lb.QueryChaincodeDefinitionArgs{Name: `cc`})
+		require.NoError(t, err)
+		require.Equal(t, `1.0`, definition.Version)
+	})
+
+	t.Run(`approve is endorsed by own MSP`, func(t *testing.T) {
+		pool := newMockPool(t, responses, `org1msp`, `org2msp`)
+		ord := &mockOrderer{}
+		lc := system.NewLifecycle(pool, ord, signingIdentity(t, `org1msp`))
+
+		require.NoError(t, lc.ApproveChaincodeDefinitionForMyOrg(ctx, `channel`, &lb.ApproveChaincodeDefinitionForMyOrgArgs{
+			Name: `cc`, Version: `1.0`, Sequence: 1}))
+		require.Equal(t, []string{`org1msp`}, pool.endorsed[lifecycle.ApproveChaincodeDefinitionForMyOrgFuncName])
+		require.Len(t, ord.envelopes, 1)
+	})
+
+	t.Run(`commit is endorsed by approved MSPs with peers in pool`, func(t *testing.T) {
+		pool := newMockPool(t, responses, `org1msp`, `org2msp`, `org4msp`)
+		ord := &mockOrderer{}
+		lc := system.NewLifecycle(pool, ord, signingIdentity(t, `org1msp`))
+
+		require.NoError(t, lc.CommitChaincodeDefinition(ctx, `channel`, &lb.CommitChaincodeDefinitionArgs{
+			Name: `cc`, Version: `1.0`, Sequence: 1}))
+		require.ElementsMatch(t, []string{`org1msp`, `org2msp`}, pool.endorsed[lifecycle.CommitChaincodeDefinitionFuncName])
+		require.Len(t, ord.envelopes, 1)
+	})
+
+	t.Run(`commit with explicit endorsing MSPs`, func(t *testing.T) {
+		pool := newMockPool(t, responses, `org1msp`, `org2msp`)
+		lc := system.NewLifecycle(pool, &mockOrderer{}, signingIdentity(t, `org1msp`))
+
+		require.NoError(t, lc.CommitChaincodeDefinition(ctx, `channel`, &lb.CommitChaincodeDefinitionArgs{Name: `cc`},
+			api.WithCommitEndorsingMSPs(`org2msp`)))
+		require.Empty(t, pool.endorsed[lifecycle.CheckCommitReadinessFuncName])
+		require.Equal(t, []string{`org2msp`}, pool.endorsed[lifecycle.CommitChaincodeDefinitionFuncName])
+	})
+
+	t.Run(`commit fails without peers of approved MSPs`, func(t *testing.T) {
+		pool := newMockPool(t, map[string]proto.Message{
+			lifecycle.CheckCommitReadinessFuncName: &lb.CheckCommitReadinessResult{
+				Approvals: map[string]bool{`org1msp`: false, `org2msp`: true}},
+		}, `org1msp`)
+		ord := &mockOrderer{}
+		lc := system.NewLifecycle(pool, ord, signingIdentity(t, `org1msp`))
+
+		err := lc.CommitChaincodeDefinition(ctx, `channel`, &lb.CommitChaincodeDefinitionArgs{Name: `cc`})
+		require.EqualError(t, err, `no peers in pool for MSPs which approved chaincode definition: [org2msp]`)
+		require.Empty(t, ord.envelopes)
+	})
+
+	t.Run(`invoke fails without orderer`, func(t *testing.T) {
+		lc := system.NewLifecycle(newMockPool(t, responses, `org1msp`), nil, signingIdentity(t, `org1msp`))
+		require.Error(t, lc.ApproveChaincodeDefinitionForMyOrg(ctx, `channel`, &lb.ApproveChaincodeDefinitionForMyOrgArgs{}))
+	})
+}
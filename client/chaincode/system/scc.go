@@ -14,6 +14,7 @@ const (
 
 type scc struct {
 	peerPool api.PeerPool
+	orderer  api.Orderer
 	identity msp.SigningIdentity
 	fabricV2 bool
 }
@@ -34,9 +35,9 @@ func (c *scc) LSCC() api.LSCC {
 }
 
 func (c *scc) Lifecycle() api.Lifecycle {
-	return NewLifecycle(c.peerPool, c.identity)
+	return NewLifecycle(c.peerPool, c.orderer, c.identity)
 }
 
-func NewSCC(peer api.PeerPool, identity msp.SigningIdentity, fabricV2 bool) api.SystemCC {
-	return &scc{peerPool: peer, identity: identity, fabricV2: fabricV2}
+func NewSCC(peer api.PeerPool, orderer api.Orderer, identity msp.SigningIdentity, fabricV2 bool) api.SystemCC {
+	return &scc{peerPool: peer, orderer: orderer, identity: identity, fabricV2: fabricV2}
 }
@@ -0,0 +1,13 @@
+package chaincode
+
+import (
+	"github.com/bogatyr285/hlf-sdk-go/api"
+)
+
+// WithEndorsementVerifier - add option for verifying endorsements before transaction is sent to orderer
+func WithEndorsementVerifier(verifier api.EndorsementVerifier) api.DoOption {
+	return func(cfg *api.DoOptions) error {
+		cfg.EndorsementVerifier = verifier
+		return nil
+	}
+}
@@ -0,0 +1,283 @@
+// Package verifier allows to verify endorsements against channel MSP configuration before transaction is sent to orderer
+package verifier
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/pkg/errors"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+)
+
+const (
+	DefaultConfigTTL = time.Minute
+)
+
+// mspVerifier verifies endorser certificate chain, revocation lists and endorsement signature
+// using MSP definitions from channel config.
+// MSPs are cached per channel and rebuilt only when config sequence changes. Config is fetched again
+// when cached one is older than configTTL or when endorsements don't pass verification with cached MSPs
+type mspVerifier struct {
+	cscc      api.CSCC
+	cs        api.CryptoSuite
+	configTTL time.Duration
+
+	mx    sync.Mutex
+	cache map[string]*cachedMSPs
+}
+
+type cachedMSPs struct {
+	sequence  uint64
+	msps      map[string]*channelMSP
+	fetchedAt time.Time
+}
+
+type Opt func(v *mspVerifier)
+
+// WithConfigTTL sets period after which channel config is fetched again to check its sequence
+func WithConfigTTL(ttl time.Duration) Opt {
+	return func(v *mspVerifier) {
+		v.configTTL = ttl
+	}
+}
+
+type channelMSP struct {
+	roots         *x509.CertPool
+	intermediates *x509.CertPool
+	crls          []*pkix.CertificateList
+}
+
+func (v *mspVerifier) Verify(ctx context.Context, channel string, responses []*peer.ProposalResponse) error {
+	cached, fetched, err := v.channelMSPs(ctx, channel, false)
+	if err != nil {
+		return err
+	}
+
+	err = v.verify(cached.msps, responses)
+	if err == nil || fetched {
+		return err
+	}
+
+	// cached MSPs can be outdated, i.e. organization is added by config update
+	updated, _, fetchErr := v.channelMSPs(ctx, channel, true)
+	if fetchErr != nil {
+		return fetchErr
+	}
+	if updated == cached {
+		return err
+	}
+	return v.verify(updated.msps, responses)
+}
+
+func (v *mspVerifier) verify(msps map[string]*channelMSP, responses []*peer.ProposalResponse) error {
+	mErr := new(api.MultiError)
+	for _, resp := range responses {
+		if err := v.verifyEndorsement(msps, resp.Payload, resp.Endorsement); err != nil {
+			mErr.Add(err)
+		}
+	}
+
+	if len(mErr.Errors) != 0 {
+		return mErr
+	}
+	return nil
+}
+
+// channelMSPs returns cached MSPs of channel, config is fetched if cache is empty, expired or refresh is required.
+// Returned flag is true if config is fetched
+func (v *mspVerifier) channelMSPs(ctx context.Context, channel string, refresh bool) (*cachedMSPs, bool, error) {
+	v.mx.Lock()
+	cached := v.cache[channel]
+	valid := cached != nil && !refresh && time.Since(cached.fetchedAt) < v.configTTL
+	v.mx.Unlock()
+
+	if valid {
+		return cached, false, nil
+	}
+
+	config, err := v.cscc.GetChannelConfig(ctx, channel)
+	if err != nil {
+		return nil, false, errors.Wrap(err, `failed to get channel config`)
+	}
+
+	v.mx.Lock()
+	defer v.mx.Unlock()
+
+	if cached = v.cache[channel]; cached != nil && cached.sequence == config.Sequence {
+		cached.fetchedAt = time.Now()
+		return cached, true, nil
+	}
+
+	msps, err := channelMSPs(config)
+	if err != nil {
+		return nil, false, errors.Wrap(err, `failed to get channel MSPs`)
+	}
+
+	cached = &cachedMSPs{sequence: config.Sequence, msps: msps, fetchedAt: time.Now()}
+	v.cache[channel] = cached
+	return cached, true, nil
+}
+
+func (v *mspVerifier) verifyEndorsement(msps map[string]*channelMSP, payload []byte, endorsement *peer.Endorsement) error {
+	if endorsement == nil {
+		return errors.New(`empty endorsement`)
+	}
+
+	sID := new(msp.SerializedIdentity)
+	if err := proto.Unmarshal(endorsement.Endorser, sID); err != nil {
+		return errors.Wrap(err, `failed to unmarshal endorser identity`)
+	}
+
+	chMSP, ok := msps[sID.Mspid]
+	if !ok {
+		return errors.Errorf("MSP %s not found in channel config", sID.Mspid)
+	}
+
+	cert, err := parseCert(sID.IdBytes)
+	if err != nil {
+		return errors.Wrapf(err, "%s: failed to parse endorser certificate", sID.Mspid)
+	}
+
+	// expiration is not checked like in fabric MSP, validation time is set to the start of certificate validity
+	chains, err := cert.Verify(x509.VerifyOptions{
+		Roots:         chMSP.roots,
+		Intermediates: chMSP.intermediates,
+		CurrentTime:   cert.NotBefore.Add(time.Second),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "%s: failed to verify endorser certificate chain", sID.Mspid)
+	}
+
+	if err = checkRevoked(chains[0], chMSP.crls); err != nil {
+		return errors.Wrapf(err, "%s: %s", sID.Mspid, cert.Subject.CommonName)
+	}
+
+	msg := make([]byte, 0, len(payload)+len(endorsement.Endorser))
+	msg = append(append(msg, payload...), endorsement.Endorser...)
+	if err = v.cs.Verify(cert.PublicKey, msg, endorsement.Signature); err != nil {
+		return errors.Wrapf(err, "%s: %s: failed to verify endorsement signature", sID.Mspid, cert.Subject.CommonName)
+	}
+
+	return nil
+}
+
+// checkRevoked checks every certificate of chain (except root) against CRLs signed by its issuer
+func checkRevoked(chain []*x509.Certificate, crls []*pkix.CertificateList) error {
+	for i := 0; i < len(chain)-1; i++ {
+		cert, issuer := chain[i], chain[i+1]
+		for _, crl := range crls {
+			if issuer.CheckCRLSignature(crl) != nil {
+				continue
+			}
+			for _, revoked := range crl.TBSCertList.RevokedCertificates {
+				if revoked.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+					return errors.Errorf("certificate %s is revoked", cert.Subject.CommonName)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// channelMSPs returns MSP definitions of application and orderer organizations from channel config
+func channelMSPs(config *common.Config) (map[string]*channelMSP, error) {
+	msps := make(map[string]*channelMSP)
+
+	for _, groupName := range []string{channelconfig.ApplicationGroupKey, channelconfig.OrdererGroupKey} {
+		group, ok := config.GetChannelGroup().GetGroups()[groupName]
+		if !ok {
+			continue
+		}
+
+		for orgName, org := range group.Groups {
+			mspValue, ok := org.Values[channelconfig.MSPKey]
+			if !ok {
+				continue
+			}
+
+			mspConfig := new(msp.MSPConfig)
+			if err := proto.Unmarshal(mspValue.Value, mspConfig); err != nil {
+				return nil, errors.Wrapf(err, "failed to unmarshal MSP config of %s", orgName)
+			}
+
+			// only x509 based MSPs are supported
+			if mspConfig.Type != 0 {
+				continue
+			}
+
+			fabricConfig := new(msp.FabricMSPConfig)
+			if err := proto.Unmarshal(mspConfig.Config, fabricConfig); err != nil {
+				return nil, errors.Wrapf(err, "failed to unmarshal fabric MSP config of %s", orgName)
+			}
+
+			chMSP, err := newChannelMSP(fabricConfig)
+			if err != nil {
+				return nil, errors.Wrapf(err, "MSP %s", fabricConfig.Name)
+			}
+			msps[fabricConfig.Name] = chMSP
+		}
+	}
+
+	return msps, nil
+}
+
+func newChannelMSP(config *msp.FabricMSPConfig) (*channelMSP, error) {
+	chMSP := &channelMSP{
+		roots:         x509.NewCertPool(),
+		intermediates: x509.NewCertPool(),
+	}
+
+	for _, certPEM := range config.RootCerts {
+		cert, err := parseCert(certPEM)
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse root certificate`)
+		}
+		chMSP.roots.AddCert(cert)
+	}
+
+	for _, certPEM := range config.IntermediateCerts {
+		cert, err := parseCert(certPEM)
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse intermediate certificate`)
+		}
+		chMSP.intermediates.AddCert(cert)
+	}
+
+	for _, crlPEM := range config.RevocationList {
+		crl, err := x509.ParseCRL(crlPEM)
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse CRL`)
+		}
+		chMSP.crls = append(chMSP.crls, crl)
+	}
+
+	return chMSP, nil
+}
+
+func parseCert(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, api.ErrInvalidPEMStructure
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// New returns endorsement verifier which uses MSP definitions from channel config received via CSCC
+func New(cscc api.CSCC, cs api.CryptoSuite, opts ...Opt) api.EndorsementVerifier {
+	v := &mspVerifier{cscc: cscc, cs: cs, configTTL: DefaultConfigTTL, cache: make(map[string]*cachedMSPs)}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
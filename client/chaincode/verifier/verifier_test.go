@@ -0,0 +1,222 @@
+package verifier_test
+
+import (
+	"context"
+	stdecdsa "crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	fabricMsp "github.com/hyperledger/fabric/msp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/client/chaincode/verifier"
+	"github.com/bogatyr285/hlf-sdk-go/crypto"
+	"github.com/bogatyr285/hlf-sdk-go/crypto/ecdsa"
+	"github.com/bogatyr285/hlf-sdk-go/identity"
+)
+
+const mspPath = `../testdata/msp`
+
+type mockCSCC struct {
+	api.CSCC
+	config *common.Config
+	calls  int
+}
+
+func (c *mockCSCC) GetChannelConfig(context.Context, string) (*common.Config, error) {
+	c.calls++
+	return c.config, nil
+}
+
+func channelConfig(t *testing.T, mspIDs ...string) *common.Config {
+	orgs := make(map[string]*common.ConfigGroup)
+	for _, mspID := range mspIDs {
+		mspConfig, err := fabricMsp.GetVerifyingMspConfig(mspPath, mspID, fabricMsp.ProviderTypeToString(fabricMsp.FABRIC))
+		require.NoError(t, err)
+
+		orgs[mspID] = &common.ConfigGroup{
+			Values: map[string]*common.ConfigValue{
+				channelconfig.MSPKey: {Value: protoMarshal(t, mspConfig)},
+			},
+		}
+	}
+
+	return &common.Config{
+		ChannelGroup: &common.ConfigGroup{
+			Groups: map[string]*common.ConfigGroup{
+				channelconfig.ApplicationGroupKey: {Groups: orgs},
+			},
+		},
+	}
+}
+
+func protoMarshal(t *testing.T, msg proto.Message) []byte {
+	b, err := proto.Marshal(msg)
+	require.NoError(t, err)
+	return b
+}
+
+func endorse(t *testing.T, id fabricMsp.SigningIdentity, payload []byte) *peer.ProposalResponse {
+	endorser, err := id.Serialize()
+	require.NoError(t, err)
+
+	sig, err := id.Sign(append(append([]byte{}, payload...), endorser...))
+	require.NoError(t, err)
+
+	return &peer.ProposalResponse{
+		Payload:     payload,
+		Endorsement: &peer.Endorsement{Endorser: endorser, Signature: sig},
+	}
+}
+
+func TestVerify(t *testing.T) {
+	cs, err := crypto.GetSuite(ecdsa.Module, ecdsa.DefaultOpts)
+	require.NoError(t, err)
+
+	org1, err := identity.NewMSPIdentityFromPath(`org1msp`, mspPath)
+	require.NoError(t, err)
+	org2, err := identity.NewMSPIdentityFromPath(`org2msp`, mspPath)
+	require.NoError(t, err)
+
+	v := verifier.New(&mockCSCC{config: channelConfig(t, `org1msp`)}, cs)
+	payload := []byte(`payload`)
+
+	// valid endorsement
+	require.NoError(t, v.Verify(context.Background(), `channel`, []*peer.ProposalResponse{
+		endorse(t, org1.GetSigningIdentity(cs), payload),
+	}))
+
+	// MSP is not member of channel
+	err = v.Verify(context.Background(), `channel`, []*peer.ProposalResponse{
+		endorse(t, org2.GetSigningIdentity(cs), payload),
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `MSP org2msp not found in channel config`)
+
+	// payload is changed after endorsement
+	resp := endorse(t, org1.GetSigningIdentity(cs), payload)
+	resp.Payload = []byte(`changed payload`)
+	err = v.Verify(context.Background(), `channel`, []*peer.ProposalResponse{resp})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `failed to verify endorsement signature`)
+}
+
+func TestVerify_ConfigCache(t *testing.T) {
+	cs, err := crypto.GetSuite(ecdsa.Module, ecdsa.DefaultOpts)
+	require.NoError(t, err)
+
+	org1, err := identity.NewMSPIdentityFromPath(`org1msp`, mspPath)
+	require.NoError(t, err)
+	org2, err := identity.NewMSPIdentityFromPath(`org2msp`, mspPath)
+	require.NoError(t, err)
+
+	cscc := &mockCSCC{config: channelConfig(t, `org1msp`)}
+	v := verifier.New(cscc, cs)
+	payload := []byte(`payload`)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, v.Verify(context.Background(), `channel`, []*peer.ProposalResponse{
+			endorse(t, org1.GetSigningIdentity(cs), payload),
+		}))
+	}
+	require.Equal(t, 1, cscc.calls)
+
+	// organization is added by config update, failed verification refreshes config
+	cscc.config = channelConfig(t, `org1msp`, `org2msp`)
+	cscc.config.Sequence = 1
+	require.NoError(t, v.Verify(context.Background(), `channel`, []*peer.ProposalResponse{
+		endorse(t, org2.GetSigningIdentity(cs), payload),
+	}))
+	require.Equal(t, 2, cscc.calls)
+
+	// expired config is fetched again
+	v = verifier.New(cscc, cs, verifier.WithConfigTTL(0))
+	for i := 0; i < 2; i++ {
+		require.NoError(t, v.Verify(context.Background(), `channel`, []*peer.ProposalResponse{
+			endorse(t, org1.GetSigningIdentity(cs), payload),
+		}))
+	}
+	require.Equal(t, 4, cscc.calls)
+}
+
+func TestVerify_Revoked(t *testing.T) {
+	cs, err := crypto.GetSuite(ecdsa.Module, ecdsa.DefaultOpts)
+	require.NoError(t, err)
+
+	now := time.Now()
+	caKey, err := stdecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: `ca.org3.example.com`},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	// endorser returns endorsement signed by certificate with presented serial number
+	endorser := func(serial int64) func(payload []byte) *peer.ProposalResponse {
+		key, err := stdecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		certDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: fmt.Sprintf("peer%d.org3.example.com", serial)},
+			NotBefore:    now.Add(-time.Hour),
+			NotAfter:     now.Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+		}, caCert, &key.PublicKey, caKey)
+		require.NoError(t, err)
+
+		return func(payload []byte) *peer.ProposalResponse {
+			id := protoMarshal(t, &msp.SerializedIdentity{
+				Mspid: `org3msp`, IdBytes: pem.EncodeToMemory(&pem.Block{Type: `CERTIFICATE`, Bytes: certDER})})
+			sig, err := cs.Sign(append(append([]byte{}, payload...), id...), key)
+			require.NoError(t, err)
+			return &peer.ProposalResponse{Payload: payload, Endorsement: &peer.Endorsement{Endorser: id, Signature: sig}}
+		}
+	}
+	revoked, valid := endorser(2), endorser(3)
+
+	crlDER, err := caCert.CreateCRL(rand.Reader, caKey,
+		[]pkix.RevokedCertificate{{SerialNumber: big.NewInt(2), RevocationTime: now}}, now, now.Add(time.Hour))
+	require.NoError(t, err)
+
+	mspConfig := &msp.MSPConfig{Config: protoMarshal(t, &msp.FabricMSPConfig{
+		Name:           `org3msp`,
+		RootCerts:      [][]byte{pem.EncodeToMemory(&pem.Block{Type: `CERTIFICATE`, Bytes: caDER})},
+		RevocationList: [][]byte{pem.EncodeToMemory(&pem.Block{Type: `X509 CRL`, Bytes: crlDER})},
+	})}
+	config := &common.Config{ChannelGroup: &common.ConfigGroup{Groups: map[string]*common.ConfigGroup{
+		channelconfig.ApplicationGroupKey: {Groups: map[string]*common.ConfigGroup{
+			`org3`: {Values: map[string]*common.ConfigValue{channelconfig.MSPKey: {Value: protoMarshal(t, mspConfig)}}},
+		}},
+	}}}
+
+	v := verifier.New(&mockCSCC{config: config}, cs)
+	payload := []byte(`payload`)
+
+	require.NoError(t, v.Verify(context.Background(), `channel`, []*peer.ProposalResponse{valid(payload)}))
+
+	err = v.Verify(context.Background(), `channel`, []*peer.ProposalResponse{valid(payload), revoked(payload)})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `org3msp: peer2.org3.example.com: certificate peer2.org3.example.com is revoked`)
+}
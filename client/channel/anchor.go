@@ -0,0 +1,38 @@
+package channel
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/configtx"
+)
+
+// SetAnchorPeers updates anchor peers of organization and waits for config block on peer of current MSP
+func (c *Core) SetAnchorPeers(ctx context.Context, mspID string, anchorPeers []api.HostPort) error {
+	return c.updateConfig(ctx,
+		func(config *configtx.ChannelConfig) error {
+			if !hasOrganization(config, mspID) {
+				return errors.Errorf("organization %s not found in channel config", mspID)
+			}
+			org, _ := config.Application.OrganizationByMSP(mspID)
+			org.AnchorPeers = anchorPeers
+			return nil
+		},
+		func(config *configtx.ChannelConfig) bool {
+			if !hasOrganization(config, mspID) {
+				return false
+			}
+			org, _ := config.Application.OrganizationByMSP(mspID)
+			return anchorPeersEqual(org.AnchorPeers, anchorPeers)
+		})
+}
+
+func anchorPeersEqual(a, b []api.HostPort) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,212 @@
+package channel_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/client/channel"
+	"github.com/bogatyr285/hlf-sdk-go/configtx"
+	"github.com/bogatyr285/hlf-sdk-go/util"
+)
+
+// mockDeliver creates block subscriptions, blocks and errors are pushed to all of them
+type mockDeliver struct {
+	api.DeliverClient
+
+	mu   sync.Mutex
+	subs []*mockBlockSubscription
+}
+
+func (d *mockDeliver) SubscribeBlock(context.Context, string, ...api.EventCCSeekOption) (api.BlockSubscription, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	sub := &mockBlockSubscription{blocks: make(chan *common.Block, 1), errors: make(chan error, 1)}
+	d.subs = append(d.subs, sub)
+	return sub, nil
+}
+
+func (d *mockDeliver) subscriptions() []*mockBlockSubscription {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.subs
+}
+
+func (d *mockDeliver) commit(block *common.Block) {
+	for _, sub := range d.subscriptions() {
+		sub.blocks <- block
+	}
+}
+
+func (d *mockDeliver) fail(err error) {
+	for _, sub := range d.subscriptions() {
+		sub.errors <- err
+	}
+}
+
+type mockBlockSubscription struct {
+	blocks chan *common.Block
+	errors chan error
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func (s *mockBlockSubscription) Blocks() <-chan *common.Block {
+	return s.blocks
+}
+
+func (s *mockBlockSubscription) Errors() chan error {
+	return s.errors
+}
+
+func (s *mockBlockSubscription) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *mockBlockSubscription) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// channelConfig returns config of channel created by ApplicationGenesis profile
+func channelConfig(t *testing.T) *common.Config {
+	profile, err := configtx.LoadProfile(`../../configtx/testdata/configtx.yaml`, `ApplicationGenesis`)
+	require.NoError(t, err)
+
+	genesis, err := configtx.GenesisBlock(`channel`, profile)
+	require.NoError(t, err)
+
+	config, err := util.GetConfigFromBlock(genesis)
+	require.NoError(t, err)
+	return config
+}
+
+// nextConfigBlock returns config block with modified config and incremented sequence
+func nextConfigBlock(t *testing.T, config *common.Config, modify func(*configtx.ChannelConfig)) *common.Block {
+	parsed, err := configtx.ParseConfig(config)
+	require.NoError(t, err)
+	modify(parsed)
+
+	next, err := parsed.Config()
+	require.NoError(t, err)
+	next.Sequence = config.Sequence + 1
+
+	block, err := configtx.NewConfigBlock(`channel`, next.Sequence, next)
+	require.NoError(t, err)
+	return block
+}
+
+// requireConfigUpdate checks that envelope is config update transaction of channel
+func requireConfigUpdate(t *testing.T, envelope *common.Envelope) {
+	payload, err := protoutil.UnmarshalPayload(envelope.Payload)
+	require.NoError(t, err)
+	chHeader, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	require.NoError(t, err)
+	require.Equal(t, int32(common.HeaderType_CONFIG_UPDATE), chHeader.Type)
+	require.Equal(t, `channel`, chHeader.ChannelId)
+}
+
+func TestCore_SetAnchorPeers(t *testing.T) {
+	config := channelConfig(t)
+	anchorPeers := []api.HostPort{{Host: `peer1.org1.example.com`, Port: 7051}}
+
+	updated := nextConfigBlock(t, config, func(c *configtx.ChannelConfig) {
+		org, _ := c.Application.OrganizationByMSP(`org1msp`)
+		org.AnchorPeers = anchorPeers
+	})
+
+	t.Run(`wait on joined peers`, func(t *testing.T) {
+		broken, committed, notJoined := &mockDeliver{}, &mockDeliver{}, &mockDeliver{}
+		pool := &mockPool{peers: []api.Peer{
+			&mockPeer{uri: `peer0`, down: true},
+			&mockPeer{uri: `peer1`, channels: []string{`channel`}, config: config, deliver: broken},
+			&mockPeer{uri: `peer2`, channels: []string{`channel`}, config: config, deliver: committed},
+			&mockPeer{uri: `peer3`, deliver: notJoined},
+		}}
+
+		ord := &mockOrderer{onBroadcast: func(*common.Envelope) {
+			broken.fail(errors.New(`stream broken`))
+			committed.commit(updated)
+		}}
+
+		core := channel.NewCore(`org1msp`, `channel`, pool, ord, nil, signingIdentity(t), nil, true, zap.NewNop())
+		require.NoError(t, core.SetAnchorPeers(context.Background(), `org1msp`, anchorPeers))
+
+		require.Len(t, ord.broadcasted, 1)
+		requireConfigUpdate(t, ord.broadcasted[0])
+
+		require.Empty(t, notJoined.subscriptions())
+		for _, d := range []*mockDeliver{broken, committed} {
+			require.Len(t, d.subscriptions(), 1)
+			require.True(t, d.subscriptions()[0].isClosed())
+		}
+	})
+
+	t.Run(`all subscriptions failed`, func(t *testing.T) {
+		broken := &mockDeliver{}
+		pool := &mockPool{peers: []api.Peer{
+			&mockPeer{uri: `peer0`, channels: []string{`channel`}, config: config, deliver: broken},
+		}}
+		ord := &mockOrderer{onBroadcast: func(*common.Envelope) {
+			broken.fail(errors.New(`stream broken`))
+		}}
+
+		core := channel.NewCore(`org1msp`, `channel`, pool, ord, nil, signingIdentity(t), nil, true, zap.NewNop())
+		err := core.SetAnchorPeers(context.Background(), `org1msp`, anchorPeers)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `stream broken`)
+		require.Len(t, ord.broadcasted, 1)
+	})
+
+	t.Run(`peers not joined`, func(t *testing.T) {
+		genesis, err := configtx.NewConfigBlock(`channel`, 0, config)
+		require.NoError(t, err)
+		notJoined := &mockDeliver{}
+		pool := &mockPool{peers: []api.Peer{&mockPeer{uri: `peer0`, deliver: notJoined}}}
+		ord := &mockOrderer{blocks: []*common.Block{genesis}}
+
+		core := channel.NewCore(`org1msp`, `channel`, pool, ord, nil, signingIdentity(t), nil, true, zap.NewNop())
+		require.NoError(t, core.SetAnchorPeers(context.Background(), `org1msp`, anchorPeers))
+
+		require.Len(t, ord.broadcasted, 1)
+		requireConfigUpdate(t, ord.broadcasted[0])
+		require.Empty(t, notJoined.subscriptions())
+	})
+
+	t.Run(`no changes`, func(t *testing.T) {
+		pool := &mockPool{peers: []api.Peer{
+			&mockPeer{uri: `peer0`, channels: []string{`channel`}, config: config, deliver: &mockDeliver{}},
+		}}
+		ord := &mockOrderer{}
+
+		core := channel.NewCore(`org1msp`, `channel`, pool, ord, nil, signingIdentity(t), nil, true, zap.NewNop())
+		require.NoError(t, core.SetAnchorPeers(context.Background(), `org1msp`,
+			[]api.HostPort{{Host: `peer0.org1.example.com`, Port: 7051}}))
+		require.Empty(t, ord.broadcasted)
+	})
+
+	t.Run(`unknown organization`, func(t *testing.T) {
+		pool := &mockPool{peers: []api.Peer{
+			&mockPeer{uri: `peer0`, channels: []string{`channel`}, config: config, deliver: &mockDeliver{}},
+		}}
+		ord := &mockOrderer{}
+
+		core := channel.NewCore(`org1msp`, `channel`, pool, ord, nil, signingIdentity(t), nil, true, zap.NewNop())
+		err := core.SetAnchorPeers(context.Background(), `org3msp`, anchorPeers)
+		require.EqualError(t, err, `organization org3msp not found in channel config`)
+		require.Empty(t, ord.broadcasted)
+	})
+}
@@ -0,0 +1,222 @@
+package channel
+
+import (
+	"context"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/pkg/errors"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/configtx"
+	"github.com/bogatyr285/hlf-sdk-go/util"
+)
+
+// ConfigBlock returns latest config block of channel fetched from orderer
+func (c *Core) ConfigBlock(ctx context.Context) (*common.Block, error) {
+	return util.GetConfigBlockFromOrderer(ctx, c.identity, c.orderer, c.chanName)
+}
+
+// currentConfig returns channel config from peers of current MSP,
+// config block is fetched from orderer if no peer has joined channel yet
+func (c *Core) currentConfig(ctx context.Context) (config *common.Config, fromPeers bool, err error) {
+	config, err = c.cscc().GetChannelConfig(ctx, c.chanName)
+	if err == nil {
+		return config, true, nil
+	}
+
+	block, ordErr := c.ConfigBlock(ctx)
+	if ordErr != nil {
+		return nil, false, errors.Wrapf(ordErr, "failed to get channel config from peers (%s) and orderer", err)
+	}
+
+	if config, err = util.GetConfigFromBlock(block); err != nil {
+		return nil, false, err
+	}
+	return config, false, nil
+}
+
+// updateConfig applies changes to current channel config, signs config update by current identity and presented signers,
+// submits it to orderer and waits for config block matching presented condition on peer of current MSP
+func (c *Core) updateConfig(
+	ctx context.Context,
+	modify func(*configtx.ChannelConfig) error,
+	match func(*configtx.ChannelConfig) bool,
+	signers ...msp.SigningIdentity,
+) error {
+	current, fromPeers, err := c.currentConfig(ctx)
+	if err != nil {
+		return errors.Wrap(err, `failed to get channel config`)
+	}
+
+	config, err := configtx.ParseConfig(current)
+	if err != nil {
+		return errors.Wrap(err, `failed to parse channel config`)
+	}
+
+	if err = modify(config); err != nil {
+		return err
+	}
+
+	configUpdate, err := config.ConfigUpdate(c.chanName)
+	if err == configtx.ErrNoDifferences {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, `failed to compute config update`)
+	}
+
+	update, err := configtx.NewUpdateFromConfigUpdate(configUpdate)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range append([]msp.SigningIdentity{c.identity}, signers...) {
+		if err = update.Sign(id); err != nil {
+			return err
+		}
+	}
+
+	// peers of current MSP have not joined channel, so there is no need to wait for config block
+	if !fromPeers {
+		return update.Submit(ctx, c.orderer, c.identity)
+	}
+
+	// subscribe before broadcast to not miss config block
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	wait, err := c.waitConfig(waitCtx, match)
+	if err != nil {
+		return err
+	}
+
+	if err = update.Submit(ctx, c.orderer, c.identity); err != nil || wait == nil {
+		return err
+	}
+
+	return wait()
+}
+
+// waitConfig subscribes on blocks of peers of current MSP which have joined channel
+// and returns func waiting until any of them commits config block matching presented condition.
+// Peers which are down or have not joined channel are skipped, returned func is nil if there are no such peers
+func (c *Core) waitConfig(ctx context.Context, match func(*configtx.ChannelConfig) bool) (func() error, error) {
+	peers, err := c.peerPool.Peers(c.mspId)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to get peers`)
+	}
+
+	var subscriptions []api.BlockSubscription
+	for _, p := range peers {
+		if !c.joined(ctx, p) {
+			continue
+		}
+
+		deliver, err := p.DeliverClient(c.identity)
+		if err != nil {
+			continue
+		}
+
+		sub, err := deliver.SubscribeBlock(ctx, c.chanName, api.SeekNewest())
+		if err != nil {
+			continue
+		}
+		subscriptions = append(subscriptions, sub)
+	}
+
+	if len(subscriptions) == 0 {
+		return nil, nil
+	}
+
+	return func() error {
+		defer closeSubscriptions(subscriptions)
+
+		waitCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		results := make(chan error, len(subscriptions))
+		for i := range subscriptions {
+			go func(sub api.BlockSubscription) {
+				results <- waitConfigBlock(waitCtx, sub, match)
+			}(subscriptions[i])
+		}
+
+		mErr := new(api.MultiError)
+		for range subscriptions {
+			err := <-results
+			if err == nil {
+				return nil
+			}
+			mErr.Add(err)
+		}
+		return mErr
+	}, nil
+}
+
+// joined returns true if peer is available and has joined channel
+func (c *Core) joined(ctx context.Context, p api.Peer) bool {
+	channels, err := c.csccFor(p).GetChannels(ctx)
+	if err != nil {
+		return false
+	}
+	for _, ch := range channels.Channels {
+		if ch.ChannelId == c.chanName {
+			return true
+		}
+	}
+	return false
+}
+
+// waitConfigBlock waits for config block matching presented condition
+func waitConfigBlock(ctx context.Context, sub api.BlockSubscription, match func(*configtx.ChannelConfig) bool) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case err, ok := <-sub.Errors():
+			if !ok {
+				return errors.New(`block subscription closed`)
+			}
+			if err != nil {
+				return errors.Wrap(err, `block subscription failed`)
+			}
+
+		case block, ok := <-sub.Blocks():
+			if !ok {
+				return errors.New(`block subscription closed`)
+			}
+
+			config, err := util.GetConfigFromBlock(block)
+			if err == util.ErrNotConfigBlock {
+				continue
+			} else if err != nil {
+				return err
+			}
+
+			parsed, err := configtx.ParseConfig(config)
+			if err != nil {
+				return errors.Wrap(err, `failed to parse channel config`)
+			}
+
+			if match(parsed) {
+				return nil
+			}
+		}
+	}
+}
+
+func closeSubscriptions(subscriptions []api.BlockSubscription) {
+	for _, sub := range subscriptions {
+		_ = sub.Close()
+	}
+}
+
+// hasOrganization returns if channel config contains application organization with presented MSP ID
+func hasOrganization(config *configtx.ChannelConfig, mspID string) bool {
+	if config.Application == nil {
+		return false
+	}
+	_, ok := config.Application.OrganizationByMSP(mspID)
+	return ok
+}
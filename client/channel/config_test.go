@@ -0,0 +1,86 @@
+package channel_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/client/channel"
+	"github.com/bogatyr285/hlf-sdk-go/configtx"
+	"github.com/bogatyr285/hlf-sdk-go/util"
+)
+
+// mockOrderer delivers blocks from chain by seek info and records broadcasted envelopes
+type mockOrderer struct {
+	api.Orderer
+	blocks      []*common.Block
+	broadcasted []*common.Envelope
+	onBroadcast func(*common.Envelope)
+}
+
+func (o *mockOrderer) Broadcast(_ context.Context, envelope *common.Envelope) (*orderer.BroadcastResponse, error) {
+	o.broadcasted = append(o.broadcasted, envelope)
+	if o.onBroadcast != nil {
+		o.onBroadcast(envelope)
+	}
+	return &orderer.BroadcastResponse{Status: common.Status_SUCCESS}, nil
+}
+
+func (o *mockOrderer) Deliver(_ context.Context, envelope *common.Envelope) (*common.Block, error) {
+	payload, err := protoutil.UnmarshalPayload(envelope.Payload)
+	if err != nil {
+		return nil, err
+	}
+	seekInfo := new(orderer.SeekInfo)
+	if err = proto.Unmarshal(payload.Data, seekInfo); err != nil {
+		return nil, err
+	}
+
+	switch start := seekInfo.Start.Type.(type) {
+	case *orderer.SeekPosition_Newest:
+		return o.blocks[len(o.blocks)-1], nil
+	case *orderer.SeekPosition_Specified:
+		return o.blocks[start.Specified.Number], nil
+	}
+	return o.blocks[0], nil
+}
+
+func TestCore_ConfigBlock(t *testing.T) {
+	profile, err := configtx.LoadProfile(`../../configtx/testdata/configtx.yaml`, `ApplicationGenesis`)
+	require.NoError(t, err)
+
+	genesis, err := configtx.GenesisBlock(`channel`, profile)
+	require.NoError(t, err)
+
+	config, err := util.GetConfigFromBlock(genesis)
+	require.NoError(t, err)
+	config.Sequence = 1
+
+	configBlock, err := configtx.NewConfigBlock(`channel`, 1, config)
+	require.NoError(t, err)
+
+	// newest block references config block in metadata
+	newest := protoutil.NewBlock(2, nil)
+	lastConfig, err := proto.Marshal(&common.OrdererBlockMetadata{LastConfig: &common.LastConfig{Index: 1}})
+	require.NoError(t, err)
+	newest.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES], err = proto.Marshal(&common.Metadata{Value: lastConfig})
+	require.NoError(t, err)
+
+	ord := &mockOrderer{blocks: []*common.Block{genesis, configBlock, newest}}
+	core := channel.NewCore(`org1msp`, `channel`, &mockPool{}, ord, nil, signingIdentity(t), nil, true, zap.NewNop())
+
+	block, err := core.ConfigBlock(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), block.Header.Number)
+
+	blockConfig, err := util.GetConfigFromBlock(block)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), blockConfig.Sequence)
+}
@@ -25,6 +25,7 @@ type Core struct {
 	chaincodesMx sync.Mutex
 	dp           api.DiscoveryProvider
 	identity     msp.SigningIdentity
+	fetcher      api.CCFetcher
 	fabricV2     bool
 	log          *zap.Logger
 }
@@ -76,7 +77,7 @@ func (c *Core) Chaincode(serviceDiscCtx context.Context, ccName string) (api.Cha
 		return nil, err
 	}
 
-	cc = chaincode.NewCore(c.mspId, ccName, c.chanName, c.peerPool, c.orderer, c.dp, c.identity)
+	cc = chaincode.NewCore(c.mspId, ccName, c.chanName, c.peerPool, c.orderer, c.dp, c.identity, c.fetcher, c.fabricV2)
 	c.chaincodes[ccName] = cc
 
 	return cc, nil
@@ -88,6 +89,7 @@ func NewCore(
 	orderer api.Orderer,
 	dp api.DiscoveryProvider,
 	identity msp.SigningIdentity,
+	fetcher api.CCFetcher,
 	fabricV2 bool,
 	log *zap.Logger,
 ) api.Channel {
@@ -99,6 +101,7 @@ func NewCore(
 		chaincodes: make(map[string]*chaincode.Core),
 		dp:         dp,
 		identity:   identity,
+		fetcher:    fetcher,
 		fabricV2:   fabricV2,
 		log:        log,
 	}
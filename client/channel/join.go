@@ -2,6 +2,7 @@ package channel
 
 import (
 	"context"
+	"sync"
 
 	"github.com/bogatyr285/hlf-sdk-go/api"
 	"github.com/bogatyr285/hlf-sdk-go/client/chaincode/system"
@@ -9,24 +10,157 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/orderer"
+	fabricPeer "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/pkg/errors"
 )
 
-func (c *Core) Join(ctx context.Context) error {
-	channelGenesis, err := c.getGenesisBlockFromOrderer(ctx)
+// Join joins channel on first ready peer of current MSP
+func (c *Core) Join(ctx context.Context, opts ...api.JoinOpt) error {
+	joinOpts, err := c.joinOpts(ctx, opts...)
 	if err != nil {
+		return err
+	}
+
+	return c.join(ctx, c.cscc(), joinOpts)
+}
+
+// JoinPeers joins channel on every peer of current MSP, peers which have already joined channel are skipped.
+// Genesis block is fetched from orderer only if some peer has not joined channel yet
+func (c *Core) JoinPeers(ctx context.Context, opts ...api.JoinOpt) ([]api.PeerJoinResult, error) {
+	peers, err := c.peerPool.Peers(c.mspId)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to get peers`)
+	}
+
+	joinOpts, err := applyJoinOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]api.PeerJoinResult, len(peers))
+	parallel(len(peers), func(i int) {
+		results[i] = c.peerJoined(ctx, peers[i])
+	})
+
+	var notJoined []int
+	for i, result := range results {
+		if !result.AlreadyJoined && result.Err == nil {
+			notJoined = append(notJoined, i)
+		}
+	}
+	if len(notJoined) == 0 {
+		return results, nil
+	}
+
+	if err = c.fetchGenesisBlock(ctx, joinOpts); err != nil {
+		return nil, err
+	}
+
+	parallel(len(notJoined), func(i int) {
+		results[notJoined[i]].Err = c.join(ctx, c.csccFor(peers[notJoined[i]]), joinOpts)
+	})
+
+	return results, nil
+}
+
+// peerJoined returns result with AlreadyJoined set if peer has joined channel
+func (c *Core) peerJoined(ctx context.Context, p api.Peer) api.PeerJoinResult {
+	result := api.PeerJoinResult{Peer: p.Uri()}
+
+	channels, err := c.csccFor(p).GetChannels(ctx)
+	if err != nil {
+		result.Err = errors.Wrap(err, `failed to get channels`)
+		return result
+	}
+
+	for _, ch := range channels.Channels {
+		if ch.ChannelId == c.chanName {
+			result.AlreadyJoined = true
+			break
+		}
+	}
+	return result
+}
+
+// parallel calls fn concurrently for indexes from 0 to n-1 and waits for all calls
+func parallel(n int, fn func(i int)) {
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func (c *Core) join(ctx context.Context, cscc api.CSCC, opts *api.JoinOpts) error {
+	if opts.SnapshotPath != `` {
+		return cscc.JoinChainBySnapshot(ctx, opts.SnapshotPath)
+	}
+	return cscc.JoinChain(ctx, c.chanName, opts.GenesisBlock)
+}
+
+// joinOpts applies join options, genesis block is fetched from orderer if neither block nor snapshot is presented
+func (c *Core) joinOpts(ctx context.Context, opts ...api.JoinOpt) (*api.JoinOpts, error) {
+	joinOpts, err := applyJoinOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.fetchGenesisBlock(ctx, joinOpts); err != nil {
+		return nil, err
+	}
+	return joinOpts, nil
+}
+
+func applyJoinOpts(opts ...api.JoinOpt) (*api.JoinOpts, error) {
+	joinOpts := new(api.JoinOpts)
+	for _, opt := range opts {
+		if err := opt(joinOpts); err != nil {
+			return nil, errors.Wrap(err, `failed to apply join option`)
+		}
+	}
+	return joinOpts, nil
+}
+
+// fetchGenesisBlock sets genesis block from orderer if neither block nor snapshot is presented
+func (c *Core) fetchGenesisBlock(ctx context.Context, joinOpts *api.JoinOpts) error {
+	if joinOpts.GenesisBlock != nil || joinOpts.SnapshotPath != `` {
+		return nil
+	}
+
+	var err error
+	if joinOpts.GenesisBlock, err = c.getGenesisBlockFromOrderer(ctx); err != nil {
 		return errors.Wrap(err, `failed to retrieve genesis block from orderer`)
 	}
+	return nil
+}
 
-	var cscc api.CSCC
+func (c *Core) cscc() api.CSCC {
+	return c.newCSCC(c.peerPool)
+}
+
+// csccFor returns CSCC which sends proposals only to presented peer
+func (c *Core) csccFor(p api.Peer) api.CSCC {
+	return c.newCSCC(&singlePeerPool{PeerPool: c.peerPool, peer: p})
+}
 
+func (c *Core) newCSCC(pool api.PeerPool) api.CSCC {
 	if c.fabricV2 {
-		cscc = system.NewCSCCV2(c.peerPool, c.identity)
-	} else {
-		cscc = system.NewCSCCV1(c.peerPool, c.identity)
+		return system.NewCSCCV2(pool, c.identity)
 	}
+	return system.NewCSCCV1(pool, c.identity)
+}
+
+// singlePeerPool processes proposals on one peer
+type singlePeerPool struct {
+	api.PeerPool
+	peer api.Peer
+}
 
-	return cscc.JoinChain(ctx, c.chanName, channelGenesis)
+func (p *singlePeerPool) Process(ctx context.Context, _ string, proposal *fabricPeer.SignedProposal) (*fabricPeer.ProposalResponse, error) {
+	return p.peer.Endorse(ctx, proposal)
 }
 
 func (c *Core) getGenesisBlockFromOrderer(ctx context.Context) (*common.Block, error) {
@@ -0,0 +1,222 @@
+package channel_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	fabricPeer "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/client/channel"
+	"github.com/bogatyr285/hlf-sdk-go/crypto"
+	"github.com/bogatyr285/hlf-sdk-go/crypto/ecdsa"
+	"github.com/bogatyr285/hlf-sdk-go/identity"
+)
+
+// mockPeer answers to CSCC GetChannels, GetChannelConfig, JoinChain and JoinChainBySnapshot
+type mockPeer struct {
+	api.Peer
+	uri     string
+	down    bool
+	joinErr error
+	config  *common.Config
+	deliver api.DeliverClient
+
+	mu       sync.Mutex
+	channels []string
+	joined   []string
+}
+
+func (p *mockPeer) Uri() string {
+	return p.uri
+}
+
+func (p *mockPeer) DeliverClient(msp.SigningIdentity) (api.DeliverClient, error) {
+	if p.deliver == nil {
+		return nil, errors.New(`deliver is not available`)
+	}
+	return p.deliver, nil
+}
+
+func (p *mockPeer) Endorse(_ context.Context, signed *fabricPeer.SignedProposal, _ ...api.PeerEndorseOpt) (*fabricPeer.ProposalResponse, error) {
+	if p.down {
+		return nil, errors.New(`peer is down`)
+	}
+
+	proposal, err := protoutil.UnmarshalProposal(signed.ProposalBytes)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := protoutil.UnmarshalChaincodeProposalPayload(proposal.Payload)
+	if err != nil {
+		return nil, err
+	}
+	spec := new(fabricPeer.ChaincodeInvocationSpec)
+	if err = proto.Unmarshal(payload.Input, spec); err != nil {
+		return nil, err
+	}
+	args := spec.ChaincodeSpec.Input.Args
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var respPayload []byte
+	switch string(args[0]) {
+	case `GetChannels`:
+		resp := &fabricPeer.ChannelQueryResponse{}
+		for _, ch := range p.channels {
+			resp.Channels = append(resp.Channels, &fabricPeer.ChannelInfo{ChannelId: ch})
+		}
+		respPayload, _ = proto.Marshal(resp)
+
+	case `GetChannelConfig`:
+		if p.config == nil {
+			return nil, errors.New(`channel not found`)
+		}
+		respPayload, _ = proto.Marshal(p.config)
+
+	case `JoinChain`:
+		if p.joinErr != nil {
+			return nil, p.joinErr
+		}
+		block := new(common.Block)
+		if err = proto.Unmarshal(args[1], block); err != nil {
+			return nil, err
+		}
+		p.joined = append(p.joined, string(block.Data.Data[0]))
+
+	case `JoinChainBySnapshot`:
+		p.joined = append(p.joined, `snapshot:`+string(args[1]))
+	}
+
+	return &fabricPeer.ProposalResponse{Response: &fabricPeer.Response{Status: 200, Payload: respPayload}}, nil
+}
+
+type mockPool struct {
+	api.PeerPool
+	peers []api.Peer
+}
+
+func (p *mockPool) Peers(string) ([]api.Peer, error) {
+	return p.peers, nil
+}
+
+// Process sends proposal to peers in order until first successful response
+func (p *mockPool) Process(ctx context.Context, _ string, proposal *fabricPeer.SignedProposal) (*fabricPeer.ProposalResponse, error) {
+	err := errors.New(`no peers`)
+	for _, peer := range p.peers {
+		var resp *fabricPeer.ProposalResponse
+		if resp, err = peer.Endorse(ctx, proposal); err == nil {
+			return resp, nil
+		}
+	}
+	return nil, err
+}
+
+func signingIdentity(t *testing.T) msp.SigningIdentity {
+	cs, err := crypto.GetSuite(ecdsa.Module, ecdsa.DefaultOpts)
+	require.NoError(t, err)
+	id, err := identity.NewMSPIdentityFromPath(`org1msp`, `../chaincode/testdata/msp`)
+	require.NoError(t, err)
+	return id.GetSigningIdentity(cs)
+}
+
+func TestCore_JoinPeers(t *testing.T) {
+	peers := []*mockPeer{
+		{uri: `peer0`},
+		{uri: `peer1`, channels: []string{`channel`}},
+		{uri: `peer2`, joinErr: errors.New(`join failed`)},
+	}
+	pool := &mockPool{}
+	for _, p := range peers {
+		pool.peers = append(pool.peers, p)
+	}
+
+	core := channel.NewCore(`org1msp`, `channel`, pool, nil, nil, signingIdentity(t), nil, true, zap.NewNop())
+
+	genesis := protoutil.NewBlock(0, nil)
+	genesis.Data.Data = [][]byte{[]byte(`genesis`)}
+	genesisBytes, err := proto.Marshal(genesis)
+	require.NoError(t, err)
+
+	results, err := core.JoinPeers(context.Background(), api.WithGenesisBlockBytes(genesisBytes))
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	require.Equal(t, api.PeerJoinResult{Peer: `peer0`}, results[0])
+	require.Equal(t, api.PeerJoinResult{Peer: `peer1`, AlreadyJoined: true}, results[1])
+	require.Equal(t, `peer2`, results[2].Peer)
+	require.Error(t, results[2].Err)
+
+	require.Equal(t, []string{`genesis`}, peers[0].joined)
+	require.Empty(t, peers[1].joined)
+
+	results, err = core.JoinPeers(context.Background(), api.WithSnapshot(`/snapshots/channel/10`))
+	require.NoError(t, err)
+	require.Equal(t, []string{`genesis`, `snapshot:/snapshots/channel/10`}, peers[0].joined)
+	require.Equal(t, []string{`snapshot:/snapshots/channel/10`}, peers[2].joined)
+}
+
+// unavailableOrderer fails every deliver request
+type unavailableOrderer struct {
+	api.Orderer
+	mu       sync.Mutex
+	delivers int
+}
+
+func (o *unavailableOrderer) Deliver(context.Context, *common.Envelope) (*common.Block, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.delivers++
+	return nil, errors.New(`orderer is unavailable`)
+}
+
+func TestCore_JoinPeers_GenesisBlock(t *testing.T) {
+	t.Run(`all peers joined`, func(t *testing.T) {
+		ord := &unavailableOrderer{}
+		pool := &mockPool{peers: []api.Peer{
+			&mockPeer{uri: `peer0`, channels: []string{`channel`}},
+			&mockPeer{uri: `peer1`, channels: []string{`other`, `channel`}},
+			&mockPeer{uri: `peer2`, down: true},
+		}}
+		core := channel.NewCore(`org1msp`, `channel`, pool, ord, nil, signingIdentity(t), nil, true, zap.NewNop())
+
+		results, err := core.JoinPeers(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, api.PeerJoinResult{Peer: `peer0`, AlreadyJoined: true}, results[0])
+		require.Equal(t, api.PeerJoinResult{Peer: `peer1`, AlreadyJoined: true}, results[1])
+		require.Error(t, results[2].Err)
+		// genesis block is not requested
+		require.Zero(t, ord.delivers)
+	})
+
+	t.Run(`peer not joined`, func(t *testing.T) {
+		ord := &unavailableOrderer{}
+		notJoined := &mockPeer{uri: `peer1`}
+		pool := &mockPool{peers: []api.Peer{&mockPeer{uri: `peer0`, channels: []string{`channel`}}, notJoined}}
+		core := channel.NewCore(`org1msp`, `channel`, pool, ord, nil, signingIdentity(t), nil, true, zap.NewNop())
+
+		_, err := core.JoinPeers(context.Background())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `failed to retrieve genesis block from orderer: orderer is unavailable`)
+		require.Equal(t, 1, ord.delivers)
+		require.Empty(t, notJoined.joined)
+	})
+
+	t.Run(`invalid option`, func(t *testing.T) {
+		pool := &mockPool{peers: []api.Peer{&mockPeer{uri: `peer0`, channels: []string{`channel`}}}}
+		core := channel.NewCore(`org1msp`, `channel`, pool, &unavailableOrderer{}, nil, signingIdentity(t), nil, true, zap.NewNop())
+
+		_, err := core.JoinPeers(context.Background(), api.WithGenesisBlockBytes([]byte(`not a block`)))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `failed to apply join option`)
+	})
+}
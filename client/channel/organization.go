@@ -0,0 +1,36 @@
+package channel
+
+import (
+	"context"
+
+	"github.com/hyperledger/fabric/msp"
+
+	"github.com/bogatyr285/hlf-sdk-go/configtx"
+)
+
+// AddOrganization adds application organization defined by MSP directory to channel
+func (c *Core) AddOrganization(ctx context.Context, mspID, mspDir string, signers ...msp.SigningIdentity) error {
+	org, err := configtx.NewOrganization(mspID, mspDir)
+	if err != nil {
+		return err
+	}
+
+	return c.updateConfig(ctx,
+		func(config *configtx.ChannelConfig) error {
+			return config.AddOrganization(org)
+		},
+		func(config *configtx.ChannelConfig) bool {
+			return hasOrganization(config, mspID)
+		}, signers...)
+}
+
+// RemoveOrganization removes application organization from channel
+func (c *Core) RemoveOrganization(ctx context.Context, mspID string, signers ...msp.SigningIdentity) error {
+	return c.updateConfig(ctx,
+		func(config *configtx.ChannelConfig) error {
+			return config.RemoveOrganization(mspID)
+		},
+		func(config *configtx.ChannelConfig) bool {
+			return !hasOrganization(config, mspID)
+		}, signers...)
+}
@@ -0,0 +1,139 @@
+package channel_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	mspPb "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/client/channel"
+	"github.com/bogatyr285/hlf-sdk-go/configtx"
+)
+
+const partnerMSPDir = `../../configtx/testdata/partnermsp`
+
+// partnerSigner signs by org1msp key, but is serialized as identity of partnermsp
+type partnerSigner struct {
+	msp.SigningIdentity
+}
+
+func (s partnerSigner) Serialize() ([]byte, error) {
+	return proto.Marshal(&mspPb.SerializedIdentity{Mspid: `partnermsp`, IdBytes: []byte(`partner`)})
+}
+
+// signerMSPs returns MSP ids of config signature creators
+func signerMSPs(t *testing.T, signatures []*common.ConfigSignature) []string {
+	var mspIDs []string
+	for _, sig := range signatures {
+		header, err := protoutil.UnmarshalSignatureHeader(sig.SignatureHeader)
+		require.NoError(t, err)
+		creator := new(mspPb.SerializedIdentity)
+		require.NoError(t, proto.Unmarshal(header.Creator, creator))
+		mspIDs = append(mspIDs, creator.Mspid)
+	}
+	return mspIDs
+}
+
+// configUpdateEnvelope returns config update with signatures from broadcasted transaction
+func configUpdateEnvelope(t *testing.T, envelope *common.Envelope) (*common.ConfigUpdate, []*common.ConfigSignature) {
+	requireConfigUpdate(t, envelope)
+
+	payload, err := protoutil.UnmarshalPayload(envelope.Payload)
+	require.NoError(t, err)
+	updateEnvelope := new(common.ConfigUpdateEnvelope)
+	require.NoError(t, proto.Unmarshal(payload.Data, updateEnvelope))
+	update := new(common.ConfigUpdate)
+	require.NoError(t, proto.Unmarshal(updateEnvelope.ConfigUpdate, update))
+
+	return update, updateEnvelope.Signatures
+}
+
+// organizationsCore returns core with one joined peer, which commits config block modified by presented func
+// when config update is broadcasted
+func organizationsCore(t *testing.T, modify func(*configtx.ChannelConfig)) (api.Channel, *mockOrderer) {
+	config := channelConfig(t)
+	deliver := &mockDeliver{}
+	pool := &mockPool{peers: []api.Peer{
+		&mockPeer{uri: `peer0`, channels: []string{`channel`}, config: config, deliver: deliver},
+	}}
+
+	ord := &mockOrderer{}
+	if modify != nil {
+		updated := nextConfigBlock(t, config, modify)
+		ord.onBroadcast = func(*common.Envelope) {
+			deliver.commit(updated)
+		}
+	}
+
+	return channel.NewCore(`org1msp`, `channel`, pool, ord, nil, signingIdentity(t), nil, true, zap.NewNop()), ord
+}
+
+func TestCore_AddOrganization(t *testing.T) {
+	org, err := configtx.NewOrganization(`org3msp`, partnerMSPDir)
+	require.NoError(t, err)
+
+	t.Run(`added`, func(t *testing.T) {
+		core, ord := organizationsCore(t, func(c *configtx.ChannelConfig) {
+			require.NoError(t, c.AddOrganization(org))
+		})
+
+		require.NoError(t, core.AddOrganization(context.Background(), `org3msp`, partnerMSPDir, partnerSigner{signingIdentity(t)}))
+		require.Len(t, ord.broadcasted, 1)
+
+		update, signatures := configUpdateEnvelope(t, ord.broadcasted[0])
+		// signed by current identity and presented signer
+		require.Equal(t, []string{`org1msp`, `partnermsp`}, signerMSPs(t, signatures))
+		require.Contains(t, update.WriteSet.Groups[channelconfig.ApplicationGroupKey].Groups, `org3msp`)
+	})
+
+	t.Run(`already exists`, func(t *testing.T) {
+		core, ord := organizationsCore(t, nil)
+
+		err := core.AddOrganization(context.Background(), `org1msp`, partnerMSPDir)
+		require.Error(t, err)
+		require.Equal(t, configtx.ErrOrganizationExists, errors.Cause(err))
+		require.Empty(t, ord.broadcasted)
+	})
+
+	t.Run(`invalid msp dir`, func(t *testing.T) {
+		core, ord := organizationsCore(t, nil)
+
+		require.Error(t, core.AddOrganization(context.Background(), `org3msp`, `testdata/missing`))
+		require.Empty(t, ord.broadcasted)
+	})
+}
+
+func TestCore_RemoveOrganization(t *testing.T) {
+	t.Run(`removed`, func(t *testing.T) {
+		core, ord := organizationsCore(t, func(c *configtx.ChannelConfig) {
+			require.NoError(t, c.RemoveOrganization(`partnermsp`))
+		})
+
+		require.NoError(t, core.RemoveOrganization(context.Background(), `partnermsp`))
+		require.Len(t, ord.broadcasted, 1)
+
+		update, signatures := configUpdateEnvelope(t, ord.broadcasted[0])
+		require.Equal(t, []string{`org1msp`}, signerMSPs(t, signatures))
+		application := update.WriteSet.Groups[channelconfig.ApplicationGroupKey]
+		require.Contains(t, application.Groups, `org1msp`)
+		require.NotContains(t, application.Groups, `partnermsp`)
+	})
+
+	t.Run(`not found`, func(t *testing.T) {
+		core, ord := organizationsCore(t, nil)
+
+		err := core.RemoveOrganization(context.Background(), `org3msp`)
+		require.Error(t, err)
+		require.Equal(t, configtx.ErrOrganizationNotFound, errors.Cause(err))
+		require.Empty(t, ord.broadcasted)
+	})
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"io"
 	"sync"
 	"time"
 
@@ -23,7 +24,6 @@ import (
 	"github.com/bogatyr285/hlf-sdk-go/orderer"
 	"github.com/bogatyr285/hlf-sdk-go/peer"
 	"github.com/bogatyr285/hlf-sdk-go/peer/pool"
-	"github.com/bogatyr285/hlf-sdk-go/util"
 )
 
 type core struct {
@@ -36,6 +36,7 @@ type core struct {
 	orderer           api.Orderer
 	discoveryProvider api.DiscoveryProvider
 	channels          map[string]api.Channel
+	channelOrderers   []api.Orderer // orderers created for channels from discovery
 	channelMx         sync.Mutex
 	chaincodes        map[string]api.ChaincodePackage
 	chaincodeMx       sync.Mutex
@@ -48,7 +49,7 @@ func (c *core) Chaincode(name string) api.ChaincodePackage {
 	c.chaincodeMx.Lock()
 	defer c.chaincodeMx.Unlock()
 	if cc, ok := c.chaincodes[name]; !ok {
-		cc = chaincode.NewCorePackage(name, system.NewLSCC(c.peerPool, c.identity), c.fetcher, c.orderer, c.identity)
+		cc = chaincode.NewCorePackage(name, c.System(), c.fetcher, c.orderer, c.identity, c.fabricV2)
 		c.chaincodes[name] = cc
 		return cc
 	} else {
@@ -57,7 +58,7 @@ func (c *core) Chaincode(name string) api.ChaincodePackage {
 }
 
 func (c *core) System() api.SystemCC {
-	return system.NewSCC(c.peerPool, c.identity, c.fabricV2)
+	return system.NewSCC(c.peerPool, c.orderer, c.identity, c.fabricV2)
 }
 
 func (c *core) CurrentIdentity() msp.SigningIdentity {
@@ -104,12 +105,10 @@ func (c *core) Channel(name string) api.Channel {
 					}
 				}
 
-				ordConn, err := util.NewGRPCConnectionFromConfigs(c.ctx, c.logger, grpcConnCfgs...)
-				if err != nil {
-					log.Error(`Failed to initialize custom GRPC connection for orderer`, zap.String(`channel`, name), zap.Error(err))
-				}
-				if ord, err = orderer.NewFromGRPC(c.ctx, ordConn); err != nil {
-					log.Error(`Failed to construct orderer from GRPC connection`)
+				if ord, err = orderer.NewPoolFromConfigs(c.ctx, c.logger, grpcConnCfgs); err != nil {
+					log.Error(`Failed to initialize orderer pool`, zap.String(`channel`, name), zap.Error(err))
+				} else {
+					c.channelOrderers = append(c.channelOrderers, ord)
 				}
 			}
 		}
@@ -120,7 +119,7 @@ func (c *core) Channel(name string) api.Channel {
 		}
 
 		ch = channel.NewCore(c.mspId, name, c.peerPool, ord,
-			c.discoveryProvider, c.identity, c.fabricV2, c.logger)
+			c.discoveryProvider, c.identity, c.fetcher, c.fabricV2, c.logger)
 		c.channels[name] = ch
 		return ch
 	}
@@ -130,6 +129,28 @@ func (c *core) FabricV2() bool {
 	return c.fabricV2
 }
 
+// Close closes default orderer and orderers of channels if they implement io.Closer,
+// pending broadcasts of orderer pool receive orderer.ErrBroadcastStreamClosed
+func (c *core) Close() error {
+	c.channelMx.Lock()
+	defer c.channelMx.Unlock()
+
+	mErr := new(api.MultiError)
+	for _, ord := range append([]api.Orderer{c.orderer}, c.channelOrderers...) {
+		if closer, ok := ord.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				mErr.Add(errors.Wrap(err, `failed to close orderer`))
+			}
+		}
+	}
+	c.channelOrderers = nil
+
+	if len(mErr.Errors) > 0 {
+		return mErr
+	}
+	return nil
+}
+
 func NewCore(mspId string, identity api.Identity, opts ...CoreOpt) (api.Core, error) {
 	var err error
 	core := &core{
@@ -254,11 +275,7 @@ func NewCore(mspId string, identity api.Identity, opts ...CoreOpt) (api.Core, er
 	if core.orderer == nil && core.config != nil {
 		core.logger.Info("initializing orderer")
 		if len(core.config.Orderers) > 0 {
-			ordConn, err := util.NewGRPCConnectionFromConfigs(core.ctx, core.logger, core.config.Orderers...)
-			if err != nil {
-				return nil, errors.Wrap(err, `failed to initialize orderer connection`)
-			}
-			core.orderer, err = orderer.NewFromGRPC(core.ctx, ordConn)
+			core.orderer, err = orderer.NewPoolFromConfigs(core.ctx, core.logger, core.config.Orderers)
 			if err != nil {
 				return nil, errors.Wrap(err, `failed to initialize orderer`)
 			}
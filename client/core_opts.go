@@ -112,3 +112,12 @@ func WithFabricV2(fabricV2 bool) CoreOpt {
 		return nil
 	}
 }
+
+// WithFetcher allows to use custom chaincode fetcher, i.e. fetcher.NewArchive or fetcher.NewCDS.
+// Otherwise, fetcher of local Go chaincode source code is used
+func WithFetcher(fetcher api.CCFetcher) CoreOpt {
+	return func(c *core) error {
+		c.fetcher = fetcher
+		return nil
+	}
+}
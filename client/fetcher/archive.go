@@ -0,0 +1,63 @@
+package fetcher
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/pkg/errors"
+)
+
+type archiveFetcher struct {
+	archivePath string
+	ccType      peer.ChaincodeSpec_Type
+}
+
+// Fetch reads prebuilt tar.gz code package and uses it as code package of deployment spec
+func (f *archiveFetcher) Fetch(ctx context.Context, id *peer.ChaincodeID) (*peer.ChaincodeDeploymentSpec, error) {
+	ccBytes, err := ioutil.ReadFile(f.archivePath)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to read chaincode archive`)
+	}
+
+	if err = validateTarGz(ccBytes); err != nil {
+		return nil, errors.Wrap(err, `invalid chaincode archive`)
+	}
+
+	return &peer.ChaincodeDeploymentSpec{
+		ChaincodeSpec: &peer.ChaincodeSpec{
+			Type:        f.ccType,
+			ChaincodeId: id,
+		},
+		CodePackage: ccBytes,
+	}, nil
+}
+
+func validateTarGz(source []byte) error {
+	gzReader, err := gzip.NewReader(bytes.NewReader(source))
+	if err != nil {
+		return errors.Wrap(err, `error reading as gzip stream`)
+	}
+
+	tarReader := tar.NewReader(gzReader)
+	for {
+		_, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, `error inspecting next tar header`)
+		}
+	}
+}
+
+// NewArchive returns fetcher which uses prebuilt tar.gz code package located at archivePath,
+// path from chaincode id is used as chaincode path in deployment spec
+func NewArchive(archivePath string, ccType peer.ChaincodeSpec_Type) api.CCFetcher {
+	return &archiveFetcher{archivePath: archivePath, ccType: ccType}
+}
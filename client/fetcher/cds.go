@@ -0,0 +1,89 @@
+package fetcher
+
+import (
+	"context"
+	"io/ioutil"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/common/ccpackage"
+	"github.com/pkg/errors"
+)
+
+type cdsFetcher struct {
+	cdsPath string
+}
+
+// Fetch reads deployment spec produced by `peer chaincode package`,
+// name and version from chaincode id, if presented, must match packaged ones.
+// Owner endorsements of SignedCDS are not part of deployment spec, use ParseSignedCDS to read them
+func (f *cdsFetcher) Fetch(ctx context.Context, id *peer.ChaincodeID) (*peer.ChaincodeDeploymentSpec, error) {
+	cdsBytes, err := ioutil.ReadFile(f.cdsPath)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to read chaincode package`)
+	}
+
+	depSpec, err := ParseCDS(cdsBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	packaged := depSpec.ChaincodeSpec.ChaincodeId
+	if id.GetName() != `` && id.GetName() != packaged.Name {
+		return nil, errors.Errorf("chaincode name mismatch: requested %s, packaged %s", id.GetName(), packaged.Name)
+	}
+	if id.GetVersion() != `` && id.GetVersion() != packaged.Version {
+		return nil, errors.Errorf("chaincode version mismatch: requested %s, packaged %s", id.GetVersion(), packaged.Version)
+	}
+
+	return depSpec, nil
+}
+
+// ParseCDS parses ChaincodeDeploymentSpec or envelope with SignedChaincodeDeploymentSpec
+func ParseCDS(cdsBytes []byte) (*peer.ChaincodeDeploymentSpec, error) {
+	// signed package is envelope with SignedChaincodeDeploymentSpec
+	env := new(common.Envelope)
+	if err := proto.Unmarshal(cdsBytes, env); err == nil {
+		if chHeader, signedSpec, err := ccpackage.ExtractSignedCCDepSpec(env); err == nil &&
+			chHeader.Type == int32(common.HeaderType_CHAINCODE_PACKAGE) {
+			cdsBytes = signedSpec.ChaincodeDeploymentSpec
+		}
+	}
+
+	depSpec := new(peer.ChaincodeDeploymentSpec)
+	if err := proto.Unmarshal(cdsBytes, depSpec); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal chaincode deployment spec`)
+	}
+
+	if depSpec.ChaincodeSpec == nil || depSpec.ChaincodeSpec.ChaincodeId == nil {
+		return nil, errors.New(`chaincode deployment spec without chaincode id`)
+	}
+
+	return depSpec, nil
+}
+
+// ParseSignedCDS parses envelope with SignedChaincodeDeploymentSpec produced by `peer chaincode package -s`,
+// returned spec keeps instantiation policy and owner endorsements
+func ParseSignedCDS(cdsBytes []byte) (*peer.SignedChaincodeDeploymentSpec, error) {
+	env := new(common.Envelope)
+	if err := proto.Unmarshal(cdsBytes, env); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal envelope`)
+	}
+
+	chHeader, signedSpec, err := ccpackage.ExtractSignedCCDepSpec(env)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to extract signed chaincode deployment spec`)
+	}
+	if chHeader.Type != int32(common.HeaderType_CHAINCODE_PACKAGE) {
+		return nil, errors.Errorf("unexpected header type: %s", common.HeaderType(chHeader.Type))
+	}
+
+	return signedSpec, nil
+}
+
+// NewCDS returns fetcher which uses CDS or SignedCDS file produced by `peer chaincode package`
+func NewCDS(cdsPath string) api.CCFetcher {
+	return &cdsFetcher{cdsPath: cdsPath}
+}
@@ -0,0 +1,138 @@
+package fetcher_test
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/client/fetcher"
+)
+
+type mockPlatform struct {
+	payload []byte
+}
+
+func (p mockPlatform) Name() string {
+	return peer.ChaincodeSpec_GOLANG.String()
+}
+
+func (p mockPlatform) GetDeploymentPayload(string) ([]byte, error) {
+	return p.payload, nil
+}
+
+func TestFetch(t *testing.T) {
+	archive, err := ioutil.ReadFile(`testdata/mycc.tar.gz`)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name    string
+		fetcher api.CCFetcher
+		id      *peer.ChaincodeID
+		expID   *peer.ChaincodeID
+		expType peer.ChaincodeSpec_Type
+		expErr  string
+	}{
+		{
+			name:    `archive`,
+			fetcher: fetcher.NewArchive(`testdata/mycc.tar.gz`, peer.ChaincodeSpec_GOLANG),
+			id:      &peer.ChaincodeID{Name: `mycc`, Version: `1.0`, Path: `mycc`},
+			expID:   &peer.ChaincodeID{Name: `mycc`, Version: `1.0`, Path: `mycc`},
+			expType: peer.ChaincodeSpec_GOLANG,
+		},
+		{
+			name:    `archive is not tar.gz`,
+			fetcher: fetcher.NewArchive(`testdata/mycc.cds`, peer.ChaincodeSpec_GOLANG),
+			id:      &peer.ChaincodeID{Name: `mycc`},
+			expErr:  `invalid chaincode archive`,
+		},
+		{
+			name:    `archive not found`,
+			fetcher: fetcher.NewArchive(`testdata/unknown.tar.gz`, peer.ChaincodeSpec_GOLANG),
+			id:      &peer.ChaincodeID{Name: `mycc`},
+			expErr:  `failed to read chaincode archive`,
+		},
+		{
+			name:    `cds`,
+			fetcher: fetcher.NewCDS(`testdata/mycc.cds`),
+			id:      &peer.ChaincodeID{Name: `mycc`, Version: `1.0`},
+			expID:   &peer.ChaincodeID{Name: `mycc`, Version: `1.0`, Path: `mycc`},
+			expType: peer.ChaincodeSpec_GOLANG,
+		},
+		{
+			name:    `signed cds`,
+			fetcher: fetcher.NewCDS(`testdata/mycc_signed.cds`),
+			id:      &peer.ChaincodeID{Name: `mycc`},
+			expID:   &peer.ChaincodeID{Name: `mycc`, Version: `1.0`, Path: `mycc`},
+			expType: peer.ChaincodeSpec_GOLANG,
+		},
+		{
+			name:    `cds without id`,
+			fetcher: fetcher.NewCDS(`testdata/mycc.cds`),
+			expID:   &peer.ChaincodeID{Name: `mycc`, Version: `1.0`, Path: `mycc`},
+			expType: peer.ChaincodeSpec_GOLANG,
+		},
+		{
+			name:    `cds name mismatch`,
+			fetcher: fetcher.NewCDS(`testdata/mycc.cds`),
+			id:      &peer.ChaincodeID{Name: `other`},
+			expErr:  `chaincode name mismatch: requested other, packaged mycc`,
+		},
+		{
+			name:    `cds version mismatch`,
+			fetcher: fetcher.NewCDS(`testdata/mycc.cds`),
+			id:      &peer.ChaincodeID{Name: `mycc`, Version: `2.0`},
+			expErr:  `chaincode version mismatch: requested 2.0, packaged 1.0`,
+		},
+		{
+			name:    `cds is not deployment spec`,
+			fetcher: fetcher.NewCDS(`testdata/mycc.tar.gz`),
+			id:      &peer.ChaincodeID{Name: `mycc`},
+			expErr:  `failed to unmarshal chaincode deployment spec`,
+		},
+		{
+			name:    `local`,
+			fetcher: fetcher.NewLocal(mockPlatform{payload: archive}),
+			id:      &peer.ChaincodeID{Name: `mycc`, Version: `1.0`, Path: `mycc`},
+			expID:   &peer.ChaincodeID{Name: `mycc`, Version: `1.0`, Path: `mycc`},
+			expType: peer.ChaincodeSpec_GOLANG,
+		},
+		{
+			name:    `local without id`,
+			fetcher: fetcher.NewLocal(mockPlatform{payload: archive}),
+			expErr:  `chaincode id is required`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			spec, err := tc.fetcher.Fetch(context.Background(), tc.id)
+			if tc.expErr != `` {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expID, spec.ChaincodeSpec.ChaincodeId)
+			require.Equal(t, tc.expType, spec.ChaincodeSpec.Type)
+			require.Equal(t, archive, spec.CodePackage)
+		})
+	}
+}
+
+func TestParseSignedCDS(t *testing.T) {
+	signed, err := ioutil.ReadFile(`testdata/mycc_signed.cds`)
+	require.NoError(t, err)
+
+	signedSpec, err := fetcher.ParseSignedCDS(signed)
+	require.NoError(t, err)
+	require.Len(t, signedSpec.OwnerEndorsements, 1)
+	require.NotEmpty(t, signedSpec.InstantiationPolicy)
+
+	unsigned, err := ioutil.ReadFile(`testdata/mycc.cds`)
+	require.NoError(t, err)
+	_, err = fetcher.ParseSignedCDS(unsigned)
+	require.Error(t, err)
+}
@@ -5,39 +5,46 @@ import (
 
 	"github.com/bogatyr285/hlf-sdk-go/api"
 	"github.com/hyperledger/fabric-protos-go/peer"
-	"github.com/hyperledger/fabric/core/chaincode/platforms"
+	"github.com/pkg/errors"
 )
 
+// Platform describes chaincode platform which is able to package chaincode source code, i.e. golang.Platform
+type Platform interface {
+	Name() string
+	GetDeploymentPayload(path string) ([]byte, error)
+}
+
 type localFetcher struct {
-	r  *platforms.Registry
-	pl platforms.Platform
+	pl Platform
 }
 
+// Fetch packages chaincode source code from GOPATH or module directory presented in id.Path
 func (f *localFetcher) Fetch(ctx context.Context, id *peer.ChaincodeID) (*peer.ChaincodeDeploymentSpec, error) {
-	panic(`implement me`)
-	//ccBytes, err := f.r.GetDeploymentPayload(f.pl.Name(), id.Path)
-	//if err != nil {
-	//	return nil, errors.Wrap(err, ``)
-	//}
-	//
-	//return &peer.ChaincodeDeploymentSpec{
-	//	ChaincodeSpec: &peer.ChaincodeSpec{
-	//		Type:        f.getTypeByPlatform(),
-	//		ChaincodeId: id,
-	//	},
-	//	CodePackage: ccBytes,
-	//	ExecEnv:     peer.ChaincodeDeploymentSpec_DOCKER,
-	//}, nil
+	if id == nil {
+		return nil, errors.New(`chaincode id is required`)
+	}
+
+	ccBytes, err := f.pl.GetDeploymentPayload(id.Path)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to get chaincode deployment payload`)
+	}
+
+	return &peer.ChaincodeDeploymentSpec{
+		ChaincodeSpec: &peer.ChaincodeSpec{
+			Type:        getTypeByPlatform(f.pl),
+			ChaincodeId: id,
+		},
+		CodePackage: ccBytes,
+	}, nil
 }
 
-func (f *localFetcher) getTypeByPlatform() peer.ChaincodeSpec_Type {
-	switch f.pl.Name() {
-	case peer.ChaincodeSpec_GOLANG.String():
-		return peer.ChaincodeSpec_GOLANG
+func getTypeByPlatform(pl Platform) peer.ChaincodeSpec_Type {
+	if ccType, ok := peer.ChaincodeSpec_Type_value[pl.Name()]; ok {
+		return peer.ChaincodeSpec_Type(ccType)
 	}
 	return peer.ChaincodeSpec_UNDEFINED
 }
 
-func NewLocal(platform platforms.Platform) api.CCFetcher {
-	return &localFetcher{r: platforms.NewRegistry(platform), pl: platform}
+func NewLocal(platform Platform) api.CCFetcher {
+	return &localFetcher{pl: platform}
 }
@@ -0,0 +1,240 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Derived from internal/configtxlator/update/update.go of github.com/hyperledger/fabric,
+// which is internal and can't be imported.
+
+package configtx
+
+import (
+	"bytes"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/pkg/errors"
+)
+
+// ComputeUpdate computes config update which transforms original config into updated one,
+// works the same way as configtxlator compute_update
+func ComputeUpdate(channelName string, original, updated *common.Config) (*common.ConfigUpdate, error) {
+	if original.ChannelGroup == nil {
+		return nil, errors.New(`no channel group included for original config`)
+	}
+
+	if updated.ChannelGroup == nil {
+		return nil, errors.New(`no channel group included for updated config`)
+	}
+
+	readSet, writeSet, groupUpdated := computeGroupUpdate(original.ChannelGroup, updated.ChannelGroup)
+	if !groupUpdated {
+		return nil, ErrNoDifferences
+	}
+
+	return &common.ConfigUpdate{
+		ChannelId: channelName,
+		ReadSet:   readSet,
+		WriteSet:  writeSet,
+	}, nil
+}
+
+func computePoliciesMapUpdate(original, updated map[string]*common.ConfigPolicy) (readSet, writeSet, sameSet map[string]*common.ConfigPolicy, updatedMembers bool) {
+	readSet = make(map[string]*common.ConfigPolicy)
+	writeSet = make(map[string]*common.ConfigPolicy)
+
+	// modified config goes into read/write sets, unchanged config is retained in case map membership changes
+	sameSet = make(map[string]*common.ConfigPolicy)
+
+	for policyName, originalPolicy := range original {
+		updatedPolicy, ok := updated[policyName]
+		if !ok {
+			updatedMembers = true
+			continue
+		}
+
+		if originalPolicy.ModPolicy == updatedPolicy.ModPolicy && proto.Equal(originalPolicy.Policy, updatedPolicy.Policy) {
+			sameSet[policyName] = &common.ConfigPolicy{
+				Version: originalPolicy.Version,
+			}
+			continue
+		}
+
+		writeSet[policyName] = &common.ConfigPolicy{
+			Version:   originalPolicy.Version + 1,
+			ModPolicy: updatedPolicy.ModPolicy,
+			Policy:    updatedPolicy.Policy,
+		}
+	}
+
+	for policyName, updatedPolicy := range updated {
+		if _, ok := original[policyName]; ok {
+			continue
+		}
+		updatedMembers = true
+		writeSet[policyName] = &common.ConfigPolicy{
+			Version:   0,
+			ModPolicy: updatedPolicy.ModPolicy,
+			Policy:    updatedPolicy.Policy,
+		}
+	}
+
+	return
+}
+
+func computeValuesMapUpdate(original, updated map[string]*common.ConfigValue) (readSet, writeSet, sameSet map[string]*common.ConfigValue, updatedMembers bool) {
+	readSet = make(map[string]*common.ConfigValue)
+	writeSet = make(map[string]*common.ConfigValue)
+
+	// modified config goes into read/write sets, unchanged config is retained in case map membership changes
+	sameSet = make(map[string]*common.ConfigValue)
+
+	for valueName, originalValue := range original {
+		updatedValue, ok := updated[valueName]
+		if !ok {
+			updatedMembers = true
+			continue
+		}
+
+		if originalValue.ModPolicy == updatedValue.ModPolicy && bytes.Equal(originalValue.Value, updatedValue.Value) {
+			sameSet[valueName] = &common.ConfigValue{
+				Version: originalValue.Version,
+			}
+			continue
+		}
+
+		writeSet[valueName] = &common.ConfigValue{
+			Version:   originalValue.Version + 1,
+			ModPolicy: updatedValue.ModPolicy,
+			Value:     updatedValue.Value,
+		}
+	}
+
+	for valueName, updatedValue := range updated {
+		if _, ok := original[valueName]; ok {
+			continue
+		}
+		updatedMembers = true
+		writeSet[valueName] = &common.ConfigValue{
+			Version:   0,
+			ModPolicy: updatedValue.ModPolicy,
+			Value:     updatedValue.Value,
+		}
+	}
+
+	return
+}
+
+func computeGroupsMapUpdate(original, updated map[string]*common.ConfigGroup) (readSet, writeSet, sameSet map[string]*common.ConfigGroup, updatedMembers bool) {
+	readSet = make(map[string]*common.ConfigGroup)
+	writeSet = make(map[string]*common.ConfigGroup)
+
+	// modified config goes into read/write sets, unchanged config is retained in case map membership changes
+	sameSet = make(map[string]*common.ConfigGroup)
+
+	for groupName, originalGroup := range original {
+		updatedGroup, ok := updated[groupName]
+		if !ok {
+			updatedMembers = true
+			continue
+		}
+
+		groupReadSet, groupWriteSet, groupUpdated := computeGroupUpdate(originalGroup, updatedGroup)
+		if !groupUpdated {
+			sameSet[groupName] = groupReadSet
+			continue
+		}
+
+		readSet[groupName] = groupReadSet
+		writeSet[groupName] = groupWriteSet
+	}
+
+	for groupName, updatedGroup := range updated {
+		if _, ok := original[groupName]; ok {
+			continue
+		}
+		updatedMembers = true
+		_, groupWriteSet, _ := computeGroupUpdate(newConfigGroup(), updatedGroup)
+		writeSet[groupName] = &common.ConfigGroup{
+			Version:   0,
+			ModPolicy: updatedGroup.ModPolicy,
+			Policies:  groupWriteSet.Policies,
+			Values:    groupWriteSet.Values,
+			Groups:    groupWriteSet.Groups,
+		}
+	}
+
+	return
+}
+
+func computeGroupUpdate(original, updated *common.ConfigGroup) (readSet, writeSet *common.ConfigGroup, updatedGroup bool) {
+	readSetPolicies, writeSetPolicies, sameSetPolicies, policiesMembersUpdated := computePoliciesMapUpdate(original.Policies, updated.Policies)
+	readSetValues, writeSetValues, sameSetValues, valuesMembersUpdated := computeValuesMapUpdate(original.Values, updated.Values)
+	readSetGroups, writeSetGroups, sameSetGroups, groupsMembersUpdated := computeGroupsMapUpdate(original.Groups, updated.Groups)
+
+	// neither members nor mod policy of group are changed
+	if !(policiesMembersUpdated || valuesMembersUpdated || groupsMembersUpdated || original.ModPolicy != updated.ModPolicy) {
+		// no modified entries in policies, values and groups
+		if len(readSetPolicies) == 0 &&
+			len(writeSetPolicies) == 0 &&
+			len(readSetValues) == 0 &&
+			len(writeSetValues) == 0 &&
+			len(readSetGroups) == 0 &&
+			len(writeSetGroups) == 0 {
+			return &common.ConfigGroup{
+				Version: original.Version,
+			}, &common.ConfigGroup{
+				Version: original.Version,
+			}, false
+		}
+
+		return &common.ConfigGroup{
+			Version:  original.Version,
+			Policies: readSetPolicies,
+			Values:   readSetValues,
+			Groups:   readSetGroups,
+		}, &common.ConfigGroup{
+			Version:  original.Version,
+			Policies: writeSetPolicies,
+			Values:   writeSetValues,
+			Groups:   writeSetGroups,
+		}, true
+	}
+
+	for k, samePolicy := range sameSetPolicies {
+		readSetPolicies[k] = samePolicy
+		writeSetPolicies[k] = samePolicy
+	}
+
+	for k, sameValue := range sameSetValues {
+		readSetValues[k] = sameValue
+		writeSetValues[k] = sameValue
+	}
+
+	for k, sameGroup := range sameSetGroups {
+		readSetGroups[k] = sameGroup
+		writeSetGroups[k] = sameGroup
+	}
+
+	return &common.ConfigGroup{
+		Version:  original.Version,
+		Policies: readSetPolicies,
+		Values:   readSetValues,
+		Groups:   readSetGroups,
+	}, &common.ConfigGroup{
+		Version:   original.Version + 1,
+		Policies:  writeSetPolicies,
+		Values:    writeSetValues,
+		Groups:    writeSetGroups,
+		ModPolicy: updated.ModPolicy,
+	}, true
+}
+
+func newConfigGroup() *common.ConfigGroup {
+	return &common.ConfigGroup{
+		Groups:   make(map[string]*common.ConfigGroup),
+		Values:   make(map[string]*common.ConfigValue),
+		Policies: make(map[string]*common.ConfigPolicy),
+	}
+}
@@ -0,0 +1,680 @@
+package configtx
+
+import (
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/pkg/errors"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+)
+
+const (
+	// EtcdRaftConsensusType is orderer consensus type which metadata is represented by Consensus.Consenters and Consensus.RaftOptions
+	EtcdRaftConsensusType = `etcdraft`
+)
+
+// ChannelConfig is editable representation of channel config
+// Values which are not represented here are left untouched by ChannelConfig.Config
+type ChannelConfig struct {
+	HashingAlgorithm               string
+	BlockDataHashingStructureWidth uint32
+	// OrdererAddresses are global orderer addresses, since fabric v1.4.2 organization endpoints are preferred
+	OrdererAddresses []string
+	Consortium       string
+	Capabilities     []string
+	Policies         map[string]Policy
+	// Application is nil for orderer system channel
+	Application *Application
+	// Orderer is nil in channel creation tx
+	Orderer *Orderer
+
+	original *common.Config
+}
+
+// Application is application part of channel config
+type Application struct {
+	// Organizations are keyed by config group name which is usually equal to MSP ID
+	Organizations map[string]*Organization
+	Capabilities  []string
+	// ACLs maps resource to policy reference, like `/Channel/Application/Readers`
+	ACLs     map[string]string
+	Policies map[string]Policy
+}
+
+// Orderer is orderer part of channel config
+type Orderer struct {
+	// Organizations are keyed by config group name which is usually equal to MSP ID
+	Organizations map[string]*Organization
+	Consensus     Consensus
+	BatchSize     BatchSize
+	BatchTimeout  time.Duration
+	Capabilities  []string
+	Policies      map[string]Policy
+}
+
+// Consensus is orderer consensus type with metadata
+type Consensus struct {
+	Type  string
+	State orderer.ConsensusType_State
+	// Consenters and RaftOptions are used by etcdraft consensus type
+	Consenters  []Consenter
+	RaftOptions *etcdraft.Options
+	// Metadata is raw metadata of other consensus types
+	Metadata []byte
+}
+
+// Consenter is member of etcdraft cluster
+type Consenter struct {
+	Address       api.HostPort
+	ClientTLSCert []byte
+	ServerTLSCert []byte
+}
+
+// BatchSize defines amount of transactions in block
+type BatchSize struct {
+	MaxMessageCount   uint32
+	AbsoluteMaxBytes  uint32
+	PreferredMaxBytes uint32
+}
+
+// Organization is application or orderer organization
+type Organization struct {
+	// MSP is nil if organization uses MSP other than FABRIC, such MSP definition is left untouched
+	MSP *msp.FabricMSPConfig
+	// AnchorPeers are used by application organizations
+	AnchorPeers []api.HostPort
+	// Endpoints are orderer addresses of orderer organizations
+	Endpoints []string
+	Policies  map[string]Policy
+}
+
+// ParseConfig parses channel config, e.g. received from CSCC.GetChannelConfig
+func ParseConfig(config *common.Config) (*ChannelConfig, error) {
+	if config.GetChannelGroup() == nil {
+		return nil, errors.New(`channel group not found in config`)
+	}
+	group := config.ChannelGroup
+
+	c := &ChannelConfig{original: proto.Clone(config).(*common.Config)}
+
+	hashingAlgorithm := new(common.HashingAlgorithm)
+	if err := unmarshalValue(group, channelconfig.HashingAlgorithmKey, hashingAlgorithm); err != nil {
+		return nil, err
+	}
+	c.HashingAlgorithm = hashingAlgorithm.Name
+
+	hashingStructure := new(common.BlockDataHashingStructure)
+	if err := unmarshalValue(group, channelconfig.BlockDataHashingStructureKey, hashingStructure); err != nil {
+		return nil, err
+	}
+	c.BlockDataHashingStructureWidth = hashingStructure.Width
+
+	ordererAddresses := new(common.OrdererAddresses)
+	if err := unmarshalValue(group, channelconfig.OrdererAddressesKey, ordererAddresses); err != nil {
+		return nil, err
+	}
+	c.OrdererAddresses = ordererAddresses.Addresses
+
+	consortium := new(common.Consortium)
+	if err := unmarshalValue(group, channelconfig.ConsortiumKey, consortium); err != nil {
+		return nil, err
+	}
+	c.Consortium = consortium.Name
+
+	var err error
+	if c.Capabilities, err = parseCapabilities(group); err != nil {
+		return nil, err
+	}
+	if c.Policies, err = parsePolicies(group); err != nil {
+		return nil, err
+	}
+
+	if appGroup, ok := group.Groups[channelconfig.ApplicationGroupKey]; ok {
+		if c.Application, err = parseApplication(appGroup); err != nil {
+			return nil, errors.WithMessage(err, `failed to parse application group`)
+		}
+	}
+
+	if ordGroup, ok := group.Groups[channelconfig.OrdererGroupKey]; ok {
+		if c.Orderer, err = parseOrderer(ordGroup); err != nil {
+			return nil, errors.WithMessage(err, `failed to parse orderer group`)
+		}
+	}
+
+	return c, nil
+}
+
+// OrdererEndpoints returns all orderer addresses, both global and from orderer organizations
+func (c *ChannelConfig) OrdererEndpoints() []string {
+	var endpoints []string
+	seen := make(map[string]bool)
+	add := func(addresses []string) {
+		for _, address := range addresses {
+			if !seen[address] {
+				seen[address] = true
+				endpoints = append(endpoints, address)
+			}
+		}
+	}
+
+	if c.Orderer != nil {
+		for _, name := range organizationNames(c.Orderer.Organizations) {
+			add(c.Orderer.Organizations[name].Endpoints)
+		}
+	}
+	add(c.OrdererAddresses)
+
+	return endpoints
+}
+
+// Config returns channel config with applied changes
+func (c *ChannelConfig) Config() (*common.Config, error) {
+	config := &common.Config{}
+	if c.original != nil {
+		config = proto.Clone(c.original).(*common.Config)
+	}
+	if config.ChannelGroup == nil {
+		config.ChannelGroup = newConfigGroup()
+		config.ChannelGroup.ModPolicy = channelconfig.AdminsPolicyKey
+	}
+	group := config.ChannelGroup
+
+	var hashingAlgorithm, hashingStructure, ordererAddresses, consortium proto.Message
+	if c.HashingAlgorithm != `` {
+		hashingAlgorithm = &common.HashingAlgorithm{Name: c.HashingAlgorithm}
+	}
+	if c.BlockDataHashingStructureWidth != 0 {
+		hashingStructure = &common.BlockDataHashingStructure{Width: c.BlockDataHashingStructureWidth}
+	}
+	if len(c.OrdererAddresses) > 0 {
+		ordererAddresses = &common.OrdererAddresses{Addresses: c.OrdererAddresses}
+	}
+	if c.Consortium != `` {
+		consortium = &common.Consortium{Name: c.Consortium}
+	}
+
+	if err := setValues(group, map[string]proto.Message{
+		channelconfig.HashingAlgorithmKey:          hashingAlgorithm,
+		channelconfig.BlockDataHashingStructureKey: hashingStructure,
+		channelconfig.ConsortiumKey:                consortium,
+		channelconfig.CapabilitiesKey:              capabilitiesValue(c.Capabilities),
+	}); err != nil {
+		return nil, err
+	}
+
+	// orderer addresses could be modified only by orderer admins
+	if err := setValue(group, channelconfig.OrdererAddressesKey, ordererAddresses,
+		`/`+channelconfig.ChannelGroupKey+`/`+channelconfig.OrdererGroupKey+`/`+channelconfig.AdminsPolicyKey); err != nil {
+		return nil, err
+	}
+
+	if err := setPolicies(group, c.Policies); err != nil {
+		return nil, err
+	}
+
+	if c.Application == nil {
+		delete(group.Groups, channelconfig.ApplicationGroupKey)
+	} else if err := c.Application.write(subGroup(group, channelconfig.ApplicationGroupKey)); err != nil {
+		return nil, errors.WithMessage(err, `failed to write application group`)
+	}
+
+	if c.Orderer == nil {
+		delete(group.Groups, channelconfig.OrdererGroupKey)
+	} else if err := c.Orderer.write(subGroup(group, channelconfig.OrdererGroupKey)); err != nil {
+		return nil, errors.WithMessage(err, `failed to write orderer group`)
+	}
+
+	return config, nil
+}
+
+// ConfigUpdate computes config update between parsed config and config with applied changes,
+// result could be used with util.ProceedChannelUpdate or NewUpdateFromConfigUpdate
+func (c *ChannelConfig) ConfigUpdate(channelName string) (*common.ConfigUpdate, error) {
+	if c.original == nil {
+		return nil, errors.New(`original config is not set, config must be parsed with ParseConfig`)
+	}
+
+	modified, err := c.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	return ComputeUpdate(channelName, c.original, modified)
+}
+
+// OrganizationByMSP returns application organization by MSP ID
+func (a *Application) OrganizationByMSP(mspID string) (*Organization, bool) {
+	return organizationByMSP(a.Organizations, mspID)
+}
+
+// OrganizationByMSP returns orderer organization by MSP ID
+func (o *Orderer) OrganizationByMSP(mspID string) (*Organization, bool) {
+	return organizationByMSP(o.Organizations, mspID)
+}
+
+func organizationByMSP(orgs map[string]*Organization, mspID string) (*Organization, bool) {
+	for _, name := range organizationNames(orgs) {
+		if orgs[name].MSP != nil && orgs[name].MSP.Name == mspID {
+			return orgs[name], true
+		}
+	}
+	// MSP ID is usually used as organization name
+	org, ok := orgs[mspID]
+	return org, ok
+}
+
+func parseApplication(group *common.ConfigGroup) (*Application, error) {
+	app := &Application{}
+
+	acls := new(peer.ACLs)
+	if err := unmarshalValue(group, channelconfig.ACLsKey, acls); err != nil {
+		return nil, err
+	}
+	if len(acls.Acls) > 0 {
+		app.ACLs = make(map[string]string, len(acls.Acls))
+		for resource, apiResource := range acls.Acls {
+			app.ACLs[resource] = apiResource.GetPolicyRef()
+		}
+	}
+
+	var err error
+	if app.Capabilities, err = parseCapabilities(group); err != nil {
+		return nil, err
+	}
+	if app.Policies, err = parsePolicies(group); err != nil {
+		return nil, err
+	}
+	if app.Organizations, err = parseOrganizations(group); err != nil {
+		return nil, err
+	}
+
+	return app, nil
+}
+
+func (a *Application) write(group *common.ConfigGroup) error {
+	var acls proto.Message
+	if len(a.ACLs) > 0 {
+		value := &peer.ACLs{Acls: make(map[string]*peer.APIResource, len(a.ACLs))}
+		for resource, policyRef := range a.ACLs {
+			value.Acls[resource] = &peer.APIResource{PolicyRef: policyRef}
+		}
+		acls = value
+	}
+
+	if err := setValues(group, map[string]proto.Message{
+		channelconfig.ACLsKey:         acls,
+		channelconfig.CapabilitiesKey: capabilitiesValue(a.Capabilities),
+	}); err != nil {
+		return err
+	}
+
+	if err := setPolicies(group, a.Policies); err != nil {
+		return err
+	}
+
+	return writeOrganizations(group, a.Organizations)
+}
+
+func parseOrderer(group *common.ConfigGroup) (*Orderer, error) {
+	ord := &Orderer{}
+
+	consensusType := new(orderer.ConsensusType)
+	if err := unmarshalValue(group, channelconfig.ConsensusTypeKey, consensusType); err != nil {
+		return nil, err
+	}
+	ord.Consensus = Consensus{Type: consensusType.Type, State: consensusType.State}
+
+	if consensusType.Type == EtcdRaftConsensusType {
+		metadata := new(etcdraft.ConfigMetadata)
+		if err := proto.Unmarshal(consensusType.Metadata, metadata); err != nil {
+			return nil, errors.Wrap(err, `failed to unmarshal etcdraft metadata`)
+		}
+		for _, consenter := range metadata.Consenters {
+			ord.Consensus.Consenters = append(ord.Consensus.Consenters, Consenter{
+				Address:       api.HostPort{Host: consenter.Host, Port: int(consenter.Port)},
+				ClientTLSCert: consenter.ClientTlsCert,
+				ServerTLSCert: consenter.ServerTlsCert,
+			})
+		}
+		ord.Consensus.RaftOptions = metadata.Options
+	} else {
+		ord.Consensus.Metadata = consensusType.Metadata
+	}
+
+	batchSize := new(orderer.BatchSize)
+	if err := unmarshalValue(group, channelconfig.BatchSizeKey, batchSize); err != nil {
+		return nil, err
+	}
+	ord.BatchSize = BatchSize{
+		MaxMessageCount:   batchSize.MaxMessageCount,
+		AbsoluteMaxBytes:  batchSize.AbsoluteMaxBytes,
+		PreferredMaxBytes: batchSize.PreferredMaxBytes,
+	}
+
+	batchTimeout := new(orderer.BatchTimeout)
+	if err := unmarshalValue(group, channelconfig.BatchTimeoutKey, batchTimeout); err != nil {
+		return nil, err
+	}
+	if batchTimeout.Timeout != `` {
+		timeout, err := time.ParseDuration(batchTimeout.Timeout)
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse batch timeout`)
+		}
+		ord.BatchTimeout = timeout
+	}
+
+	var err error
+	if ord.Capabilities, err = parseCapabilities(group); err != nil {
+		return nil, err
+	}
+	if ord.Policies, err = parsePolicies(group); err != nil {
+		return nil, err
+	}
+	if ord.Organizations, err = parseOrganizations(group); err != nil {
+		return nil, err
+	}
+
+	return ord, nil
+}
+
+func (o *Orderer) write(group *common.ConfigGroup) error {
+	consensusType := &orderer.ConsensusType{
+		Type:     o.Consensus.Type,
+		State:    o.Consensus.State,
+		Metadata: o.Consensus.Metadata,
+	}
+
+	if o.Consensus.Type == EtcdRaftConsensusType {
+		metadata := &etcdraft.ConfigMetadata{Options: o.Consensus.RaftOptions}
+		for _, consenter := range o.Consensus.Consenters {
+			metadata.Consenters = append(metadata.Consenters, &etcdraft.Consenter{
+				Host:          consenter.Address.Host,
+				Port:          uint32(consenter.Address.Port),
+				ClientTlsCert: consenter.ClientTLSCert,
+				ServerTlsCert: consenter.ServerTLSCert,
+			})
+		}
+
+		var err error
+		if consensusType.Metadata, err = proto.Marshal(metadata); err != nil {
+			return errors.Wrap(err, `failed to marshal etcdraft metadata`)
+		}
+	}
+
+	var batchTimeout proto.Message
+	if o.BatchTimeout > 0 {
+		batchTimeout = &orderer.BatchTimeout{Timeout: o.BatchTimeout.String()}
+	}
+
+	if err := setValues(group, map[string]proto.Message{
+		channelconfig.ConsensusTypeKey: consensusType,
+		channelconfig.BatchSizeKey: &orderer.BatchSize{
+			MaxMessageCount:   o.BatchSize.MaxMessageCount,
+			AbsoluteMaxBytes:  o.BatchSize.AbsoluteMaxBytes,
+			PreferredMaxBytes: o.BatchSize.PreferredMaxBytes,
+		},
+		channelconfig.BatchTimeoutKey: batchTimeout,
+		channelconfig.CapabilitiesKey: capabilitiesValue(o.Capabilities),
+	}); err != nil {
+		return err
+	}
+
+	if err := setPolicies(group, o.Policies); err != nil {
+		return err
+	}
+
+	return writeOrganizations(group, o.Organizations)
+}
+
+func parseOrganizations(group *common.ConfigGroup) (map[string]*Organization, error) {
+	orgs := make(map[string]*Organization, len(group.Groups))
+	for name, orgGroup := range group.Groups {
+		org, err := parseOrganization(orgGroup)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to parse organization %s", name)
+		}
+		orgs[name] = org
+	}
+	return orgs, nil
+}
+
+func parseOrganization(group *common.ConfigGroup) (*Organization, error) {
+	org := &Organization{}
+
+	mspConfig := new(msp.MSPConfig)
+	if err := unmarshalValue(group, channelconfig.MSPKey, mspConfig); err != nil {
+		return nil, err
+	}
+	if mspConfig.Type == 0 && len(mspConfig.Config) > 0 {
+		org.MSP = new(msp.FabricMSPConfig)
+		if err := proto.Unmarshal(mspConfig.Config, org.MSP); err != nil {
+			return nil, errors.Wrap(err, `failed to unmarshal fabric msp config`)
+		}
+	}
+
+	anchorPeers := new(peer.AnchorPeers)
+	if err := unmarshalValue(group, channelconfig.AnchorPeersKey, anchorPeers); err != nil {
+		return nil, err
+	}
+	for _, anchorPeer := range anchorPeers.AnchorPeers {
+		org.AnchorPeers = append(org.AnchorPeers, api.HostPort{Host: anchorPeer.Host, Port: int(anchorPeer.Port)})
+	}
+
+	endpoints := new(common.OrdererAddresses)
+	if err := unmarshalValue(group, channelconfig.EndpointsKey, endpoints); err != nil {
+		return nil, err
+	}
+	org.Endpoints = endpoints.Addresses
+
+	var err error
+	if org.Policies, err = parsePolicies(group); err != nil {
+		return nil, err
+	}
+
+	return org, nil
+}
+
+func writeOrganizations(group *common.ConfigGroup, orgs map[string]*Organization) error {
+	for name := range group.Groups {
+		if _, ok := orgs[name]; !ok {
+			delete(group.Groups, name)
+		}
+	}
+
+	for name, org := range orgs {
+		if err := org.write(subGroup(group, name)); err != nil {
+			return errors.WithMessagef(err, "failed to write organization %s", name)
+		}
+	}
+	return nil
+}
+
+func (o *Organization) write(group *common.ConfigGroup) error {
+	if o.MSP != nil {
+		mspConfigBytes, err := proto.Marshal(o.MSP)
+		if err != nil {
+			return errors.Wrap(err, `failed to marshal fabric msp config`)
+		}
+		if err = setValue(group, channelconfig.MSPKey, &msp.MSPConfig{Config: mspConfigBytes}, ``); err != nil {
+			return err
+		}
+	}
+
+	var anchorPeers, endpoints proto.Message
+	if len(o.AnchorPeers) > 0 {
+		value := &peer.AnchorPeers{}
+		for _, anchorPeer := range o.AnchorPeers {
+			value.AnchorPeers = append(value.AnchorPeers, &peer.AnchorPeer{Host: anchorPeer.Host, Port: int32(anchorPeer.Port)})
+		}
+		anchorPeers = value
+	}
+	if len(o.Endpoints) > 0 {
+		endpoints = &common.OrdererAddresses{Addresses: o.Endpoints}
+	}
+
+	if err := setValues(group, map[string]proto.Message{
+		channelconfig.AnchorPeersKey: anchorPeers,
+		channelconfig.EndpointsKey:   endpoints,
+	}); err != nil {
+		return err
+	}
+
+	return setPolicies(group, o.Policies)
+}
+
+func parseCapabilities(group *common.ConfigGroup) ([]string, error) {
+	capabilities := new(common.Capabilities)
+	if err := unmarshalValue(group, channelconfig.CapabilitiesKey, capabilities); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range capabilities.Capabilities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func capabilitiesValue(capabilities []string) proto.Message {
+	if len(capabilities) == 0 {
+		return nil
+	}
+	value := &common.Capabilities{Capabilities: make(map[string]*common.Capability, len(capabilities))}
+	for _, capability := range capabilities {
+		value.Capabilities[capability] = &common.Capability{}
+	}
+	return value
+}
+
+func parsePolicies(group *common.ConfigGroup) (map[string]Policy, error) {
+	policies := make(map[string]Policy, len(group.Policies))
+	for name, configPolicy := range group.Policies {
+		policy, err := parsePolicy(configPolicy)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to parse policy %s", name)
+		}
+		policies[name] = policy
+	}
+	return policies, nil
+}
+
+func setPolicies(group *common.ConfigGroup, policies map[string]Policy) error {
+	if group.Policies == nil {
+		group.Policies = make(map[string]*common.ConfigPolicy)
+	}
+
+	for name := range group.Policies {
+		if _, ok := policies[name]; !ok {
+			delete(group.Policies, name)
+		}
+	}
+
+	for name, policy := range policies {
+		// existing policy is kept as is if it is not changed, because DSL representation is not bijective
+		if existing, ok := group.Policies[name]; ok {
+			if parsed, err := parsePolicy(existing); err == nil &&
+				parsed.Type == policy.Type && parsed.Rule == policy.Rule &&
+				(policy.ModPolicy == `` || policy.ModPolicy == existing.ModPolicy) {
+				continue
+			}
+		}
+
+		configPolicy, err := policy.configPolicy()
+		if err != nil {
+			return errors.WithMessagef(err, "failed to make policy %s", name)
+		}
+		group.Policies[name] = configPolicy
+	}
+	return nil
+}
+
+// unmarshalValue unmarshals config value if it exists, otherwise value is left empty
+func unmarshalValue(group *common.ConfigGroup, key string, value proto.Message) error {
+	configValue, ok := group.Values[key]
+	if !ok {
+		return nil
+	}
+	if err := proto.Unmarshal(configValue.Value, value); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal %s value", key)
+	}
+	return nil
+}
+
+// setValues sets values with Admins mod policy by default, nil value removes value from group
+func setValues(group *common.ConfigGroup, values map[string]proto.Message) error {
+	for key, value := range values {
+		if err := setValue(group, key, value, ``); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setValue(group *common.ConfigGroup, key string, value proto.Message, modPolicy string) error {
+	if group.Values == nil {
+		group.Values = make(map[string]*common.ConfigValue)
+	}
+
+	if value == nil {
+		delete(group.Values, key)
+		return nil
+	}
+
+	existing, ok := group.Values[key]
+	if ok {
+		// keep original bytes if value is not changed to avoid redundant config updates
+		current := proto.Clone(value)
+		current.Reset()
+		if err := proto.Unmarshal(existing.Value, current); err == nil && proto.Equal(current, value) {
+			return nil
+		}
+	}
+
+	valueBytes, err := proto.Marshal(value)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %s value", key)
+	}
+
+	configValue := &common.ConfigValue{Value: valueBytes, ModPolicy: modPolicy}
+	switch {
+	case ok:
+		configValue.Version, configValue.ModPolicy = existing.Version, existing.ModPolicy
+	case modPolicy == ``:
+		configValue.ModPolicy = channelconfig.AdminsPolicyKey
+	}
+
+	group.Values[key] = configValue
+	return nil
+}
+
+// subGroup returns existing sub group or creates new one with Admins mod policy
+func subGroup(group *common.ConfigGroup, name string) *common.ConfigGroup {
+	if group.Groups == nil {
+		group.Groups = make(map[string]*common.ConfigGroup)
+	}
+	sub, ok := group.Groups[name]
+	if !ok {
+		sub = newConfigGroup()
+		sub.ModPolicy = channelconfig.AdminsPolicyKey
+		group.Groups[name] = sub
+	}
+	return sub
+}
+
+func organizationNames(orgs map[string]*Organization) []string {
+	names := make([]string, 0, len(orgs))
+	for name := range orgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
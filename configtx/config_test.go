@@ -0,0 +1,105 @@
+package configtx_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/configtx"
+	"github.com/bogatyr285/hlf-sdk-go/util"
+)
+
+func TestChannelConfig(t *testing.T) {
+	original := channelConfig(t, `org1msp`, `org2msp`, `org3msp`)
+
+	config, err := configtx.ParseConfig(original)
+	require.NoError(t, err)
+
+	require.Equal(t, `SHA256`, config.HashingAlgorithm)
+	require.Equal(t, []string{`orderer:7050`}, config.OrdererAddresses)
+	require.Nil(t, config.Orderer)
+	require.Equal(t, configtx.Policy{
+		Type:      configtx.ImplicitMetaPolicyType,
+		Rule:      `MAJORITY Admins`,
+		ModPolicy: channelconfig.AdminsPolicyKey,
+	}, config.Policies[channelconfig.AdminsPolicyKey])
+
+	require.Len(t, config.Application.Organizations, 3)
+	org1, ok := config.Application.OrganizationByMSP(`org1msp`)
+	require.True(t, ok)
+	require.Equal(t, `org1msp`, org1.MSP.Name)
+	require.NotEmpty(t, org1.MSP.RootCerts)
+	require.Equal(t, configtx.Policy{
+		Type:      configtx.SignaturePolicyType,
+		Rule:      `OR('org1msp.member')`,
+		ModPolicy: channelconfig.AdminsPolicyKey,
+	}, org1.Policies[channelconfig.AdminsPolicyKey])
+
+	// config without changes is serialized as is
+	unchanged, err := config.Config()
+	require.NoError(t, err)
+	require.True(t, proto.Equal(original, unchanged))
+
+	_, err = config.ConfigUpdate(`channel`)
+	require.Equal(t, configtx.ErrNoDifferences, err)
+
+	org1.AnchorPeers = []api.HostPort{{Host: `peer0.org1`, Port: 7051}}
+	org1.Policies[channelconfig.WritersPolicyKey] = configtx.Policy{
+		Type: configtx.SignaturePolicyType,
+		Rule: `OR('org1msp.admin', 'org1msp.client')`,
+	}
+	delete(config.Application.Organizations, `org3msp`)
+	config.Application.ACLs = map[string]string{`qscc/GetChainInfo`: `/Channel/Application/Readers`}
+	config.Application.Capabilities = []string{`V2_0`}
+	config.Orderer = &configtx.Orderer{
+		Organizations: map[string]*configtx.Organization{
+			`orderer`: {Endpoints: []string{`orderer1:7050`, `orderer:7050`}},
+		},
+		Consensus: configtx.Consensus{
+			Type: configtx.EtcdRaftConsensusType,
+			Consenters: []configtx.Consenter{{
+				Address:       api.HostPort{Host: `orderer1`, Port: 7050},
+				ClientTLSCert: []byte(`client`),
+				ServerTLSCert: []byte(`server`),
+			}},
+			RaftOptions: &etcdraft.Options{TickInterval: `500ms`, ElectionTick: 10},
+		},
+		BatchSize:    configtx.BatchSize{MaxMessageCount: 10, AbsoluteMaxBytes: 1 << 20, PreferredMaxBytes: 1 << 19},
+		BatchTimeout: 2 * time.Second,
+		Policies:     map[string]configtx.Policy{},
+	}
+
+	modified, err := config.Config()
+	require.NoError(t, err)
+
+	reparsed, err := configtx.ParseConfig(modified)
+	require.NoError(t, err)
+
+	require.Len(t, reparsed.Application.Organizations, 2)
+	reparsedOrg1, _ := reparsed.Application.OrganizationByMSP(`org1msp`)
+	require.Equal(t, org1.AnchorPeers, reparsedOrg1.AnchorPeers)
+	require.Equal(t, `OR('org1msp.admin', 'org1msp.client')`, reparsedOrg1.Policies[channelconfig.WritersPolicyKey].Rule)
+	require.Equal(t, channelconfig.AdminsPolicyKey, reparsedOrg1.Policies[channelconfig.WritersPolicyKey].ModPolicy)
+	require.Equal(t, config.Application.ACLs, reparsed.Application.ACLs)
+	require.Equal(t, config.Application.Capabilities, reparsed.Application.Capabilities)
+	require.Equal(t, config.Orderer.Consensus.Consenters, reparsed.Orderer.Consensus.Consenters)
+	require.True(t, proto.Equal(config.Orderer.Consensus.RaftOptions, reparsed.Orderer.Consensus.RaftOptions))
+	require.Equal(t, config.Orderer.BatchSize, reparsed.Orderer.BatchSize)
+	require.Equal(t, config.Orderer.BatchTimeout, reparsed.Orderer.BatchTimeout)
+
+	require.Equal(t, []string{`orderer1:7050`, `orderer:7050`}, reparsed.OrdererEndpoints())
+	addresses, err := util.GetOrdererHostsFromChannelConfig(modified)
+	require.NoError(t, err)
+	require.Equal(t, []string{`orderer1:7050`, `orderer:7050`}, addresses)
+
+	update, err := config.ConfigUpdate(`channel`)
+	require.NoError(t, err)
+	require.Equal(t, `channel`, update.ChannelId)
+	require.Contains(t, update.WriteSet.Groups, channelconfig.OrdererGroupKey)
+	require.NotContains(t, update.WriteSet.Groups[channelconfig.ApplicationGroupKey].Groups, `org3msp`)
+}
@@ -0,0 +1,134 @@
+package configtx
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrProfileApplicationNotFound = errors.New(`profile must contain application section`)
+	ErrProfileConsortiumNotFound  = errors.New(`profile must contain consortium`)
+)
+
+// GenesisBlock creates genesis block of channel from profile,
+// block could be used to join orderers with channel participation API and to join peers
+func GenesisBlock(channelName string, profile *Profile) (*common.Block, error) {
+	if channelName == `` {
+		return nil, ErrEmptyChannelName
+	}
+
+	channelConfig, err := profile.ChannelConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := channelConfig.Config()
+	if err != nil {
+		return nil, errors.WithMessage(err, `failed to make channel config`)
+	}
+
+	return NewConfigBlock(channelName, 0, config)
+}
+
+// NewConfigBlock creates unsigned config block with presented number, block is not chained to previous one
+func NewConfigBlock(channelName string, number uint64, config *common.Config) (*common.Block, error) {
+	nonce, err := protoutil.CreateNonce()
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to create nonce`)
+	}
+
+	channelHeader := protoutil.MakeChannelHeader(common.HeaderType_CONFIG, 0, channelName, 0)
+	signatureHeader := protoutil.MakeSignatureHeader(nil, nonce)
+	protoutil.SetTxID(channelHeader, signatureHeader)
+
+	configEnvelope, err := proto.Marshal(&common.ConfigEnvelope{Config: config})
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to marshal config envelope`)
+	}
+
+	payload, err := proto.Marshal(&common.Payload{
+		Header: protoutil.MakePayloadHeader(channelHeader, signatureHeader),
+		Data:   configEnvelope,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to marshal payload`)
+	}
+
+	envelope, err := proto.Marshal(&common.Envelope{Payload: payload})
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to marshal envelope`)
+	}
+
+	block := protoutil.NewBlock(number, nil)
+	block.Data = &common.BlockData{Data: [][]byte{envelope}}
+	block.Header.DataHash = protoutil.BlockDataHash(block.Data)
+
+	lastConfig, err := proto.Marshal(&common.LastConfig{Index: number})
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to marshal last config`)
+	}
+
+	if block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG], err = proto.Marshal(
+		&common.Metadata{Value: lastConfig}); err != nil {
+		return nil, errors.Wrap(err, `failed to marshal last config metadata`)
+	}
+
+	ordererMetadata, err := proto.Marshal(&common.OrdererBlockMetadata{LastConfig: &common.LastConfig{Index: number}})
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to marshal orderer block metadata`)
+	}
+
+	if block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES], err = proto.Marshal(
+		&common.Metadata{Value: ordererMetadata}); err != nil {
+		return nil, errors.Wrap(err, `failed to marshal signatures metadata`)
+	}
+
+	return block, nil
+}
+
+// NewChannelCreateUpdate creates unsigned channel creation config update from profile with consortium
+// for networks with orderer system channel. Transaction is made with Update.Envelope after signing by organization admins
+func NewChannelCreateUpdate(channelName string, profile *Profile) (*Update, error) {
+	if profile.Application == nil {
+		return nil, ErrProfileApplicationNotFound
+	}
+	if profile.Consortium == `` {
+		return nil, ErrProfileConsortiumNotFound
+	}
+
+	channelConfig, err := profile.ChannelConfig()
+	if err != nil {
+		return nil, err
+	}
+	// consortium is added to read and write sets separately like in configtxgen
+	channelConfig.Consortium = ``
+
+	updated, err := channelConfig.Config()
+	if err != nil {
+		return nil, errors.WithMessage(err, `failed to make channel config`)
+	}
+
+	// template contains organizations of consortium without application values and policies
+	template := proto.Clone(updated).(*common.Config)
+	application := template.ChannelGroup.Groups[channelconfig.ApplicationGroupKey]
+	application.Values = nil
+	application.Policies = nil
+
+	configUpdate, err := ComputeUpdate(channelName, template, updated)
+	if err != nil {
+		return nil, err
+	}
+
+	consortium, err := proto.Marshal(&common.Consortium{Name: profile.Consortium})
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to marshal consortium`)
+	}
+
+	configUpdate.ReadSet.Values[channelconfig.ConsortiumKey] = &common.ConfigValue{}
+	configUpdate.WriteSet.Values[channelconfig.ConsortiumKey] = &common.ConfigValue{Value: consortium}
+
+	return NewUpdateFromConfigUpdate(configUpdate)
+}
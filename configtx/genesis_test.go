@@ -0,0 +1,95 @@
+package configtx_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/configtx"
+	"github.com/bogatyr285/hlf-sdk-go/crypto"
+	"github.com/bogatyr285/hlf-sdk-go/crypto/ecdsa"
+	"github.com/bogatyr285/hlf-sdk-go/identity"
+	"github.com/bogatyr285/hlf-sdk-go/util"
+)
+
+func TestGenesisBlock(t *testing.T) {
+	profile, err := configtx.LoadProfile(`testdata/configtx.yaml`, `ApplicationGenesis`)
+	require.NoError(t, err)
+
+	require.Equal(t, configtx.ByteSize(99*1024*1024), profile.Orderer.BatchSize.AbsoluteMaxBytes)
+	require.Equal(t, time.Second, profile.Orderer.BatchTimeout)
+
+	block, err := configtx.GenesisBlock(`channel`, profile)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), block.Header.Number)
+	require.Equal(t, protoutil.BlockDataHash(block.Data), block.Header.DataHash)
+
+	config, err := util.GetConfigFromBlock(block)
+	require.NoError(t, err)
+
+	// config is valid for fabric
+	bundle, err := channelconfig.NewBundle(`channel`, config, factory.GetDefault())
+	require.NoError(t, err)
+
+	ordererConfig, ok := bundle.OrdererConfig()
+	require.True(t, ok)
+	require.Equal(t, configtx.EtcdRaftConsensusType, ordererConfig.ConsensusType())
+	require.Equal(t, uint32(10), ordererConfig.BatchSize().MaxMessageCount)
+	require.Equal(t, uint32(512*1024), ordererConfig.BatchSize().PreferredMaxBytes)
+	require.Equal(t, []string{`orderer.example.com:7050`}, ordererConfig.Organizations()[`OrdererOrg`].Endpoints())
+
+	appConfig, ok := bundle.ApplicationConfig()
+	require.True(t, ok)
+	require.True(t, appConfig.Capabilities().LifecycleV20())
+	require.Len(t, appConfig.Organizations(), 2)
+
+	parsed, err := configtx.ParseConfig(config)
+	require.NoError(t, err)
+	require.Equal(t, uint32(20*1024*1024), parsed.Orderer.Consensus.RaftOptions.SnapshotIntervalSize)
+	require.Equal(t, `500ms`, parsed.Orderer.Consensus.RaftOptions.TickInterval)
+	org1, _ := parsed.Application.OrganizationByMSP(`org1msp`)
+	require.Equal(t, []api.HostPort{{Host: `peer0.org1.example.com`, Port: 7051}}, org1.AnchorPeers)
+	require.Equal(t, `OR('org1msp.member')`, org1.Policies[channelconfig.ReadersPolicyKey].Rule)
+}
+
+func TestNewChannelCreateUpdate(t *testing.T) {
+	profile, err := configtx.LoadProfile(`testdata/configtx.yaml`, `TwoOrgsChannel`)
+	require.NoError(t, err)
+
+	update, err := configtx.NewChannelCreateUpdate(`channel`, profile)
+	require.NoError(t, err)
+
+	cs, err := crypto.GetSuite(ecdsa.Module, ecdsa.DefaultOpts)
+	require.NoError(t, err)
+	id, err := identity.NewMSPIdentityFromPath(`org1msp`, mspPath)
+	require.NoError(t, err)
+	signer := id.GetSigningIdentity(cs)
+
+	require.NoError(t, update.Sign(signer))
+	envelope, err := update.Envelope(signer)
+	require.NoError(t, err)
+
+	envUpdate, err := protoutil.EnvelopeToConfigUpdate(envelope)
+	require.NoError(t, err)
+	require.Len(t, envUpdate.Signatures, 1)
+
+	configUpdate := new(common.ConfigUpdate)
+	require.NoError(t, proto.Unmarshal(envUpdate.ConfigUpdate, configUpdate))
+	require.Equal(t, `channel`, configUpdate.ChannelId)
+	require.Contains(t, configUpdate.ReadSet.Values, channelconfig.ConsortiumKey)
+	require.Contains(t, configUpdate.WriteSet.Values, channelconfig.ConsortiumKey)
+
+	application := configUpdate.WriteSet.Groups[channelconfig.ApplicationGroupKey]
+	require.Equal(t, uint64(1), application.Version)
+	require.Contains(t, application.Groups, `org1msp`)
+	require.Contains(t, application.Groups, `partnermsp`)
+	require.Contains(t, application.Values, channelconfig.ACLsKey)
+	require.Contains(t, application.Policies, channelconfig.AdminsPolicyKey)
+}
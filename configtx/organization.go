@@ -0,0 +1,111 @@
+package configtx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	fabricMsp "github.com/hyperledger/fabric/msp"
+	"github.com/pkg/errors"
+)
+
+const (
+	// EndorsementPolicyKey is name of organization policy used by default chaincode endorsement policy
+	EndorsementPolicyKey = `Endorsement`
+)
+
+var (
+	ErrApplicationNotFound  = errors.New(`application group not found in channel config`)
+	ErrOrganizationExists   = errors.New(`organization already exists in channel config`)
+	ErrOrganizationNotFound = errors.New(`organization not found in channel config`)
+)
+
+// NewOrganization creates application organization from MSP directory with cacerts, intermediatecerts, tlscacerts,
+// admincerts and config.yaml. Organization has default Readers, Writers, Admins and Endorsement policies
+func NewOrganization(mspID, mspDir string) (*Organization, error) {
+	mspConfig, err := fabricMsp.GetVerifyingMspConfig(mspDir, mspID, fabricMsp.ProviderTypeToString(fabricMsp.FABRIC))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load msp from %s", mspDir)
+	}
+
+	fabricMSPConfig := new(msp.FabricMSPConfig)
+	if err = proto.Unmarshal(mspConfig.Config, fabricMSPConfig); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal fabric msp config`)
+	}
+
+	return &Organization{
+		MSP:      fabricMSPConfig,
+		Policies: DefaultOrganizationPolicies(mspID, fabricMSPConfig.GetFabricNodeOus().GetEnable()),
+	}, nil
+}
+
+// DefaultOrganizationPolicies returns organization policies like in fabric samples,
+// peer and client roles are used if MSP has NodeOUs enabled
+func DefaultOrganizationPolicies(mspID string, nodeOUs bool) map[string]Policy {
+	signedBy := func(roles ...string) Policy {
+		principals := make([]string, len(roles))
+		for i, role := range roles {
+			principals[i] = fmt.Sprintf("'%s.%s'", mspID, role)
+		}
+		return Policy{
+			Type:      SignaturePolicyType,
+			Rule:      fmt.Sprintf("OR(%s)", strings.Join(principals, `, `)),
+			ModPolicy: channelconfig.AdminsPolicyKey,
+		}
+	}
+
+	if !nodeOUs {
+		return map[string]Policy{
+			channelconfig.ReadersPolicyKey: signedBy(`member`),
+			channelconfig.WritersPolicyKey: signedBy(`member`),
+			channelconfig.AdminsPolicyKey:  signedBy(`admin`),
+			EndorsementPolicyKey:           signedBy(`member`),
+		}
+	}
+
+	return map[string]Policy{
+		channelconfig.ReadersPolicyKey: signedBy(`admin`, `peer`, `client`),
+		channelconfig.WritersPolicyKey: signedBy(`admin`, `client`),
+		channelconfig.AdminsPolicyKey:  signedBy(`admin`),
+		EndorsementPolicyKey:           signedBy(`peer`),
+	}
+}
+
+// AddOrganization adds application organization, MSP ID is used as organization name
+func (c *ChannelConfig) AddOrganization(org *Organization) error {
+	if c.Application == nil {
+		return ErrApplicationNotFound
+	}
+	if org.MSP == nil {
+		return errors.New(`organization msp is not set`)
+	}
+
+	mspID := org.MSP.Name
+	if _, ok := c.Application.OrganizationByMSP(mspID); ok {
+		return errors.Wrap(ErrOrganizationExists, mspID)
+	}
+
+	if c.Application.Organizations == nil {
+		c.Application.Organizations = make(map[string]*Organization)
+	}
+	c.Application.Organizations[mspID] = org
+	return nil
+}
+
+// RemoveOrganization removes application organization by MSP ID
+func (c *ChannelConfig) RemoveOrganization(mspID string) error {
+	if c.Application == nil {
+		return ErrApplicationNotFound
+	}
+
+	for name, org := range c.Application.Organizations {
+		if (org.MSP != nil && org.MSP.Name == mspID) || (org.MSP == nil && name == mspID) {
+			delete(c.Application.Organizations, name)
+			return nil
+		}
+	}
+
+	return errors.Wrap(ErrOrganizationNotFound, mspID)
+}
@@ -0,0 +1,47 @@
+package configtx_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bogatyr285/hlf-sdk-go/configtx"
+)
+
+func TestOrganization(t *testing.T) {
+	partner, err := configtx.NewOrganization(`partnermsp`, `testdata/partnermsp`)
+	require.NoError(t, err)
+
+	require.Equal(t, `partnermsp`, partner.MSP.Name)
+	require.Len(t, partner.MSP.RootCerts, 1)
+	require.Len(t, partner.MSP.TlsRootCerts, 1)
+	require.True(t, partner.MSP.FabricNodeOus.Enable)
+	require.Equal(t, `OR('partnermsp.admin', 'partnermsp.peer', 'partnermsp.client')`,
+		partner.Policies[channelconfig.ReadersPolicyKey].Rule)
+	require.Equal(t, `OR('partnermsp.peer')`, partner.Policies[configtx.EndorsementPolicyKey].Rule)
+
+	original := channelConfig(t, `org1msp`, `org2msp`)
+	config, err := configtx.ParseConfig(original)
+	require.NoError(t, err)
+
+	require.NoError(t, config.AddOrganization(partner))
+	require.Error(t, config.AddOrganization(partner))
+
+	update, err := config.ConfigUpdate(`channel`)
+	require.NoError(t, err)
+	require.Contains(t, update.WriteSet.Groups[channelconfig.ApplicationGroupKey].Groups, `partnermsp`)
+
+	modified, err := config.Config()
+	require.NoError(t, err)
+
+	reparsed, err := configtx.ParseConfig(modified)
+	require.NoError(t, err)
+	added, ok := reparsed.Application.OrganizationByMSP(`partnermsp`)
+	require.True(t, ok)
+	require.Equal(t, partner.Policies, added.Policies)
+
+	require.NoError(t, reparsed.RemoveOrganization(`org2msp`))
+	require.Error(t, reparsed.RemoveOrganization(`org2msp`))
+	require.Len(t, reparsed.Application.Organizations, 2)
+}
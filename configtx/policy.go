@@ -0,0 +1,146 @@
+package configtx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/policydsl"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ImplicitMetaPolicyType is type of policy referencing sub policies, rule is like `MAJORITY Admins`
+	ImplicitMetaPolicyType = `ImplicitMeta`
+	// SignaturePolicyType is type of policy with signature policy DSL rule, like `OR('Org1MSP.admin')`
+	SignaturePolicyType = `Signature`
+)
+
+// Policy is config policy in the form used by configtx.yaml
+type Policy struct {
+	Type string
+	Rule string
+	// ModPolicy is policy required to modify this policy, Admins by default
+	ModPolicy string
+}
+
+func parsePolicy(configPolicy *common.ConfigPolicy) (Policy, error) {
+	policy := Policy{ModPolicy: configPolicy.ModPolicy}
+
+	switch common.Policy_PolicyType(configPolicy.GetPolicy().GetType()) {
+	case common.Policy_IMPLICIT_META:
+		implicitMeta := new(common.ImplicitMetaPolicy)
+		if err := proto.Unmarshal(configPolicy.Policy.Value, implicitMeta); err != nil {
+			return policy, errors.Wrap(err, `failed to unmarshal implicit meta policy`)
+		}
+		policy.Type = ImplicitMetaPolicyType
+		policy.Rule = fmt.Sprintf("%s %s", implicitMeta.Rule, implicitMeta.SubPolicy)
+
+	case common.Policy_SIGNATURE:
+		envelope := new(common.SignaturePolicyEnvelope)
+		if err := proto.Unmarshal(configPolicy.Policy.Value, envelope); err != nil {
+			return policy, errors.Wrap(err, `failed to unmarshal signature policy`)
+		}
+		rule, err := signaturePolicyRule(envelope.Rule, envelope.Identities)
+		if err != nil {
+			return policy, err
+		}
+		policy.Type = SignaturePolicyType
+		policy.Rule = rule
+
+	default:
+		return policy, errors.Errorf("unsupported policy type %d", configPolicy.GetPolicy().GetType())
+	}
+
+	return policy, nil
+}
+
+func (p Policy) configPolicy() (*common.ConfigPolicy, error) {
+	var (
+		policyType common.Policy_PolicyType
+		value      proto.Message
+	)
+
+	switch p.Type {
+	case ImplicitMetaPolicyType:
+		parts := strings.Fields(p.Rule)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid implicit meta policy rule %q", p.Rule)
+		}
+		rule, ok := common.ImplicitMetaPolicy_Rule_value[parts[0]]
+		if !ok {
+			return nil, errors.Errorf("unknown implicit meta policy rule %q", parts[0])
+		}
+		policyType = common.Policy_IMPLICIT_META
+		value = &common.ImplicitMetaPolicy{Rule: common.ImplicitMetaPolicy_Rule(rule), SubPolicy: parts[1]}
+
+	case SignaturePolicyType:
+		envelope, err := policydsl.FromString(p.Rule)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse signature policy %q", p.Rule)
+		}
+		policyType = common.Policy_SIGNATURE
+		value = envelope
+
+	default:
+		return nil, errors.Errorf("unknown policy type %q", p.Type)
+	}
+
+	valueBytes, err := proto.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to marshal policy`)
+	}
+
+	modPolicy := p.ModPolicy
+	if modPolicy == `` {
+		modPolicy = channelconfig.AdminsPolicyKey
+	}
+
+	return &common.ConfigPolicy{
+		Policy:    &common.Policy{Type: int32(policyType), Value: valueBytes},
+		ModPolicy: modPolicy,
+	}, nil
+}
+
+// signaturePolicyRule converts signature policy to DSL accepted by policydsl.FromString
+func signaturePolicyRule(rule *common.SignaturePolicy, identities []*msp.MSPPrincipal) (string, error) {
+	switch r := rule.GetType().(type) {
+	case *common.SignaturePolicy_SignedBy:
+		if r.SignedBy < 0 || int(r.SignedBy) >= len(identities) {
+			return ``, errors.Errorf("identity index %d out of range", r.SignedBy)
+		}
+		principal := identities[r.SignedBy]
+		if principal.PrincipalClassification != msp.MSPPrincipal_ROLE {
+			return ``, errors.Errorf("unsupported principal classification %s", principal.PrincipalClassification)
+		}
+		role := new(msp.MSPRole)
+		if err := proto.Unmarshal(principal.Principal, role); err != nil {
+			return ``, errors.Wrap(err, `failed to unmarshal msp role`)
+		}
+		return fmt.Sprintf("'%s.%s'", role.MspIdentifier, strings.ToLower(role.Role.String())), nil
+
+	case *common.SignaturePolicy_NOutOf_:
+		rules := make([]string, len(r.NOutOf.Rules))
+		for i, subRule := range r.NOutOf.Rules {
+			var err error
+			if rules[i], err = signaturePolicyRule(subRule, identities); err != nil {
+				return ``, err
+			}
+		}
+
+		switch int(r.NOutOf.N) {
+		case 1:
+			return fmt.Sprintf("OR(%s)", strings.Join(rules, `, `)), nil
+		case len(rules):
+			return fmt.Sprintf("AND(%s)", strings.Join(rules, `, `)), nil
+		default:
+			return fmt.Sprintf("OutOf(%d, %s)", r.NOutOf.N, strings.Join(rules, `, `)), nil
+		}
+
+	default:
+		return ``, errors.New(`unknown signature policy rule`)
+	}
+}
@@ -0,0 +1,396 @@
+package configtx
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+)
+
+const (
+	// BlockValidationPolicyKey is orderer policy used to validate blocks
+	BlockValidationPolicyKey = `BlockValidation`
+
+	defaultHashingAlgorithm      = `SHA256`
+	defaultBlockDataHashingWidth = 4294967295
+	defaultBatchTimeout          = 2 * time.Second
+	defaultMaxMessageCount       = 500
+	defaultAbsoluteMaxBytes      = 10 * 1024 * 1024
+	defaultPreferredMaxBytes     = 2 * 1024 * 1024
+	defaultTickInterval          = `500ms`
+	defaultElectionTick          = 10
+	defaultHeartbeatTick         = 1
+	defaultMaxInflightBlocks     = 5
+	defaultSnapshotIntervalSize  = 16 * 1024 * 1024
+	implicitMetaAnyReaders       = `ANY Readers`
+	implicitMetaAnyWriters       = `ANY Writers`
+	implicitMetaMajorityAdmins   = `MAJORITY Admins`
+)
+
+var (
+	ErrProfileOrgMSPDirNotFound = errors.New(`organization MSPDir is not set`)
+)
+
+// Profile is channel profile in configtx.yaml format
+type Profile struct {
+	Consortium   string                   `yaml:"Consortium"`
+	Policies     map[string]ProfilePolicy `yaml:"Policies"`
+	Capabilities map[string]bool          `yaml:"Capabilities"`
+	Application  *ProfileApplication      `yaml:"Application"`
+	Orderer      *ProfileOrderer          `yaml:"Orderer"`
+}
+
+// ProfilePolicy is policy definition in configtx.yaml format
+type ProfilePolicy struct {
+	Type string `yaml:"Type"`
+	Rule string `yaml:"Rule"`
+}
+
+// ProfileOrganization is organization definition in configtx.yaml format
+type ProfileOrganization struct {
+	Name             string                   `yaml:"Name"`
+	ID               string                   `yaml:"ID"`
+	MSPDir           string                   `yaml:"MSPDir"`
+	Policies         map[string]ProfilePolicy `yaml:"Policies"`
+	AnchorPeers      []ProfileAnchorPeer      `yaml:"AnchorPeers"`
+	OrdererEndpoints []string                 `yaml:"OrdererEndpoints"`
+}
+
+// ProfileAnchorPeer is anchor peer of application organization
+type ProfileAnchorPeer struct {
+	Host string `yaml:"Host"`
+	Port int    `yaml:"Port"`
+}
+
+// ProfileApplication is application section in configtx.yaml format
+type ProfileApplication struct {
+	Organizations []*ProfileOrganization   `yaml:"Organizations"`
+	Capabilities  map[string]bool          `yaml:"Capabilities"`
+	Policies      map[string]ProfilePolicy `yaml:"Policies"`
+	ACLs          map[string]string        `yaml:"ACLs"`
+}
+
+// ProfileOrderer is orderer section in configtx.yaml format
+type ProfileOrderer struct {
+	OrdererType   string                   `yaml:"OrdererType"`
+	Addresses     []string                 `yaml:"Addresses"`
+	BatchTimeout  time.Duration            `yaml:"BatchTimeout"`
+	BatchSize     ProfileBatchSize         `yaml:"BatchSize"`
+	EtcdRaft      *ProfileEtcdRaft         `yaml:"EtcdRaft"`
+	Organizations []*ProfileOrganization   `yaml:"Organizations"`
+	Capabilities  map[string]bool          `yaml:"Capabilities"`
+	Policies      map[string]ProfilePolicy `yaml:"Policies"`
+}
+
+// ProfileBatchSize is orderer batch size, sizes could be set like `10 MB`
+type ProfileBatchSize struct {
+	MaxMessageCount   uint32   `yaml:"MaxMessageCount"`
+	AbsoluteMaxBytes  ByteSize `yaml:"AbsoluteMaxBytes"`
+	PreferredMaxBytes ByteSize `yaml:"PreferredMaxBytes"`
+}
+
+// ProfileEtcdRaft is etcdraft consensus configuration
+type ProfileEtcdRaft struct {
+	Consenters []ProfileConsenter `yaml:"Consenters"`
+	Options    ProfileRaftOptions `yaml:"Options"`
+}
+
+// ProfileConsenter is etcdraft consenter, TLS certificates are file paths
+type ProfileConsenter struct {
+	Host          string `yaml:"Host"`
+	Port          int    `yaml:"Port"`
+	ClientTLSCert string `yaml:"ClientTLSCert"`
+	ServerTLSCert string `yaml:"ServerTLSCert"`
+}
+
+// ProfileRaftOptions are etcdraft options
+type ProfileRaftOptions struct {
+	TickInterval         string   `yaml:"TickInterval"`
+	ElectionTick         uint32   `yaml:"ElectionTick"`
+	HeartbeatTick        uint32   `yaml:"HeartbeatTick"`
+	MaxInflightBlocks    uint32   `yaml:"MaxInflightBlocks"`
+	SnapshotIntervalSize ByteSize `yaml:"SnapshotIntervalSize"`
+}
+
+// ByteSize is size in bytes which could be set with KB, MB or GB units
+type ByteSize uint32
+
+func (b *ByteSize) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value string
+	if err := unmarshal(&value); err != nil {
+		return err
+	}
+
+	value = strings.ToUpper(strings.TrimSpace(value))
+	multiplier := uint64(1)
+	for suffix, m := range map[string]uint64{`KB`: 1 << 10, `MB`: 1 << 20, `GB`: 1 << 30} {
+		if strings.HasSuffix(value, suffix) {
+			multiplier = m
+			value = strings.TrimSpace(strings.TrimSuffix(value, suffix))
+			break
+		}
+	}
+
+	size, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse byte size %q", value)
+	}
+	if size*multiplier > 1<<32-1 {
+		return errors.Errorf("byte size %q overflows uint32", value)
+	}
+
+	*b = ByteSize(size * multiplier)
+	return nil
+}
+
+// LoadProfile reads profile from configtx.yaml file, relative paths are resolved against file directory
+func LoadProfile(configtxPath, profileName string) (*Profile, error) {
+	raw, err := ioutil.ReadFile(configtxPath)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to read configtx file`)
+	}
+	return ParseProfile(raw, profileName, filepath.Dir(configtxPath))
+}
+
+// ParseProfile parses profile from configtx.yaml content, relative paths are resolved against baseDir
+func ParseProfile(raw []byte, profileName, baseDir string) (*Profile, error) {
+	configtx := struct {
+		Profiles map[string]*Profile `yaml:"Profiles"`
+	}{}
+
+	if err := yaml.Unmarshal(raw, &configtx); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal configtx YAML`)
+	}
+
+	profile, ok := configtx.Profiles[profileName]
+	if !ok || profile == nil {
+		return nil, errors.Errorf("profile %s not found", profileName)
+	}
+
+	resolve := func(path string) string {
+		if path == `` || filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(baseDir, path)
+	}
+
+	// organizations could be shared between sections by YAML anchors, so they are copied
+	resolveOrgs := func(orgs []*ProfileOrganization) {
+		for i := range orgs {
+			org := *orgs[i]
+			org.MSPDir = resolve(org.MSPDir)
+			orgs[i] = &org
+		}
+	}
+
+	if profile.Application != nil {
+		resolveOrgs(profile.Application.Organizations)
+	}
+	if profile.Orderer != nil {
+		resolveOrgs(profile.Orderer.Organizations)
+		if profile.Orderer.EtcdRaft != nil {
+			consenters := make([]ProfileConsenter, len(profile.Orderer.EtcdRaft.Consenters))
+			for i, consenter := range profile.Orderer.EtcdRaft.Consenters {
+				consenter.ClientTLSCert = resolve(consenter.ClientTLSCert)
+				consenter.ServerTLSCert = resolve(consenter.ServerTLSCert)
+				consenters[i] = consenter
+			}
+			profile.Orderer.EtcdRaft.Consenters = consenters
+		}
+	}
+
+	return profile, nil
+}
+
+// ChannelConfig builds channel config from profile, MSP definitions and TLS certificates are read from disk.
+// Defaults of configtxgen are used for omitted policies and orderer settings
+func (p *Profile) ChannelConfig() (*ChannelConfig, error) {
+	c := &ChannelConfig{
+		HashingAlgorithm:               defaultHashingAlgorithm,
+		BlockDataHashingStructureWidth: defaultBlockDataHashingWidth,
+		Consortium:                     p.Consortium,
+		Capabilities:                   capabilitiesList(p.Capabilities),
+		Policies:                       profilePolicies(p.Policies, nil),
+	}
+
+	if p.Application != nil {
+		orgs, err := profileOrganizations(p.Application.Organizations)
+		if err != nil {
+			return nil, errors.WithMessage(err, `failed to load application organizations`)
+		}
+
+		c.Application = &Application{
+			Organizations: orgs,
+			Capabilities:  capabilitiesList(p.Application.Capabilities),
+			ACLs:          p.Application.ACLs,
+			Policies:      profilePolicies(p.Application.Policies, nil),
+		}
+	}
+
+	if p.Orderer != nil {
+		ord, err := p.Orderer.orderer()
+		if err != nil {
+			return nil, err
+		}
+		c.Orderer = ord
+		c.OrdererAddresses = p.Orderer.Addresses
+	}
+
+	return c, nil
+}
+
+func (o *ProfileOrderer) orderer() (*Orderer, error) {
+	orgs, err := profileOrganizations(o.Organizations)
+	if err != nil {
+		return nil, errors.WithMessage(err, `failed to load orderer organizations`)
+	}
+
+	ord := &Orderer{
+		Organizations: orgs,
+		Consensus:     Consensus{Type: o.OrdererType},
+		BatchSize: BatchSize{
+			MaxMessageCount:   o.BatchSize.MaxMessageCount,
+			AbsoluteMaxBytes:  uint32(o.BatchSize.AbsoluteMaxBytes),
+			PreferredMaxBytes: uint32(o.BatchSize.PreferredMaxBytes),
+		},
+		BatchTimeout: o.BatchTimeout,
+		Capabilities: capabilitiesList(o.Capabilities),
+		Policies: profilePolicies(o.Policies, map[string]Policy{
+			BlockValidationPolicyKey: {Type: ImplicitMetaPolicyType, Rule: implicitMetaAnyWriters},
+		}),
+	}
+
+	if ord.BatchTimeout == 0 {
+		ord.BatchTimeout = defaultBatchTimeout
+	}
+	if ord.BatchSize.MaxMessageCount == 0 {
+		ord.BatchSize.MaxMessageCount = defaultMaxMessageCount
+	}
+	if ord.BatchSize.AbsoluteMaxBytes == 0 {
+		ord.BatchSize.AbsoluteMaxBytes = defaultAbsoluteMaxBytes
+	}
+	if ord.BatchSize.PreferredMaxBytes == 0 {
+		ord.BatchSize.PreferredMaxBytes = defaultPreferredMaxBytes
+	}
+
+	if o.OrdererType == EtcdRaftConsensusType {
+		if o.EtcdRaft == nil {
+			return nil, errors.New(`etcdraft configuration is not set`)
+		}
+
+		for _, consenter := range o.EtcdRaft.Consenters {
+			clientCert, err := ioutil.ReadFile(consenter.ClientTLSCert)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read client TLS cert of consenter %s", consenter.Host)
+			}
+			serverCert, err := ioutil.ReadFile(consenter.ServerTLSCert)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read server TLS cert of consenter %s", consenter.Host)
+			}
+			ord.Consensus.Consenters = append(ord.Consensus.Consenters, Consenter{
+				Address:       api.HostPort{Host: consenter.Host, Port: consenter.Port},
+				ClientTLSCert: clientCert,
+				ServerTLSCert: serverCert,
+			})
+		}
+
+		options := o.EtcdRaft.Options
+		ord.Consensus.RaftOptions = &etcdraft.Options{
+			TickInterval:         options.TickInterval,
+			ElectionTick:         options.ElectionTick,
+			HeartbeatTick:        options.HeartbeatTick,
+			MaxInflightBlocks:    options.MaxInflightBlocks,
+			SnapshotIntervalSize: uint32(options.SnapshotIntervalSize),
+		}
+		setRaftDefaults(ord.Consensus.RaftOptions)
+	}
+
+	return ord, nil
+}
+
+func setRaftDefaults(options *etcdraft.Options) {
+	if options.TickInterval == `` {
+		options.TickInterval = defaultTickInterval
+	}
+	if options.ElectionTick == 0 {
+		options.ElectionTick = defaultElectionTick
+	}
+	if options.HeartbeatTick == 0 {
+		options.HeartbeatTick = defaultHeartbeatTick
+	}
+	if options.MaxInflightBlocks == 0 {
+		options.MaxInflightBlocks = defaultMaxInflightBlocks
+	}
+	if options.SnapshotIntervalSize == 0 {
+		options.SnapshotIntervalSize = defaultSnapshotIntervalSize
+	}
+}
+
+func profileOrganizations(profileOrgs []*ProfileOrganization) (map[string]*Organization, error) {
+	orgs := make(map[string]*Organization, len(profileOrgs))
+	for _, profileOrg := range profileOrgs {
+		name := profileOrg.Name
+		if name == `` {
+			name = profileOrg.ID
+		}
+
+		if profileOrg.MSPDir == `` {
+			return nil, errors.Wrap(ErrProfileOrgMSPDirNotFound, name)
+		}
+
+		org, err := NewOrganization(profileOrg.ID, profileOrg.MSPDir)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to load organization %s", name)
+		}
+
+		if len(profileOrg.Policies) > 0 {
+			org.Policies = profilePolicies(profileOrg.Policies, nil)
+		}
+		for _, anchorPeer := range profileOrg.AnchorPeers {
+			org.AnchorPeers = append(org.AnchorPeers, api.HostPort{Host: anchorPeer.Host, Port: anchorPeer.Port})
+		}
+		org.Endpoints = profileOrg.OrdererEndpoints
+
+		orgs[name] = org
+	}
+	return orgs, nil
+}
+
+// profilePolicies converts profile policies, implicit meta policies like in configtxgen are used if policies are omitted
+func profilePolicies(profilePolicies map[string]ProfilePolicy, defaults map[string]Policy) map[string]Policy {
+	policies := make(map[string]Policy)
+	if len(profilePolicies) == 0 {
+		policies[channelconfig.ReadersPolicyKey] = Policy{Type: ImplicitMetaPolicyType, Rule: implicitMetaAnyReaders}
+		policies[channelconfig.WritersPolicyKey] = Policy{Type: ImplicitMetaPolicyType, Rule: implicitMetaAnyWriters}
+		policies[channelconfig.AdminsPolicyKey] = Policy{Type: ImplicitMetaPolicyType, Rule: implicitMetaMajorityAdmins}
+		for name, policy := range defaults {
+			policies[name] = policy
+		}
+		return policies
+	}
+
+	for name, policy := range profilePolicies {
+		policies[name] = Policy{Type: policy.Type, Rule: policy.Rule}
+	}
+	return policies
+}
+
+func capabilitiesList(capabilities map[string]bool) []string {
+	var list []string
+	for capability, enabled := range capabilities {
+		if enabled {
+			list = append(list, capability)
+		}
+	}
+	sort.Strings(list)
+	return list
+}
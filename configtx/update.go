@@ -0,0 +1,227 @@
+// Package configtx allows to read and edit channel config, compute config updates, sign them by several parties, check and submit
+package configtx
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/util"
+)
+
+var (
+	ErrNoDifferences        = errors.New(`no differences detected between original and updated config`)
+	ErrConfigUpdateMismatch = errors.New(`config updates are different`)
+	ErrEmptyChannelName     = errors.New(`channel name is empty`)
+)
+
+// Update is channel config update with signatures collected from organization admins
+type Update struct {
+	channelName  string
+	configUpdate []byte
+	signatures   []*common.ConfigSignature
+}
+
+// NewUpdate computes config update from original and modified channel config
+func NewUpdate(channelName string, original, modified *common.Config) (*Update, error) {
+	configUpdate, err := ComputeUpdate(channelName, original, modified)
+	if err != nil {
+		return nil, err
+	}
+	return NewUpdateFromConfigUpdate(configUpdate)
+}
+
+// NewUpdateFromConfigUpdate creates unsigned update from already computed config update
+func NewUpdateFromConfigUpdate(configUpdate *common.ConfigUpdate) (*Update, error) {
+	if configUpdate.ChannelId == `` {
+		return nil, ErrEmptyChannelName
+	}
+
+	configUpdateBytes, err := proto.Marshal(configUpdate)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to marshal common.ConfigUpdate`)
+	}
+
+	return &Update{channelName: configUpdate.ChannelId, configUpdate: configUpdateBytes}, nil
+}
+
+// ParseUpdate parses update with signatures from signable bundle (marshalled common.ConfigUpdateEnvelope)
+func ParseUpdate(raw []byte) (*Update, error) {
+	envelope := new(common.ConfigUpdateEnvelope)
+	if err := proto.Unmarshal(raw, envelope); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal common.ConfigUpdateEnvelope`)
+	}
+
+	configUpdate := new(common.ConfigUpdate)
+	if err := proto.Unmarshal(envelope.ConfigUpdate, configUpdate); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal common.ConfigUpdate`)
+	}
+
+	if configUpdate.ChannelId == `` {
+		return nil, ErrEmptyChannelName
+	}
+
+	return &Update{
+		channelName:  configUpdate.ChannelId,
+		configUpdate: envelope.ConfigUpdate,
+		signatures:   envelope.Signatures,
+	}, nil
+}
+
+// ReadFile reads update with signatures from signable bundle file
+func ReadFile(path string) (*Update, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to read update file`)
+	}
+	return ParseUpdate(raw)
+}
+
+// ChannelName returns name of updated channel
+func (u *Update) ChannelName() string {
+	return u.channelName
+}
+
+// ConfigUpdate returns marshalled common.ConfigUpdate which is signed by organization admins
+func (u *Update) ConfigUpdate() []byte {
+	return u.configUpdate
+}
+
+// Signatures returns collected signatures
+func (u *Update) Signatures() []*common.ConfigSignature {
+	return u.signatures
+}
+
+// Bytes returns signable bundle (marshalled common.ConfigUpdateEnvelope) with collected signatures
+func (u *Update) Bytes() ([]byte, error) {
+	return proto.Marshal(u.envelope())
+}
+
+// WriteFile writes signable bundle to file
+func (u *Update) WriteFile(path string) error {
+	raw, err := u.Bytes()
+	if err != nil {
+		return errors.Wrap(err, `failed to marshal common.ConfigUpdateEnvelope`)
+	}
+
+	if err = ioutil.WriteFile(path, raw, 0644); err != nil {
+		return errors.Wrap(err, `failed to write update file`)
+	}
+	return nil
+}
+
+// Sign signs config update by identity and adds signature to collected ones
+func (u *Update) Sign(id msp.SigningIdentity) error {
+	sig, err := util.NewConfigSignature(id, u.configUpdate)
+	if err != nil {
+		return errors.Wrap(err, `failed to sign config update`)
+	}
+	u.AddSignatures(sig)
+	return nil
+}
+
+// AddSignatures adds signatures, signatures of already presented creators are replaced
+func (u *Update) AddSignatures(sigs ...*common.ConfigSignature) {
+	for _, sig := range sigs {
+		creator := signatureCreator(sig)
+
+		var replaced bool
+		for i := range u.signatures {
+			if creator != nil && bytes.Equal(signatureCreator(u.signatures[i]), creator) {
+				u.signatures[i] = sig
+				replaced = true
+				break
+			}
+		}
+
+		if !replaced {
+			u.signatures = append(u.signatures, sig)
+		}
+	}
+}
+
+// Merge adds signatures collected in other bundles of the same config update
+func (u *Update) Merge(others ...*Update) error {
+	for _, other := range others {
+		if !bytes.Equal(u.configUpdate, other.configUpdate) {
+			return ErrConfigUpdateMismatch
+		}
+		u.AddSignatures(other.signatures...)
+	}
+	return nil
+}
+
+// Check verifies that update can be applied to current channel config and collected signatures satisfy mod policies
+func (u *Update) Check(config *common.Config) error {
+	bundle, err := channelconfig.NewBundle(u.channelName, config, factory.GetDefault())
+	if err != nil {
+		return errors.Wrap(err, `failed to create channel config bundle`)
+	}
+
+	envelopeBytes, err := proto.Marshal(u.envelope())
+	if err != nil {
+		return errors.Wrap(err, `failed to marshal common.ConfigUpdateEnvelope`)
+	}
+
+	channelHeader, err := util.NewChannelHeader(common.HeaderType_CONFIG_UPDATE, ``, u.channelName, 0, nil)
+	if err != nil {
+		return errors.Wrap(err, `failed to get channel header`)
+	}
+
+	payload, err := util.NewPayloadFromHeader(channelHeader, nil, envelopeBytes)
+	if err != nil {
+		return errors.Wrap(err, `failed to get payload`)
+	}
+
+	if _, err = bundle.ConfigtxValidator().ProposeConfigUpdate(&common.Envelope{Payload: payload}); err != nil {
+		return errors.Wrap(err, `config update is not valid`)
+	}
+	return nil
+}
+
+// Submit sends config update with collected signatures to orderer, transaction is signed by submitter identity
+func (u *Update) Submit(ctx context.Context, orderer api.Orderer, submitter msp.SigningIdentity) error {
+	envelope, err := u.Envelope(submitter)
+	if err != nil {
+		return err
+	}
+
+	if _, err = orderer.Broadcast(ctx, envelope); err != nil {
+		return errors.Wrap(err, `failed broadcast to orderer`)
+	}
+	return nil
+}
+
+// Envelope returns config update transaction with collected signatures signed by submitter identity,
+// e.g. channel creation transaction
+func (u *Update) Envelope(submitter msp.SigningIdentity) (*common.Envelope, error) {
+	envelope, err := util.NewConfigUpdateTx(u.channelName, u.envelope(), submitter)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to create config update transaction`)
+	}
+	return envelope, nil
+}
+
+func (u *Update) envelope() *common.ConfigUpdateEnvelope {
+	return &common.ConfigUpdateEnvelope{
+		ConfigUpdate: u.configUpdate,
+		Signatures:   u.signatures,
+	}
+}
+
+func signatureCreator(sig *common.ConfigSignature) []byte {
+	header, err := protoutil.UnmarshalSignatureHeader(sig.SignatureHeader)
+	if err != nil {
+		return nil
+	}
+	return header.Creator
+}
@@ -0,0 +1,142 @@
+package configtx_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/policydsl"
+	fabricMsp "github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/configtx"
+	"github.com/bogatyr285/hlf-sdk-go/crypto"
+	"github.com/bogatyr285/hlf-sdk-go/crypto/ecdsa"
+	"github.com/bogatyr285/hlf-sdk-go/identity"
+)
+
+const mspPath = `../client/chaincode/testdata/msp`
+
+type mockOrderer struct {
+	api.Orderer
+	envelope *common.Envelope
+}
+
+func (o *mockOrderer) Broadcast(_ context.Context, envelope *common.Envelope) (*orderer.BroadcastResponse, error) {
+	o.envelope = envelope
+	return &orderer.BroadcastResponse{Status: common.Status_SUCCESS}, nil
+}
+
+func addValue(t *testing.T, group *common.ConfigGroup, value *channelconfig.StandardConfigValue, modPolicy string) {
+	valueBytes, err := proto.Marshal(value.Value())
+	require.NoError(t, err)
+	if group.Values == nil {
+		group.Values = make(map[string]*common.ConfigValue)
+	}
+	group.Values[value.Key()] = &common.ConfigValue{Value: valueBytes, ModPolicy: modPolicy}
+}
+
+func addPolicy(group *common.ConfigGroup, policy *policies.StandardConfigPolicy, modPolicy string) {
+	group.Policies[policy.Key()] = &common.ConfigPolicy{Policy: policy.Value(), ModPolicy: modPolicy}
+}
+
+// channelConfig returns config of channel with application organizations, admins of each organization are its members
+func channelConfig(t *testing.T, mspIDs ...string) *common.Config {
+	application := protoutil.NewConfigGroup()
+	application.ModPolicy = channelconfig.AdminsPolicyKey
+	addPolicy(application, policies.ImplicitMetaMajorityPolicy(channelconfig.AdminsPolicyKey), channelconfig.AdminsPolicyKey)
+	addPolicy(application, policies.ImplicitMetaAnyPolicy(channelconfig.ReadersPolicyKey), channelconfig.AdminsPolicyKey)
+	addPolicy(application, policies.ImplicitMetaAnyPolicy(channelconfig.WritersPolicyKey), channelconfig.AdminsPolicyKey)
+
+	for _, mspID := range mspIDs {
+		mspConfig, err := fabricMsp.GetVerifyingMspConfig(mspPath, mspID, fabricMsp.ProviderTypeToString(fabricMsp.FABRIC))
+		require.NoError(t, err)
+
+		org := protoutil.NewConfigGroup()
+		org.ModPolicy = channelconfig.AdminsPolicyKey
+		addValue(t, org, channelconfig.MSPValue(mspConfig), channelconfig.AdminsPolicyKey)
+		member := policydsl.SignedByMspMember(mspID)
+		addPolicy(org, policies.SignaturePolicy(channelconfig.AdminsPolicyKey, member), channelconfig.AdminsPolicyKey)
+		addPolicy(org, policies.SignaturePolicy(channelconfig.ReadersPolicyKey, member), channelconfig.AdminsPolicyKey)
+		addPolicy(org, policies.SignaturePolicy(channelconfig.WritersPolicyKey, member), channelconfig.AdminsPolicyKey)
+		application.Groups[mspID] = org
+	}
+
+	channel := protoutil.NewConfigGroup()
+	channel.ModPolicy = channelconfig.AdminsPolicyKey
+	addValue(t, channel, channelconfig.HashingAlgorithmValue(), channelconfig.AdminsPolicyKey)
+	addValue(t, channel, channelconfig.BlockDataHashingStructureValue(), channelconfig.AdminsPolicyKey)
+	addValue(t, channel, channelconfig.OrdererAddressesValue([]string{`orderer:7050`}), `/Channel/Orderer/Admins`)
+	addPolicy(channel, policies.ImplicitMetaMajorityPolicy(channelconfig.AdminsPolicyKey), channelconfig.AdminsPolicyKey)
+	addPolicy(channel, policies.ImplicitMetaAnyPolicy(channelconfig.ReadersPolicyKey), channelconfig.AdminsPolicyKey)
+	addPolicy(channel, policies.ImplicitMetaAnyPolicy(channelconfig.WritersPolicyKey), channelconfig.AdminsPolicyKey)
+	channel.Groups[channelconfig.ApplicationGroupKey] = application
+
+	return &common.Config{ChannelGroup: channel}
+}
+
+func TestUpdate(t *testing.T) {
+	cs, err := crypto.GetSuite(ecdsa.Module, ecdsa.DefaultOpts)
+	require.NoError(t, err)
+
+	var ids []fabricMsp.SigningIdentity
+	for _, mspID := range []string{`org1msp`, `org2msp`, `org3msp`} {
+		id, err := identity.NewMSPIdentityFromPath(mspID, mspPath)
+		require.NoError(t, err)
+		ids = append(ids, id.GetSigningIdentity(cs))
+	}
+
+	original := channelConfig(t, `org1msp`, `org2msp`, `org3msp`)
+	modified := proto.Clone(original).(*common.Config)
+	addValue(t, modified.ChannelGroup.Groups[channelconfig.ApplicationGroupKey],
+		channelconfig.ACLValues(map[string]string{`qscc/GetChainInfo`: `/Channel/Application/Readers`}), channelconfig.AdminsPolicyKey)
+
+	update, err := configtx.NewUpdate(`channel`, original, modified)
+	require.NoError(t, err)
+
+	_, err = configtx.NewUpdate(`channel`, original, original)
+	require.Equal(t, configtx.ErrNoDifferences, err)
+
+	// majority of admins is required
+	require.NoError(t, update.Sign(ids[0]))
+	err = update.Check(original)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `policy for [Group]  /Channel/Application not satisfied`)
+
+	// second admin signs bundle elsewhere
+	dir, err := ioutil.TempDir(``, `configtx`)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	bundlePath := filepath.Join(dir, `update.pb`)
+	require.NoError(t, update.WriteFile(bundlePath))
+
+	received, err := configtx.ReadFile(bundlePath)
+	require.NoError(t, err)
+	require.Equal(t, `channel`, received.ChannelName())
+	require.NoError(t, received.Sign(ids[1]))
+	// repeated signature of the same identity is replaced
+	require.NoError(t, received.Sign(ids[1]))
+	require.Len(t, received.Signatures(), 2)
+
+	require.NoError(t, update.Merge(received))
+	require.Len(t, update.Signatures(), 2)
+	require.NoError(t, update.Check(original))
+
+	ord := &mockOrderer{}
+	require.NoError(t, update.Submit(context.Background(), ord, ids[0]))
+
+	envUpdate, err := protoutil.EnvelopeToConfigUpdate(ord.envelope)
+	require.NoError(t, err)
+	require.Equal(t, update.ConfigUpdate(), envUpdate.ConfigUpdate)
+	require.Len(t, envUpdate.Signatures, 2)
+}
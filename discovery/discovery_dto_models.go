@@ -16,6 +16,7 @@ type chaincodeDTO struct {
 	endorsers        map[string][]string
 	orderers         map[string][]string
 	peers            map[string][]string
+	layouts          []api.EndorsementLayout
 	chaincodeName    string
 	chaincodeVersion string
 	channelName      string
@@ -38,6 +39,11 @@ func (d *chaincodeDTO) Endorsers() []*api.HostEndpoint {
 	defer d.lock.RUnlock()
 	return mapToArray(d.endorsers)
 }
+func (d *chaincodeDTO) EndorsementLayouts() []api.EndorsementLayout {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.layouts
+}
 func (d *chaincodeDTO) Orderers() []*api.HostEndpoint {
 	d.lock.RLock()
 	defer d.lock.RUnlock()
@@ -60,6 +66,17 @@ func (d *chaincodeDTO) addEndpointToEndorsers(mspID, hostAddr string) {
 	d.endorsers[mspID] = append(d.endorsers[mspID], hostAddr)
 }
 
+func (d *chaincodeDTO) addEndorsementLayout(layout api.EndorsementLayout) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for _, l := range d.layouts {
+		if layoutsEqual(l, layout) {
+			return
+		}
+	}
+	d.layouts = append(d.layouts, layout)
+}
+
 func (d *chaincodeDTO) addEndpointToOrderers(mspID, hostAddr string) {
 	d.lock.Lock()
 	defer d.lock.Unlock()
@@ -93,6 +110,18 @@ func mapToArray(hosts map[string][]string) []*api.HostEndpoint {
 	return res
 }
 
+func layoutsEqual(l1, l2 api.EndorsementLayout) bool {
+	if len(l1) != len(l2) {
+		return false
+	}
+	for mspID, count := range l1 {
+		if l2[mspID] != count {
+			return false
+		}
+	}
+	return true
+}
+
 /* */
 // implementation of api.ChaincodeDiscoverer interface
 var _ api.ChannelDiscoverer = (*channelDTO)(nil)
@@ -7,6 +7,7 @@ import (
 	"github.com/bogatyr285/hlf-sdk-go/api"
 	"github.com/bogatyr285/hlf-sdk-go/api/config"
 	"github.com/bogatyr285/hlf-sdk-go/util"
+	"github.com/hyperledger/fabric-protos-go/discovery"
 	discClient "github.com/hyperledger/fabric/discovery/client"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -33,13 +34,13 @@ func NewGossipDiscoveryProvider(
 	clientIdentity []byte,
 	tlsMapper tlsConfigMapper,
 ) (*GossipDiscoveryProvider, error) {
-	discClient, err := newFabricDiscoveryClient(ctx, connCfg, log, identitySigner)
+	discClient, conn, err := newFabricDiscoveryClient(ctx, connCfg, log, identitySigner)
 	if err != nil {
 		return nil, err
 	}
 
 	// TODO probably we need to make a test call(ping) here to make sure user provided valid identity
-	sd := newGossipServiceDiscovery(discClient, clientIdentity)
+	sd := newGossipServiceDiscovery(discClient, discovery.NewDiscoveryClient(conn), identitySigner, clientIdentity)
 
 	return &GossipDiscoveryProvider{sd: sd, tlsMapper: tlsMapper}, nil
 }
@@ -51,15 +52,15 @@ func newFabricDiscoveryClient(
 	c config.ConnectionConfig,
 	log *zap.Logger,
 	identitySigner discClient.Signer,
-) (*discClient.Client, error) {
+) (*discClient.Client, *grpc.ClientConn, error) {
 	opts, err := util.NewGRPCOptionsFromConfig(c, log)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	conn, err := grpc.DialContext(ctx, c.Host, opts...)
 	if err != nil {
-		return nil, fmt.Errorf(`grpc dial to host=%s: %w`, c.Host, err)
+		return nil, nil, fmt.Errorf(`grpc dial to host=%s: %w`, c.Host, err)
 	}
 
 	discClient := discClient.NewClient(
@@ -70,7 +71,7 @@ func newFabricDiscoveryClient(
 		10,
 	)
 
-	return discClient, nil
+	return discClient, conn, nil
 }
 
 func (d *GossipDiscoveryProvider) Chaincode(ctx context.Context, channelName string, ccName string) (api.ChaincodeDiscoverer, error) {
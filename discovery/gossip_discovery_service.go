@@ -4,8 +4,14 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/discovery"
+	"github.com/hyperledger/fabric-protos-go/msp"
 	discClient "github.com/hyperledger/fabric/discovery/client"
+	"github.com/hyperledger/fabric/gossip/protoext"
+	"github.com/pkg/errors"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
 )
 
 // gossipServiceDiscovery - fetches info about all available peers, endorsers and orderers for channel & chaincode
@@ -13,12 +19,21 @@ import (
 // helper module for GossipDiscoveryProvider
 type gossipServiceDiscovery struct {
 	client         *discClient.Client
+	discovery      discovery.DiscoveryClient
+	signer         discClient.Signer
 	clientIdentity []byte
 }
 
-func newGossipServiceDiscovery(client *discClient.Client, clientIdentity []byte) *gossipServiceDiscovery {
+func newGossipServiceDiscovery(
+	client *discClient.Client,
+	discovery discovery.DiscoveryClient,
+	signer discClient.Signer,
+	clientIdentity []byte,
+) *gossipServiceDiscovery {
 	return &gossipServiceDiscovery{
 		client:         client,
+		discovery:      discovery,
+		signer:         signer,
 		clientIdentity: clientIdentity,
 	}
 }
@@ -40,40 +55,31 @@ func (s *gossipServiceDiscovery) DiscoverChaincode(ctx context.Context, ccName,
 		return nil, err
 	}
 
-	res, err := s.client.Send(ctx, req, s.getAuthInfo())
+	// discovery client response doesn't expose all endorsement layouts, so raw response is parsed
+	results, err := s.sendRaw(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
-	chanEndorsers, err := res.ForChannel(chanName).Endorsers(discClient.InvocationChain{
-		&discovery.ChaincodeCall{
-			Name: ccName,
-		},
-	}, discClient.NoFilter)
-	if err != nil {
-		return nil, err
+	for _, res := range results {
+		if resErr := res.GetError(); resErr != nil {
+			return nil, errors.New(resErr.Content)
+		}
 	}
 
-	chanPeers, err := res.ForChannel(chanName).Peers()
-	if err != nil {
-		return nil, err
-	}
-	chanCfg, err := res.ForChannel(chanName).Config()
-	if err != nil {
-		return nil, err
+	chanPeers := results[0].GetMembers()
+	chanCfg := results[1].GetConfigResult()
+	ccDescriptors := results[2].GetCcQueryRes().GetContent()
+	if chanPeers == nil || chanCfg == nil || len(ccDescriptors) != 1 {
+		return nil, errors.Errorf("unexpected discovery response for chaincode %s, channel %s", ccName, chanName)
 	}
 
-	// get chaincode version
-	var ccVersion string
-	if len(chanEndorsers) != 0 {
-		ccodes := chanEndorsers[0].StateInfoMessage.GossipMessage.GetStateInfo().Properties.Chaincodes
-		if len(ccodes) != 0 {
-			ccVersion = ccodes[0].Version
-		}
+	dc := newChaincodeDTO(ccName, ``, chanName)
+	if err = s.parseEndorsementDescriptor(dc, ccDescriptors[0]); err != nil {
+		return nil, errors.Wrap(err, `failed to parse endorsement descriptor`)
 	}
 
-	dc := newChaincodeDTO(ccName, ccVersion, chanName)
-	return s.parseDiscoverChaincodeResponse(dc, chanEndorsers, chanPeers, chanCfg), nil
+	return s.parseDiscoverChaincodeResponse(dc, chanPeers, chanCfg)
 }
 
 // DiscoverChannel - returns orderers for provided channel
@@ -120,18 +126,17 @@ func (s *gossipServiceDiscovery) LocalDiscovery(ctx context.Context) (*localPeer
 
 func (s *gossipServiceDiscovery) parseDiscoverChaincodeResponse(
 	dc *chaincodeDTO,
-	endorsers discClient.Endorsers,
-	peers []*discClient.Peer,
+	peers *discovery.PeerMembershipResult,
 	cfg *discovery.ConfigResult,
-) *chaincodeDTO {
-	for i := range endorsers {
-		hostAddr := endorsers[i].AliveMessage.GetAliveMsg().Membership.Endpoint
-		dc.addEndpointToEndorsers(endorsers[i].MSPID, hostAddr)
-	}
-
-	for i := range peers {
-		hostAddr := peers[i].AliveMessage.GetAliveMsg().Membership.Endpoint
-		dc.addEndpointToPeers(peers[i].MSPID, hostAddr)
+) (*chaincodeDTO, error) {
+	for mspID := range peers.PeersByOrg {
+		for _, p := range peers.PeersByOrg[mspID].Peers {
+			alive, err := protoext.EnvelopeToGossipMessage(p.MembershipInfo)
+			if err != nil {
+				return nil, errors.Wrap(err, `failed to unmarshal peer alive message`)
+			}
+			dc.addEndpointToPeers(mspID, alive.GetAliveMsg().GetMembership().GetEndpoint())
+		}
 	}
 
 	for ordererMSPID := range cfg.Orderers {
@@ -141,7 +146,64 @@ func (s *gossipServiceDiscovery) parseDiscoverChaincodeResponse(
 		}
 	}
 
-	return dc
+	return dc, nil
+}
+
+// parseEndorsementDescriptor fills endorsers, endorsement layouts and chaincode version,
+// each layout group is mapped to MSP of peers of this group
+func (s *gossipServiceDiscovery) parseEndorsementDescriptor(dc *chaincodeDTO, desc *discovery.EndorsementDescriptor) error {
+	groupMSPs := make(map[string]string)
+	seen := make(map[string]bool)
+
+	for grp, peers := range desc.EndorsersByGroups {
+		for _, p := range peers.Peers {
+			sID := &msp.SerializedIdentity{}
+			if err := proto.Unmarshal(p.Identity, sID); err != nil {
+				return errors.Wrap(err, `failed to unmarshal peer identity`)
+			}
+
+			alive, err := protoext.EnvelopeToGossipMessage(p.MembershipInfo)
+			if err != nil {
+				return errors.Wrap(err, `failed to unmarshal peer alive message`)
+			}
+
+			if _, ok := groupMSPs[grp]; !ok {
+				groupMSPs[grp] = sID.Mspid
+			}
+
+			hostAddr := alive.GetAliveMsg().GetMembership().GetEndpoint()
+			if !seen[hostAddr] {
+				seen[hostAddr] = true
+				dc.addEndpointToEndorsers(sID.Mspid, hostAddr)
+			}
+
+			if dc.chaincodeVersion == `` && p.StateInfo != nil {
+				stateInfo, err := protoext.EnvelopeToGossipMessage(p.StateInfo)
+				if err != nil {
+					return errors.Wrap(err, `failed to unmarshal peer state info message`)
+				}
+				for _, cc := range stateInfo.GetStateInfo().GetProperties().GetChaincodes() {
+					if cc.Name == dc.chaincodeName {
+						dc.chaincodeVersion = cc.Version
+					}
+				}
+			}
+		}
+	}
+
+	for _, l := range desc.Layouts {
+		layout := make(api.EndorsementLayout)
+		for grp, count := range l.QuantitiesByGroup {
+			mspID, ok := groupMSPs[grp]
+			if !ok {
+				return errors.Errorf("group %s isn't mapped to endorsers, but exists in a layout", grp)
+			}
+			layout[mspID] += int(count)
+		}
+		dc.addEndorsementLayout(layout)
+	}
+
+	return nil
 }
 
 func (s *gossipServiceDiscovery) parseDiscoverLocalPeers(
@@ -170,6 +232,33 @@ func (s *gossipServiceDiscovery) parseDiscoverChannelResponse(
 	return dc
 }
 
+// sendRaw sends signed discovery request and returns query results in order of request queries
+func (s *gossipServiceDiscovery) sendRaw(ctx context.Context, req *discClient.Request) ([]*discovery.QueryResult, error) {
+	reqToBeSent := *req.Request
+	reqToBeSent.Authentication = s.getAuthInfo()
+
+	payload, err := proto.Marshal(&reqToBeSent)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to marshal discovery request`)
+	}
+
+	sig, err := s.signer(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to sign discovery request`)
+	}
+
+	resp, err := s.discovery.Discover(ctx, &discovery.SignedRequest{Payload: payload, Signature: sig})
+	if err != nil {
+		return nil, errors.Wrap(err, `discovery service refused request`)
+	}
+
+	if len(resp.Results) != len(reqToBeSent.Queries) {
+		return nil, errors.Errorf("sent %d queries but received %d responses back", len(reqToBeSent.Queries), len(resp.Results))
+	}
+
+	return resp.Results, nil
+}
+
 func (s *gossipServiceDiscovery) getAuthInfo() *discovery.AuthInfo {
 	return &discovery.AuthInfo{
 		ClientIdentity: s.clientIdentity,
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/msp"
 	"github.com/hyperledger/fabric/common/policydsl"
 	"github.com/mitchellh/mapstructure"
@@ -63,6 +64,15 @@ func (d *LocalConfigProvider) Chaincode(_ context.Context, channelName, ccName s
 						ccDTO.addEndpointToEndorsers(mspID, hostAddr)
 					}
 
+					layouts, err := getLayoutsFromPolicy(cc.Policy)
+					if err != nil {
+						return nil, err
+					}
+
+					for i := range layouts {
+						ccDTO.addEndorsementLayout(layouts[i])
+					}
+
 					return newChaincodeDiscovererTLSDecorator(ccDTO, d.tlsMapper), nil
 				}
 			}
@@ -121,3 +131,85 @@ func getMSPsFromPolicy(policy string) ([]string, error) {
 
 	return mspIds, nil
 }
+
+// getLayoutsFromPolicy returns all combinations of MSPs which satisfy signature policy
+func getLayoutsFromPolicy(policy string) ([]api.EndorsementLayout, error) {
+	policyEnvelope, err := policydsl.FromString(policy)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to parse policy`)
+	}
+
+	mspIds := make([]string, len(policyEnvelope.Identities))
+	for i, id := range policyEnvelope.Identities {
+		var mspIdentity msp.SerializedIdentity
+		if err = proto.Unmarshal(id.Principal, &mspIdentity); err != nil {
+			return nil, errors.Wrap(err, `failed to get MSP identity`)
+		}
+		mspIds[i] = mspIdentity.Mspid
+	}
+
+	return layoutsForRule(policyEnvelope.Rule, mspIds)
+}
+
+func layoutsForRule(rule *common.SignaturePolicy, mspIds []string) ([]api.EndorsementLayout, error) {
+	switch r := rule.Type.(type) {
+	case *common.SignaturePolicy_SignedBy:
+		if r.SignedBy < 0 || int(r.SignedBy) >= len(mspIds) {
+			return nil, errors.Errorf("identity index %d out of range", r.SignedBy)
+		}
+		return []api.EndorsementLayout{{mspIds[r.SignedBy]: 1}}, nil
+
+	case *common.SignaturePolicy_NOutOf_:
+		rules := r.NOutOf.Rules
+		n := int(r.NOutOf.N)
+		if n > len(rules) {
+			return nil, errors.Errorf("policy requires %d out of %d rules", n, len(rules))
+		}
+
+		subLayouts := make([][]api.EndorsementLayout, len(rules))
+		for i := range rules {
+			layouts, err := layoutsForRule(rules[i], mspIds)
+			if err != nil {
+				return nil, err
+			}
+			subLayouts[i] = layouts
+		}
+
+		var (
+			layouts []api.EndorsementLayout
+			choose  func(from, left int, merged []api.EndorsementLayout)
+		)
+		// every combination of n rules produces cross product of their layouts
+		choose = func(from, left int, merged []api.EndorsementLayout) {
+			if left == 0 {
+				layouts = append(layouts, merged...)
+				return
+			}
+			for i := from; i <= len(rules)-left; i++ {
+				choose(i+1, left-1, mergeLayouts(merged, subLayouts[i]))
+			}
+		}
+		choose(0, n, []api.EndorsementLayout{{}})
+
+		return layouts, nil
+	}
+
+	return nil, errors.Errorf("unknown signature policy type %T", rule.Type)
+}
+
+func mergeLayouts(layouts1, layouts2 []api.EndorsementLayout) []api.EndorsementLayout {
+	merged := make([]api.EndorsementLayout, 0, len(layouts1)*len(layouts2))
+	for _, l1 := range layouts1 {
+		for _, l2 := range layouts2 {
+			layout := make(api.EndorsementLayout, len(l1)+len(l2))
+			for mspID, count := range l1 {
+				layout[mspID] += count
+			}
+			for mspID, count := range l2 {
+				layout[mspID] += count
+			}
+			merged = append(merged, layout)
+		}
+	}
+	return merged
+}
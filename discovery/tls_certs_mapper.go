@@ -66,6 +66,10 @@ func (d *chaincodeDiscovererTLSDecorator) Endorsers() []*api.HostEndpoint {
 	return addTLSSettings(d.target.Endorsers(), d.tlsMapper)
 }
 
+func (d *chaincodeDiscovererTLSDecorator) EndorsementLayouts() []api.EndorsementLayout {
+	return d.target.EndorsementLayouts()
+}
+
 func (d *chaincodeDiscovererTLSDecorator) Orderers() []*api.HostEndpoint {
 	return addTLSSettings(d.target.Orderers(), d.tlsMapper)
 }
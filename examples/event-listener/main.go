@@ -12,6 +12,7 @@ import (
 	"github.com/bogatyr285/hlf-sdk-go/client"
 	_ "github.com/bogatyr285/hlf-sdk-go/crypto/ecdsa"
 	"github.com/bogatyr285/hlf-sdk-go/identity"
+	"github.com/bogatyr285/hlf-sdk-go/peer/deliver/hub"
 	"go.uber.org/zap"
 )
 
@@ -54,11 +55,18 @@ func main() {
 		log.Fatalln(`unable to initialize core:`, err)
 	}
 
-	cc, err := core.Channel(channel).Chaincode(context.Background(), chaincode)
+	deliver, err := core.PeerPool().DeliverClient(mspId, core.CurrentIdentity())
 	if err != nil {
-		log.Fatalln(`unable to initialize channel:`, err)
+		log.Fatalln(`unable to initialize deliver client:`, err)
 	}
 
+	// all subscriptions share one block stream of channel
+	blocks, err := hub.New(context.Background(), deliver, channel)
+	if err != nil {
+		log.Fatalln(`unable to initialize deliver hub:`, err)
+	}
+	defer blocks.Close()
+
 	var wg sync.WaitGroup
 
 	for i := 0; i < 10; i++ {
@@ -66,7 +74,7 @@ func main() {
 
 		go func(idx int, wg *sync.WaitGroup) {
 			defer wg.Done()
-			sub, err := cc.Subscribe(context.Background())
+			sub, err := blocks.SubscribeCC(chaincode)
 			if err != nil {
 				log.Printf("Failed to process rouitine %d: %s", idx, err)
 				return
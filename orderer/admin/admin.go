@@ -0,0 +1,161 @@
+// Package admin implements client of orderer channel participation API (osnadmin)
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/api/config"
+	"github.com/bogatyr285/hlf-sdk-go/util"
+)
+
+const (
+	channelsEndpoint = `/participation/v1/channels`
+	configBlockField = `config-block`
+)
+
+type admin struct {
+	baseURL string
+	client  *http.Client
+}
+
+type Opt func(a *admin) error
+
+// WithHTTPClient allows to use custom HTTP client instead of client created from connection config
+func WithHTTPClient(client *http.Client) Opt {
+	return func(a *admin) error {
+		a.client = client
+		return nil
+	}
+}
+
+// New creates orderer admin client, c.Host is address of orderer admin endpoint.
+// HTTPS with mutual TLS is used if TLS is enabled in config
+func New(c config.ConnectionConfig, opts ...Opt) (api.OrdererAdmin, error) {
+	a := &admin{baseURL: c.Host}
+
+	if !strings.Contains(a.baseURL, `://`) {
+		if c.Tls.Enabled {
+			a.baseURL = `https://` + a.baseURL
+		} else {
+			a.baseURL = `http://` + a.baseURL
+		}
+	}
+	a.baseURL = strings.TrimSuffix(a.baseURL, `/`)
+
+	for _, opt := range opts {
+		if err := opt(a); err != nil {
+			return nil, fmt.Errorf(`apply option: %w`, err)
+		}
+	}
+
+	if a.client == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if c.Tls.Enabled {
+			tlsCfg, err := util.NewTLSConfig(c.Tls)
+			if err != nil {
+				return nil, fmt.Errorf(`initialize TLS config: %w`, err)
+			}
+			transport.TLSClientConfig = tlsCfg
+		}
+		a.client = &http.Client{Transport: transport, Timeout: c.Timeout.Duration}
+	}
+
+	return a, nil
+}
+
+func (a *admin) ListChannels(ctx context.Context) (*api.OrdererChannelList, error) {
+	channels := new(api.OrdererChannelList)
+	if err := a.do(ctx, http.MethodGet, channelsEndpoint, nil, ``, http.StatusOK, channels); err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+func (a *admin) ListChannel(ctx context.Context, channelName string) (*api.OrdererChannelInfo, error) {
+	info := new(api.OrdererChannelInfo)
+	if err := a.do(ctx, http.MethodGet, channelEndpoint(channelName), nil, ``, http.StatusOK, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (a *admin) JoinChannel(ctx context.Context, configBlock *common.Block) (*api.OrdererChannelInfo, error) {
+	blockBytes, err := proto.Marshal(configBlock)
+	if err != nil {
+		return nil, fmt.Errorf(`marshal config block: %w`, err)
+	}
+
+	body := new(bytes.Buffer)
+	form := multipart.NewWriter(body)
+	part, err := form.CreateFormFile(configBlockField, `config.block`)
+	if err != nil {
+		return nil, fmt.Errorf(`create form file: %w`, err)
+	}
+	if _, err = part.Write(blockBytes); err != nil {
+		return nil, fmt.Errorf(`write config block: %w`, err)
+	}
+	if err = form.Close(); err != nil {
+		return nil, fmt.Errorf(`close multipart form: %w`, err)
+	}
+
+	info := new(api.OrdererChannelInfo)
+	if err = a.do(ctx, http.MethodPost, channelsEndpoint, body, form.FormDataContentType(), http.StatusCreated, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (a *admin) RemoveChannel(ctx context.Context, channelName string) error {
+	return a.do(ctx, http.MethodDelete, channelEndpoint(channelName), nil, ``, http.StatusNoContent, nil)
+}
+
+func (a *admin) do(ctx context.Context, method, endpoint string, body io.Reader, contentType string, expectedStatus int, out interface{}) error {
+	req, err := http.NewRequest(method, a.baseURL+endpoint, body)
+	if err != nil {
+		return fmt.Errorf(`create request: %w`, err)
+	}
+	if contentType != `` {
+		req.Header.Set(`Content-Type`, contentType)
+	}
+
+	resp, err := a.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf(`process request: %w`, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf(`read response body: %w`, err)
+	}
+
+	if resp.StatusCode != expectedStatus {
+		return api.ErrUnexpectedHTTPStatus{Status: resp.StatusCode, Body: respBody}
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err = json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf(`unmarshal JSON response: %w`, err)
+	}
+	return nil
+}
+
+func channelEndpoint(channelName string) string {
+	return channelsEndpoint + `/` + url.PathEscape(channelName)
+}
@@ -0,0 +1,144 @@
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/api/config"
+	"github.com/bogatyr285/hlf-sdk-go/orderer/admin"
+)
+
+// participationMock is stand-in for orderer channel participation API
+type participationMock struct {
+	mu       sync.Mutex
+	channels map[string]*common.Block
+}
+
+func (m *participationMock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name := strings.TrimPrefix(r.URL.Path, `/participation/v1/channels`)
+	name = strings.TrimPrefix(name, `/`)
+
+	switch {
+	case r.Method == http.MethodGet && name == ``:
+		list := api.OrdererChannelList{}
+		for ch := range m.channels {
+			list.Channels = append(list.Channels, api.OrdererChannelInfoShort{Name: ch, URL: r.URL.Path + `/` + ch})
+		}
+		writeJSON(w, http.StatusOK, list)
+
+	case r.Method == http.MethodGet:
+		block, ok := m.channels[name]
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{`error`: `channel does not exist`})
+			return
+		}
+		writeJSON(w, http.StatusOK, channelInfo(name, block))
+
+	case r.Method == http.MethodPost:
+		file, _, err := r.FormFile(`config-block`)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{`error`: err.Error()})
+			return
+		}
+		blockBytes, _ := ioutil.ReadAll(file)
+		block := new(common.Block)
+		if err = proto.Unmarshal(blockBytes, block); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{`error`: err.Error()})
+			return
+		}
+		name = string(block.Data.Data[0])
+		if _, ok := m.channels[name]; ok {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{`error`: `channel already exists`})
+			return
+		}
+		m.channels[name] = block
+		writeJSON(w, http.StatusCreated, channelInfo(name, block))
+
+	case r.Method == http.MethodDelete:
+		if _, ok := m.channels[name]; !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{`error`: `channel does not exist`})
+			return
+		}
+		delete(m.channels, name)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func channelInfo(name string, block *common.Block) api.OrdererChannelInfo {
+	return api.OrdererChannelInfo{
+		Name:              name,
+		URL:               `/participation/v1/channels/` + name,
+		Status:            `active`,
+		ConsensusRelation: `consenter`,
+		Height:            block.Header.Number + 1,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set(`Content-Type`, `application/json`)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func TestAdmin(t *testing.T) {
+	server := httptest.NewTLSServer(&participationMock{channels: make(map[string]*common.Block)})
+	defer server.Close()
+
+	dir, err := ioutil.TempDir(``, `osnadmin`)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	caPath := filepath.Join(dir, `ca.pem`)
+	require.NoError(t, ioutil.WriteFile(caPath,
+		pem.EncodeToMemory(&pem.Block{Type: `CERTIFICATE`, Bytes: server.Certificate().Raw}), 0600))
+
+	cli, err := admin.New(config.ConnectionConfig{
+		Host: strings.TrimPrefix(server.URL, `https://`),
+		Tls:  config.TlsConfig{Enabled: true, CACertPath: caPath},
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	genesis := protoutil.NewBlock(0, nil)
+	genesis.Data.Data = [][]byte{[]byte(`channel`)}
+
+	info, err := cli.JoinChannel(ctx, genesis)
+	require.NoError(t, err)
+	require.Equal(t, `channel`, info.Name)
+	require.Equal(t, uint64(1), info.Height)
+
+	_, err = cli.JoinChannel(ctx, genesis)
+	require.Equal(t, http.StatusMethodNotAllowed, err.(api.ErrUnexpectedHTTPStatus).Status)
+
+	list, err := cli.ListChannels(ctx)
+	require.NoError(t, err)
+	require.Nil(t, list.SystemChannel)
+	require.Equal(t, []api.OrdererChannelInfoShort{{Name: `channel`, URL: `/participation/v1/channels/channel`}}, list.Channels)
+
+	info, err = cli.ListChannel(ctx, `channel`)
+	require.NoError(t, err)
+	require.Equal(t, `consenter`, info.ConsensusRelation)
+
+	require.NoError(t, cli.RemoveChannel(ctx, `channel`))
+
+	_, err = cli.ListChannel(ctx, `channel`)
+	require.Equal(t, http.StatusNotFound, err.(api.ErrUnexpectedHTTPStatus).Status)
+}
@@ -0,0 +1,297 @@
+package orderer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	fabricOrderer "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric/msp"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/api/config"
+	"github.com/bogatyr285/hlf-sdk-go/util"
+)
+
+const (
+	DefaultDeliverRetryDelay = time.Second
+)
+
+var (
+	ErrNoOrdererConnections = errors.New(`no orderer connections provided`)
+	// ErrDeliverClientClosed is sent to subscriptions of closed deliver client
+	ErrDeliverClientClosed = errors.New(`deliver client is closed`)
+)
+
+type deliverClient struct {
+	conns      []*grpc.ClientConn
+	identity   msp.SigningIdentity
+	log        *zap.Logger
+	retryDelay time.Duration
+	maxRetries int
+	// dialed is true if conns were dialed by client itself, they are closed on Close
+	dialed bool
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+type DeliverOpt func(*deliverClient)
+
+// WithDeliverLogger sets logger used for reporting reconnects
+func WithDeliverLogger(log *zap.Logger) DeliverOpt {
+	return func(c *deliverClient) {
+		c.log = log
+	}
+}
+
+// WithDeliverRetryDelay sets delay before reconnect to next orderer
+func WithDeliverRetryDelay(delay time.Duration) DeliverOpt {
+	return func(c *deliverClient) {
+		c.retryDelay = delay
+	}
+}
+
+// WithDeliverMaxRetries limits count of consecutive reconnects without received blocks, 0 means unlimited
+func WithDeliverMaxRetries(retries int) DeliverOpt {
+	return func(c *deliverClient) {
+		c.maxRetries = retries
+	}
+}
+
+// NewDeliverClient returns block deliver client using connection per orderer endpoint
+func NewDeliverClient(identity msp.SigningIdentity, conns []*grpc.ClientConn, opts ...DeliverOpt) (api.OrdererDeliverClient, error) {
+	if len(conns) == 0 {
+		return nil, ErrNoOrdererConnections
+	}
+
+	c := &deliverClient{
+		conns:      conns,
+		identity:   identity,
+		log:        zap.NewNop(),
+		retryDelay: DefaultDeliverRetryDelay,
+		closed:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// NewDeliverClientFromConfigs dials every configured orderer and returns block deliver client,
+// connections are closed by Close of client
+func NewDeliverClientFromConfigs(ctx context.Context, identity msp.SigningIdentity, log *zap.Logger, configs []config.ConnectionConfig, opts ...DeliverOpt) (api.OrdererDeliverClient, error) {
+	conns, err := dialConns(ctx, log, configs)
+	if err != nil {
+		return nil, err
+	}
+
+	cli, err := NewDeliverClient(identity, conns, append([]DeliverOpt{WithDeliverLogger(log)}, opts...)...)
+	if err != nil {
+		_ = closeConns(conns)
+		return nil, err
+	}
+	cli.(*deliverClient).dialed = true
+
+	return cli, nil
+}
+
+// Close finishes subscriptions with ErrDeliverClientClosed.
+// Connections are closed only if they were dialed by client
+func (c *deliverClient) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		if c.dialed {
+			err = closeConns(c.conns)
+		}
+	})
+	return err
+}
+
+func (c *deliverClient) SubscribeBlock(ctx context.Context, channelName string, seekOpt ...api.EventCCSeekOption) (api.BlockSubscription, error) {
+	select {
+	case <-c.closed:
+		return nil, ErrDeliverClientClosed
+	default:
+	}
+
+	var startPos, stopPos *fabricOrderer.SeekPosition
+
+	if len(seekOpt) > 0 {
+		startPos, stopPos = seekOpt[0]()
+	} else {
+		startPos, stopPos = api.SeekNewest()()
+	}
+
+	subCtx, cancel := context.WithCancel(ctx)
+	sub := &blockSubscription{
+		ctx:     subCtx,
+		cancel:  cancel,
+		client:  c,
+		channel: channelName,
+		start:   startPos,
+		stop:    stopPos,
+		blocks:  make(chan *common.Block),
+		errors:  make(chan error, 1),
+		done:    make(chan struct{}),
+	}
+
+	go sub.run()
+
+	return sub, nil
+}
+
+type blockSubscription struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	client  *deliverClient
+	channel string
+	start   *fabricOrderer.SeekPosition
+	stop    *fabricOrderer.SeekPosition
+
+	// last is number of last received block, valid when received is true
+	last     uint64
+	received bool
+
+	blocks chan *common.Block
+	errors chan error
+	done   chan struct{}
+}
+
+func (s *blockSubscription) Blocks() <-chan *common.Block {
+	return s.blocks
+}
+
+func (s *blockSubscription) Errors() chan error {
+	return s.errors
+}
+
+func (s *blockSubscription) Close() error {
+	s.cancel()
+	<-s.done
+	return nil
+}
+
+func (s *blockSubscription) run() {
+	defer close(s.done)
+	defer close(s.errors)
+	defer close(s.blocks)
+
+	endpoint, retries := 0, 0
+	for {
+		receivedBefore, lastBefore := s.received, s.last
+		err := s.deliver(s.client.conns[endpoint])
+		if err == nil || s.ctx.Err() != nil {
+			return
+		}
+
+		select {
+		case <-s.client.closed:
+			s.errors <- ErrDeliverClientClosed
+			return
+		default:
+		}
+
+		var statusErr *ErrUnexpectedStatus
+		if errors.As(err, &statusErr) && !retryableStatus(statusErr.Status) {
+			s.errors <- err
+			return
+		}
+
+		if s.received != receivedBefore || s.last != lastBefore {
+			retries = 0
+		}
+		retries++
+		if s.client.maxRetries > 0 && retries > s.client.maxRetries {
+			s.errors <- fmt.Errorf(`deliver retries exceeded: %w`, err)
+			return
+		}
+
+		s.client.log.Warn(`orderer deliver stream failed, reconnecting`,
+			zap.String(`channel`, s.channel),
+			zap.String(`orderer`, s.client.conns[endpoint].Target()),
+			zap.Error(err))
+
+		endpoint = (endpoint + 1) % len(s.client.conns)
+
+		select {
+		case <-time.After(s.client.retryDelay):
+		case <-s.ctx.Done():
+			return
+		case <-s.client.closed:
+			s.errors <- ErrDeliverClientClosed
+			return
+		}
+	}
+}
+
+// deliver reads blocks from single orderer, returns nil when stop position is reached
+func (s *blockSubscription) deliver(conn *grpc.ClientConn) error {
+	start := s.start
+	if s.received {
+		if stop := s.stop.GetSpecified(); stop != nil && s.last >= stop.Number {
+			return nil
+		}
+		start = &fabricOrderer.SeekPosition{Type: &fabricOrderer.SeekPosition_Specified{
+			Specified: &fabricOrderer.SeekSpecified{Number: s.last + 1}}}
+	}
+
+	seek, err := util.SeekEnvelope(s.channel, start, s.stop, s.client.identity)
+	if err != nil {
+		return fmt.Errorf(`get seek envelope: %w`, err)
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+
+	stream, err := fabricOrderer.NewAtomicBroadcastClient(conn).Deliver(ctx)
+	if err != nil {
+		return fmt.Errorf(`initialize deliver client: %w`, err)
+	}
+
+	if err = stream.Send(seek); err != nil {
+		return fmt.Errorf(`send envelope: %w`, err)
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return fmt.Errorf(`receive response: %w`, err)
+		}
+
+		switch respType := resp.Type.(type) {
+		case *fabricOrderer.DeliverResponse_Status:
+			if respType.Status != common.Status_SUCCESS {
+				return &ErrUnexpectedStatus{Status: respType.Status, Endpoint: conn.Target()}
+			}
+			return nil
+
+		case *fabricOrderer.DeliverResponse_Block:
+			select {
+			case s.blocks <- respType.Block:
+				s.last, s.received = respType.Block.GetHeader().GetNumber(), true
+			case <-s.ctx.Done():
+				return s.ctx.Err()
+			case <-s.client.closed:
+				return ErrDeliverClientClosed
+			}
+		}
+	}
+}
+
+// retryableStatus returns true if request can be repeated on another orderer
+func retryableStatus(status common.Status) bool {
+	switch status {
+	case common.Status_SERVICE_UNAVAILABLE, common.Status_NOT_FOUND, common.Status_INTERNAL_SERVER_ERROR:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,312 @@
+package orderer_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	fabricOrderer "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/api/config"
+	"github.com/bogatyr285/hlf-sdk-go/crypto"
+	"github.com/bogatyr285/hlf-sdk-go/crypto/ecdsa"
+	"github.com/bogatyr285/hlf-sdk-go/identity"
+	"github.com/bogatyr285/hlf-sdk-go/orderer"
+)
+
+// mockOrderer serves blocks of chain, fails stream after failAfter blocks or responds with status.
+// Broadcast responds with broadcastStatus, SUCCESS by default, and envelope payload as info,
+// broadcast stream fails after broadcastFailAfter envelopes if it is set,
+// responses are held until broadcastHold is closed if it is set
+type mockOrderer struct {
+	fabricOrderer.UnimplementedAtomicBroadcastServer
+	chain              []*common.Block
+	failAfter          int
+	status             common.Status
+	broadcastStatus    common.Status
+	broadcastFailAfter int
+	broadcastHold      chan struct{}
+
+	mu         sync.Mutex
+	seeks      []uint64
+	broadcasts int
+}
+
+func (m *mockOrderer) Broadcast(stream fabricOrderer.AtomicBroadcast_BroadcastServer) error {
+	for received := 0; ; received++ {
+		if m.broadcastFailAfter > 0 && received == m.broadcastFailAfter {
+			return errors.New(`stream failed`)
+		}
+
+		env, err := stream.Recv()
+		if err != nil {
+			return nil
+		}
+
+		m.mu.Lock()
+		m.broadcasts++
+		m.mu.Unlock()
+
+		if m.broadcastHold != nil {
+			<-m.broadcastHold
+		}
+
+		status := m.broadcastStatus
+		if status == common.Status_UNKNOWN {
+			status = common.Status_SUCCESS
+		}
+		if err = stream.Send(&fabricOrderer.BroadcastResponse{Status: status, Info: string(env.Payload)}); err != nil {
+			return err
+		}
+	}
+}
+
+func (m *mockOrderer) broadcastCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.broadcasts
+}
+
+func (m *mockOrderer) Deliver(stream fabricOrderer.AtomicBroadcast_DeliverServer) error {
+	env, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return err
+	}
+	seekInfo := new(fabricOrderer.SeekInfo)
+	if err = proto.Unmarshal(payload.Data, seekInfo); err != nil {
+		return err
+	}
+
+	if m.status != common.Status_UNKNOWN {
+		return stream.Send(&fabricOrderer.DeliverResponse{Type: &fabricOrderer.DeliverResponse_Status{Status: m.status}})
+	}
+
+	start, stop := m.position(seekInfo.Start), m.position(seekInfo.Stop)
+	m.mu.Lock()
+	m.seeks = append(m.seeks, start)
+	m.mu.Unlock()
+
+	for num, sent := start, 0; num <= stop; num, sent = num+1, sent+1 {
+		if m.failAfter >= 0 && sent == m.failAfter {
+			return errors.New(`stream failed`)
+		}
+		if err = stream.Send(&fabricOrderer.DeliverResponse{
+			Type: &fabricOrderer.DeliverResponse_Block{Block: m.chain[num]}}); err != nil {
+			return err
+		}
+	}
+
+	return stream.Send(&fabricOrderer.DeliverResponse{Type: &fabricOrderer.DeliverResponse_Status{Status: common.Status_SUCCESS}})
+}
+
+func (m *mockOrderer) position(pos *fabricOrderer.SeekPosition) uint64 {
+	switch {
+	case pos.GetOldest() != nil:
+		return 0
+	case pos.GetNewest() != nil:
+		return uint64(len(m.chain) - 1)
+	default:
+		return pos.GetSpecified().GetNumber()
+	}
+}
+
+func newChain(size int) []*common.Block {
+	chain := make([]*common.Block, size)
+	for i := range chain {
+		chain[i] = protoutil.NewBlock(uint64(i), nil)
+	}
+	return chain
+}
+
+func serve(t testing.TB, srv fabricOrderer.AtomicBroadcastServer) *grpc.ClientConn {
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	fabricOrderer.RegisterAtomicBroadcastServer(s, srv)
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial(`bufnet`, grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.Dial()
+		}))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// listenConns accepts TCP connections, every accepted connection is signalled
+// to accepted and then to closed when client closes it
+func listenConns(t *testing.T) (host string, accepted, closed <-chan struct{}) {
+	lis, err := net.Listen(`tcp`, `127.0.0.1:0`)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = lis.Close() })
+
+	acceptedCh, closedCh := make(chan struct{}, 16), make(chan struct{}, 16)
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			acceptedCh <- struct{}{}
+			go func() {
+				_, _ = io.Copy(ioutil.Discard, conn)
+				_ = conn.Close()
+				closedCh <- struct{}{}
+			}()
+		}
+	}()
+	return lis.Addr().String(), acceptedCh, closedCh
+}
+
+// requireConnClosed checks that connection is closed by client if it was established
+func requireConnClosed(t *testing.T, accepted, closed <-chan struct{}) {
+	select {
+	case <-accepted:
+	case <-time.After(time.Second):
+		// connection was closed before it was established
+		return
+	}
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal(`connection is not closed`)
+	}
+}
+
+func signingIdentity(t *testing.T) msp.SigningIdentity {
+	cs, err := crypto.GetSuite(ecdsa.Module, ecdsa.DefaultOpts)
+	require.NoError(t, err)
+	id, err := identity.NewMSPIdentityFromPath(`org1msp`, `../client/chaincode/testdata/msp`)
+	require.NoError(t, err)
+	return id.GetSigningIdentity(cs)
+}
+
+func readBlocks(t *testing.T, sub api.BlockSubscription) []uint64 {
+	var numbers []uint64
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case block, ok := <-sub.Blocks():
+			if !ok {
+				return numbers
+			}
+			numbers = append(numbers, block.Header.Number)
+		case <-timeout:
+			t.Fatal(`subscription is not finished`)
+		}
+	}
+}
+
+func TestDeliverClient_SubscribeBlock(t *testing.T) {
+	chain := newChain(10)
+	failing := &mockOrderer{chain: chain, failAfter: 3}
+	healthy := &mockOrderer{chain: chain, failAfter: -1}
+
+	cli, err := orderer.NewDeliverClient(signingIdentity(t),
+		[]*grpc.ClientConn{serve(t, failing), serve(t, healthy)},
+		orderer.WithDeliverRetryDelay(time.Millisecond))
+	require.NoError(t, err)
+
+	t.Run(`resume on next orderer`, func(t *testing.T) {
+		sub, err := cli.SubscribeBlock(context.Background(), `channel`, api.SeekRange(2, 8))
+		require.NoError(t, err)
+		defer sub.Close()
+
+		require.Equal(t, []uint64{2, 3, 4, 5, 6, 7, 8}, readBlocks(t, sub))
+		require.Equal(t, []uint64{2}, failing.seeks)
+		require.Equal(t, []uint64{5}, healthy.seeks)
+		require.NoError(t, <-sub.Errors())
+	})
+
+	t.Run(`fatal status`, func(t *testing.T) {
+		forbidden, err := orderer.NewDeliverClient(signingIdentity(t),
+			[]*grpc.ClientConn{serve(t, &mockOrderer{status: common.Status_FORBIDDEN})})
+		require.NoError(t, err)
+
+		sub, err := forbidden.SubscribeBlock(context.Background(), `channel`, api.SeekOldest())
+		require.NoError(t, err)
+		defer sub.Close()
+
+		require.Empty(t, readBlocks(t, sub))
+		var statusErr *orderer.ErrUnexpectedStatus
+		require.True(t, errors.As(<-sub.Errors(), &statusErr))
+	})
+}
+
+func TestDeliverClient_Close(t *testing.T) {
+	t.Run(`subscriptions are finished`, func(t *testing.T) {
+		conn := serve(t, &mockOrderer{chain: newChain(10), failAfter: -1})
+		cli, err := orderer.NewDeliverClient(signingIdentity(t), []*grpc.ClientConn{conn})
+		require.NoError(t, err)
+
+		// subscription is blocked on sending block which is not read
+		sub, err := cli.SubscribeBlock(context.Background(), `channel`, api.SeekRange(0, 9))
+		require.NoError(t, err)
+		defer sub.Close()
+		time.Sleep(50 * time.Millisecond)
+
+		require.NoError(t, cli.Close())
+		for range sub.Blocks() {
+		}
+		require.Equal(t, orderer.ErrDeliverClientClosed, <-sub.Errors())
+
+		_, err = cli.SubscribeBlock(context.Background(), `channel`)
+		require.Equal(t, orderer.ErrDeliverClientClosed, err)
+
+		// connections presented by caller are not closed
+		require.NotEqual(t, connectivity.Shutdown, conn.GetState())
+	})
+
+	t.Run(`dialed connections are closed`, func(t *testing.T) {
+		host, accepted, closed := listenConns(t)
+		cli, err := orderer.NewDeliverClientFromConfigs(context.Background(), signingIdentity(t), zap.NewNop(),
+			[]config.ConnectionConfig{{Host: host}})
+		require.NoError(t, err)
+
+		select {
+		case <-accepted:
+		case <-time.After(5 * time.Second):
+			t.Fatal(`connection is not established`)
+		}
+		require.NoError(t, cli.Close())
+		select {
+		case <-closed:
+		case <-time.After(5 * time.Second):
+			t.Fatal(`connection is not closed`)
+		}
+	})
+}
+
+func TestNewDeliverClientFromConfigs(t *testing.T) {
+	host, accepted, closed := listenConns(t)
+	_, err := orderer.NewDeliverClientFromConfigs(context.Background(), signingIdentity(t), zap.NewNop(),
+		[]config.ConnectionConfig{
+			{Host: host},
+			{Host: `orderer1:7050`, Tls: config.TlsConfig{Enabled: true, CACertPath: `testdata/missing.pem`}},
+		})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `get GRPC options for orderer1:7050`)
+
+	// connection dialed before failure is closed
+	requireConnClosed(t, accepted, closed)
+}
@@ -16,12 +16,18 @@ import (
 	"github.com/bogatyr285/hlf-sdk-go/util"
 )
 
+// ErrUnexpectedStatus is returned when orderer responds with non-SUCCESS status
 type ErrUnexpectedStatus struct {
-	status common.Status
+	Status common.Status
+	// Endpoint is address of orderer returned status
+	Endpoint string
 }
 
 func (e *ErrUnexpectedStatus) Error() string {
-	return fmt.Sprintf("unexpected status: %s", e.status.String())
+	if e.Endpoint == `` {
+		return fmt.Sprintf("unexpected status: %s", e.Status.String())
+	}
+	return fmt.Sprintf("unexpected status from %s: %s", e.Endpoint, e.Status.String())
 }
 
 type orderer struct {
@@ -34,8 +40,12 @@ type orderer struct {
 	grpcOptions     []grpc.DialOption
 }
 
-func (o *orderer) Broadcast(ctx context.Context, envelope *common.Envelope) (resp *fabricOrderer.BroadcastResponse, err error) {
-	cli, err := o.broadcastClient.Broadcast(ctx)
+func (o *orderer) Broadcast(ctx context.Context, envelope *common.Envelope) (*fabricOrderer.BroadcastResponse, error) {
+	return broadcast(ctx, o.broadcastClient, o.uri, envelope)
+}
+
+func broadcast(ctx context.Context, client fabricOrderer.AtomicBroadcastClient, endpoint string, envelope *common.Envelope) (resp *fabricOrderer.BroadcastResponse, err error) {
+	cli, err := client.Broadcast(ctx)
 	if err != nil {
 		err = fmt.Errorf(`initialize broadcast client: %w`, err)
 		return
@@ -62,7 +72,7 @@ func (o *orderer) Broadcast(ctx context.Context, envelope *common.Envelope) (res
 		return
 	} else {
 		if resp.Status != common.Status_SUCCESS {
-			err = &ErrUnexpectedStatus{status: resp.Status}
+			err = &ErrUnexpectedStatus{Status: resp.Status, Endpoint: endpoint}
 			return
 		}
 	}
@@ -70,8 +80,12 @@ func (o *orderer) Broadcast(ctx context.Context, envelope *common.Envelope) (res
 	return
 }
 
-func (o *orderer) Deliver(ctx context.Context, envelope *common.Envelope) (block *common.Block, err error) {
-	cli, err := o.broadcastClient.Deliver(ctx)
+func (o *orderer) Deliver(ctx context.Context, envelope *common.Envelope) (*common.Block, error) {
+	return deliver(ctx, o.broadcastClient, o.uri, envelope)
+}
+
+func deliver(ctx context.Context, client fabricOrderer.AtomicBroadcastClient, endpoint string, envelope *common.Envelope) (block *common.Block, err error) {
+	cli, err := client.Deliver(ctx)
 	if err != nil {
 		err = fmt.Errorf(`initialize deliver client: %w`, err)
 		return
@@ -95,7 +109,7 @@ func (o *orderer) Deliver(ctx context.Context, envelope *common.Envelope) (block
 			switch respType := resp.Type.(type) {
 			case *fabricOrderer.DeliverResponse_Status:
 				if respType.Status != common.Status_SUCCESS {
-					err = &ErrUnexpectedStatus{status: respType.Status}
+					err = &ErrUnexpectedStatus{Status: respType.Status, Endpoint: endpoint}
 				} else {
 					err = nil
 					return
@@ -165,3 +179,34 @@ func NewFromGRPC(ctx context.Context, conn *grpc.ClientConn, grpcOptions ...grpc
 
 	return obj, nil
 }
+
+// dialConns dials every configured orderer, connections dialed before failure are closed
+func dialConns(ctx context.Context, log *zap.Logger, configs []config.ConnectionConfig) ([]*grpc.ClientConn, error) {
+	conns := make([]*grpc.ClientConn, 0, len(configs))
+	for _, c := range configs {
+		grpcOpts, err := util.NewGRPCOptionsFromConfig(c, log)
+		if err != nil {
+			_ = closeConns(conns)
+			return nil, fmt.Errorf(`get GRPC options for %s: %w`, c.Host, err)
+		}
+
+		conn, err := grpc.DialContext(ctx, c.Host, grpcOpts...)
+		if err != nil {
+			_ = closeConns(conns)
+			return nil, fmt.Errorf(`initialize GRPC connection to %s: %w`, c.Host, err)
+		}
+		conns = append(conns, conn)
+	}
+	return conns, nil
+}
+
+// closeConns closes all connections, returns first error
+func closeConns(conns []*grpc.ClientConn) error {
+	var err error
+	for _, conn := range conns {
+		if cErr := conn.Close(); cErr != nil && err == nil {
+			err = fmt.Errorf(`close connection to %s: %w`, conn.Target(), cErr)
+		}
+	}
+	return err
+}
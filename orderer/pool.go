@@ -0,0 +1,261 @@
+package orderer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	fabricOrderer "github.com/hyperledger/fabric-protos-go/orderer"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/api/config"
+)
+
+const (
+	DefaultPoolRetries    = 3
+	DefaultPoolBackoff    = 100 * time.Millisecond
+	DefaultPoolMaxBackoff = 5 * time.Second
+)
+
+// pool sends requests to healthy orderer endpoints in round-robin order,
+// failed requests are retried with backoff on next endpoint
+type pool struct {
+	endpoints []*poolEndpoint
+	log       *zap.Logger
+
+	retries    int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	streaming  bool
+	// conns dialed by pool itself, closed on Close
+	conns []*grpc.ClientConn
+
+	next   int
+	nextMx sync.Mutex
+}
+
+type poolEndpoint struct {
+	uri    string
+	client fabricOrderer.AtomicBroadcastClient
+	// stream is long-lived broadcast stream, nil if streaming is disabled
+	stream *broadcastStream
+
+	mx             sync.Mutex
+	failures       int
+	unhealthyUntil time.Time
+}
+
+type PoolOpt func(*pool)
+
+// WithPoolLogger sets logger used for reporting failed endpoints
+func WithPoolLogger(log *zap.Logger) PoolOpt {
+	return func(p *pool) {
+		p.log = log
+	}
+}
+
+// WithPoolRetries sets count of retries after first failed attempt
+func WithPoolRetries(retries int) PoolOpt {
+	return func(p *pool) {
+		p.retries = retries
+	}
+}
+
+// WithPoolBackoff sets initial and max time for which failed endpoint is skipped,
+// time is doubled on every consecutive failure of endpoint
+func WithPoolBackoff(backoff, maxBackoff time.Duration) PoolOpt {
+	return func(p *pool) {
+		p.backoff = backoff
+		p.maxBackoff = maxBackoff
+	}
+}
+
+// WithPoolBroadcastStream enables long-lived broadcast stream per orderer instead of stream per Broadcast call,
+// envelopes of concurrent calls are pipelined into one stream
+func WithPoolBroadcastStream() PoolOpt {
+	return func(p *pool) {
+		p.streaming = true
+	}
+}
+
+// NewPool returns orderer which tracks health of every endpoint and retries
+// broadcast and deliver on next endpoint when orderer is unavailable
+func NewPool(conns []*grpc.ClientConn, opts ...PoolOpt) (api.Orderer, error) {
+	if len(conns) == 0 {
+		return nil, ErrNoOrdererConnections
+	}
+
+	p := &pool{
+		log:        zap.NewNop(),
+		retries:    DefaultPoolRetries,
+		backoff:    DefaultPoolBackoff,
+		maxBackoff: DefaultPoolMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	for _, conn := range conns {
+		e := &poolEndpoint{
+			uri:    conn.Target(),
+			client: fabricOrderer.NewAtomicBroadcastClient(conn),
+		}
+		if p.streaming {
+			e.stream = newBroadcastStream(e.client, e.uri)
+		}
+		p.endpoints = append(p.endpoints, e)
+	}
+
+	return p, nil
+}
+
+// NewPoolFromConfigs dials every configured orderer and returns orderer pool
+func NewPoolFromConfigs(ctx context.Context, log *zap.Logger, configs []config.ConnectionConfig, opts ...PoolOpt) (api.Orderer, error) {
+	conns, err := dialConns(ctx, log, configs)
+	if err != nil {
+		return nil, err
+	}
+
+	ord, err := NewPool(conns, append([]PoolOpt{WithPoolLogger(log)}, opts...)...)
+	if err != nil {
+		_ = closeConns(conns)
+		return nil, err
+	}
+	ord.(*pool).conns = conns
+
+	return ord, nil
+}
+
+// Close closes broadcast streams of all endpoints, pending broadcasts receive ErrBroadcastStreamClosed.
+// Connections are closed only if they were dialed by pool
+func (p *pool) Close() error {
+	for _, e := range p.endpoints {
+		if e.stream != nil {
+			_ = e.stream.Close()
+		}
+	}
+
+	return closeConns(p.conns)
+}
+
+func (p *pool) Broadcast(ctx context.Context, envelope *common.Envelope) (resp *fabricOrderer.BroadcastResponse, err error) {
+	err = p.do(ctx, func(e *poolEndpoint) (err error) {
+		if e.stream != nil {
+			resp, err = e.stream.Broadcast(ctx, envelope)
+		} else {
+			resp, err = broadcast(ctx, e.client, e.uri, envelope)
+		}
+		return err
+	})
+	return resp, err
+}
+
+func (p *pool) Deliver(ctx context.Context, envelope *common.Envelope) (block *common.Block, err error) {
+	err = p.do(ctx, func(e *poolEndpoint) (err error) {
+		block, err = deliver(ctx, e.client, e.uri, envelope)
+		return err
+	})
+	return block, err
+}
+
+// do calls f on endpoints until it succeeds, returns non-retryable error or retries are exhausted.
+// Failed endpoint is skipped until its backoff expires, so retry goes to next healthy endpoint
+// or waits for the endpoint which recovers first
+func (p *pool) do(ctx context.Context, f func(e *poolEndpoint) error) error {
+	var err error
+	for attempt := 0; attempt <= p.retries; attempt++ {
+		e, wait := p.endpoint()
+		if wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				if err == nil {
+					return ctx.Err()
+				}
+				return fmt.Errorf(`%s: %w`, err.Error(), ctx.Err())
+			}
+		}
+
+		if err = f(e); err == nil {
+			e.succeeded()
+			return nil
+		}
+
+		if ctx.Err() != nil || errors.Is(err, ErrBroadcastStreamClosed) {
+			return err
+		}
+
+		var statusErr *ErrUnexpectedStatus
+		if errors.As(err, &statusErr) && !retryableStatus(statusErr.Status) {
+			// orderer is alive but rejected request, retry will not help
+			e.succeeded()
+			return err
+		}
+
+		e.failed(p.delay)
+		p.log.Warn(`orderer request failed`, zap.String(`orderer`, e.uri), zap.Int(`attempt`, attempt), zap.Error(err))
+	}
+
+	return err
+}
+
+// endpoint returns next healthy endpoint in round-robin order,
+// if all endpoints are unhealthy returns one which will recover first and time to wait for it
+func (p *pool) endpoint() (*poolEndpoint, time.Duration) {
+	p.nextMx.Lock()
+	start := p.next
+	p.next = (p.next + 1) % len(p.endpoints)
+	p.nextMx.Unlock()
+
+	now := time.Now()
+	var candidate *poolEndpoint
+	var candidateUntil time.Time
+	for i := 0; i < len(p.endpoints); i++ {
+		e := p.endpoints[(start+i)%len(p.endpoints)]
+		until := e.healthyAt()
+		if !until.After(now) {
+			return e, 0
+		}
+		if candidate == nil || until.Before(candidateUntil) {
+			candidate, candidateUntil = e, until
+		}
+	}
+
+	return candidate, candidateUntil.Sub(now)
+}
+
+func (p *pool) delay(failures int) time.Duration {
+	delay := p.backoff
+	for i := 1; i < failures && delay < p.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.maxBackoff {
+		delay = p.maxBackoff
+	}
+	return delay
+}
+
+func (e *poolEndpoint) healthyAt() time.Time {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+	return e.unhealthyUntil
+}
+
+func (e *poolEndpoint) succeeded() {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+	e.failures = 0
+	e.unhealthyUntil = time.Time{}
+}
+
+// failed marks endpoint unhealthy for delay growing with count of consecutive failures
+func (e *poolEndpoint) failed(delay func(failures int) time.Duration) {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+	e.failures++
+	e.unhealthyUntil = time.Now().Add(delay(e.failures))
+}
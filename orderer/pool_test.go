@@ -0,0 +1,74 @@
+package orderer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/bogatyr285/hlf-sdk-go/api/config"
+	"github.com/bogatyr285/hlf-sdk-go/orderer"
+)
+
+func TestPool_Broadcast(t *testing.T) {
+	unavailable := &mockOrderer{broadcastStatus: common.Status_SERVICE_UNAVAILABLE}
+	healthy := &mockOrderer{}
+
+	ord, err := orderer.NewPool([]*grpc.ClientConn{serve(t, unavailable), serve(t, healthy)},
+		orderer.WithPoolBackoff(time.Minute, time.Minute))
+	require.NoError(t, err)
+
+	t.Run(`retry on next orderer`, func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			resp, err := ord.Broadcast(context.Background(), &common.Envelope{})
+			require.NoError(t, err)
+			require.Equal(t, common.Status_SUCCESS, resp.Status)
+		}
+		// unavailable orderer is skipped until backoff expires
+		require.Equal(t, 1, unavailable.broadcastCount())
+		require.Equal(t, 3, healthy.broadcastCount())
+	})
+
+	t.Run(`non-retryable status`, func(t *testing.T) {
+		forbidden, err := orderer.NewPool([]*grpc.ClientConn{
+			serve(t, &mockOrderer{broadcastStatus: common.Status_FORBIDDEN}), serve(t, healthy)})
+		require.NoError(t, err)
+
+		_, err = forbidden.Broadcast(context.Background(), &common.Envelope{})
+		var statusErr *orderer.ErrUnexpectedStatus
+		require.True(t, errors.As(err, &statusErr))
+		require.Equal(t, common.Status_FORBIDDEN, statusErr.Status)
+		require.Equal(t, `bufnet`, statusErr.Endpoint)
+	})
+
+	t.Run(`retries exhausted`, func(t *testing.T) {
+		down, err := orderer.NewPool([]*grpc.ClientConn{serve(t, unavailable)},
+			orderer.WithPoolRetries(2), orderer.WithPoolBackoff(time.Millisecond, time.Millisecond))
+		require.NoError(t, err)
+
+		before := unavailable.broadcastCount()
+		_, err = down.Broadcast(context.Background(), &common.Envelope{})
+		var statusErr *orderer.ErrUnexpectedStatus
+		require.True(t, errors.As(err, &statusErr))
+		require.Equal(t, common.Status_SERVICE_UNAVAILABLE, statusErr.Status)
+		require.Equal(t, before+3, unavailable.broadcastCount())
+	})
+}
+
+func TestNewPoolFromConfigs(t *testing.T) {
+	host, accepted, closed := listenConns(t)
+	_, err := orderer.NewPoolFromConfigs(context.Background(), zap.NewNop(), []config.ConnectionConfig{
+		{Host: host},
+		{Host: `orderer1:7050`, Tls: config.TlsConfig{Enabled: true, CACertPath: `testdata/missing.pem`}},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `get GRPC options for orderer1:7050`)
+
+	// connection dialed before failure is closed
+	requireConnClosed(t, accepted, closed)
+}
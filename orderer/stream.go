@@ -0,0 +1,168 @@
+package orderer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	fabricOrderer "github.com/hyperledger/fabric-protos-go/orderer"
+)
+
+var (
+	ErrBroadcastStreamClosed = errors.New(`broadcast stream closed`)
+)
+
+// broadcastStream is long-lived broadcast stream which pipelines envelopes.
+// Orderer responds in order of received envelopes, so responses are matched to pending requests in order of sending.
+// Stream is re-opened on next request after failure, requests pending on failed stream receive its error
+type broadcastStream struct {
+	client   fabricOrderer.AtomicBroadcastClient
+	endpoint string
+
+	// mx guards current stream and keeps order of sends
+	mx      sync.Mutex
+	current *broadcastStreamConn
+	closed  bool
+}
+
+type broadcastStreamConn struct {
+	stream fabricOrderer.AtomicBroadcast_BroadcastClient
+	cancel context.CancelFunc
+
+	// pendingMx is separate from send lock, so receiving is not blocked by send waiting for flow control
+	pendingMx sync.Mutex
+	pending   []chan broadcastResult
+	err       error
+}
+
+type broadcastResult struct {
+	resp *fabricOrderer.BroadcastResponse
+	err  error
+}
+
+func newBroadcastStream(client fabricOrderer.AtomicBroadcastClient, endpoint string) *broadcastStream {
+	return &broadcastStream{client: client, endpoint: endpoint}
+}
+
+func (s *broadcastStream) Broadcast(ctx context.Context, envelope *common.Envelope) (*fabricOrderer.BroadcastResponse, error) {
+	result, err := s.send(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case r := <-result:
+		return r.resp, r.err
+	case <-ctx.Done():
+		// response will be dropped when received, result channel is buffered
+		return nil, ctx.Err()
+	}
+}
+
+func (s *broadcastStream) send(envelope *common.Envelope) (chan broadcastResult, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.closed {
+		return nil, ErrBroadcastStreamClosed
+	}
+
+	if s.current == nil || s.current.failed() {
+		ctx, cancel := context.WithCancel(context.Background())
+		stream, err := s.client.Broadcast(ctx)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf(`initialize broadcast client: %w`, err)
+		}
+		s.current = &broadcastStreamConn{stream: stream, cancel: cancel}
+		go s.current.receive(s.endpoint)
+	}
+
+	result, err := s.current.enqueue()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.current.stream.Send(envelope); err != nil {
+		err = fmt.Errorf(`send envelope: %w`, err)
+		s.current.fail(err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Close closes stream, pending requests receive ErrBroadcastStreamClosed
+func (s *broadcastStream) Close() error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.closed = true
+	if s.current != nil {
+		s.current.fail(ErrBroadcastStreamClosed)
+	}
+	return nil
+}
+
+func (c *broadcastStreamConn) enqueue() (chan broadcastResult, error) {
+	c.pendingMx.Lock()
+	defer c.pendingMx.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	result := make(chan broadcastResult, 1)
+	c.pending = append(c.pending, result)
+	return result, nil
+}
+
+func (c *broadcastStreamConn) receive(endpoint string) {
+	for {
+		resp, err := c.stream.Recv()
+		if err != nil {
+			c.fail(fmt.Errorf(`receive response: %w`, err))
+			return
+		}
+
+		c.pendingMx.Lock()
+		if len(c.pending) == 0 {
+			c.pendingMx.Unlock()
+			c.fail(errors.New(`receive response: unexpected response without request`))
+			return
+		}
+		result := c.pending[0]
+		c.pending = c.pending[1:]
+		c.pendingMx.Unlock()
+
+		if resp.Status != common.Status_SUCCESS {
+			result <- broadcastResult{resp: resp, err: &ErrUnexpectedStatus{Status: resp.Status, Endpoint: endpoint}}
+		} else {
+			result <- broadcastResult{resp: resp}
+		}
+	}
+}
+
+func (c *broadcastStreamConn) failed() bool {
+	c.pendingMx.Lock()
+	defer c.pendingMx.Unlock()
+	return c.err != nil
+}
+
+// fail closes stream and returns err to pending requests, first error is kept
+func (c *broadcastStreamConn) fail(err error) {
+	c.cancel()
+
+	c.pendingMx.Lock()
+	defer c.pendingMx.Unlock()
+
+	if c.err == nil {
+		c.err = err
+	}
+	for _, result := range c.pending {
+		result <- broadcastResult{err: c.err}
+	}
+	c.pending = nil
+}
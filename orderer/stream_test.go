@@ -0,0 +1,108 @@
+package orderer_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/orderer"
+)
+
+func TestPool_BroadcastStream(t *testing.T) {
+	t.Run(`responses match requests`, func(t *testing.T) {
+		ord, err := orderer.NewPool([]*grpc.ClientConn{serve(t, &mockOrderer{})}, orderer.WithPoolBroadcastStream())
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				payload := fmt.Sprintf("envelope-%d", i)
+				resp, err := ord.Broadcast(context.Background(), &common.Envelope{Payload: []byte(payload)})
+				require.NoError(t, err)
+				require.Equal(t, payload, resp.Info)
+			}(i)
+		}
+		wg.Wait()
+	})
+
+	t.Run(`reopen after failure`, func(t *testing.T) {
+		failing := &mockOrderer{broadcastFailAfter: 2}
+		ord, err := orderer.NewPool([]*grpc.ClientConn{serve(t, failing)},
+			orderer.WithPoolBroadcastStream(), orderer.WithPoolBackoff(time.Millisecond, time.Millisecond))
+		require.NoError(t, err)
+
+		for i := 0; i < 5; i++ {
+			resp, err := ord.Broadcast(context.Background(), &common.Envelope{Payload: []byte(`envelope`)})
+			require.NoError(t, err)
+			require.Equal(t, `envelope`, resp.Info)
+		}
+		require.Equal(t, 5, failing.broadcastCount())
+	})
+
+	t.Run(`close fails pending broadcasts`, func(t *testing.T) {
+		hold := make(chan struct{})
+		defer close(hold)
+
+		mock := &mockOrderer{broadcastHold: hold}
+		ord, err := orderer.NewPool([]*grpc.ClientConn{serve(t, mock)}, orderer.WithPoolBroadcastStream())
+		require.NoError(t, err)
+
+		errs := make(chan error, 3)
+		for i := 0; i < cap(errs); i++ {
+			go func() {
+				_, err := ord.Broadcast(context.Background(), &common.Envelope{Payload: []byte(`envelope`)})
+				errs <- err
+			}()
+		}
+
+		// first envelope is received by orderer, response is held
+		require.Eventually(t, func() bool { return mock.broadcastCount() > 0 }, time.Second, time.Millisecond)
+
+		closer, ok := ord.(io.Closer)
+		require.True(t, ok)
+		require.NoError(t, closer.Close())
+
+		for i := 0; i < cap(errs); i++ {
+			require.True(t, errors.Is(<-errs, orderer.ErrBroadcastStreamClosed))
+		}
+
+		_, err = ord.Broadcast(context.Background(), &common.Envelope{Payload: []byte(`envelope`)})
+		require.True(t, errors.Is(err, orderer.ErrBroadcastStreamClosed))
+	})
+}
+
+func benchmarkBroadcast(b *testing.B, ord api.Orderer) {
+	envelope := &common.Envelope{Payload: []byte(`envelope`)}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := ord.Broadcast(context.Background(), envelope); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkBroadcast_PerCallStream(b *testing.B) {
+	ord, err := orderer.NewFromGRPC(context.Background(), serve(b, &mockOrderer{}))
+	require.NoError(b, err)
+	benchmarkBroadcast(b, ord)
+}
+
+func BenchmarkBroadcast_PersistentStream(b *testing.B) {
+	ord, err := orderer.NewPool([]*grpc.ClientConn{serve(b, &mockOrderer{})}, orderer.WithPoolBroadcastStream())
+	require.NoError(b, err)
+	benchmarkBroadcast(b, ord)
+}
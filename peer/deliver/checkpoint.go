@@ -0,0 +1,344 @@
+package deliver
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/pkg/errors"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/util"
+	"github.com/bogatyr285/hlf-sdk-go/util/txflags"
+)
+
+const (
+	DefaultCheckpointRetryDelay = time.Second
+	// DefaultCheckpointSaveInterval is count of processed blocks without events after which position is saved
+	DefaultCheckpointSaveInterval = 100
+)
+
+var (
+	// ErrCheckpointAckDisabled is returned by Ack of subscription created without api.WithCheckpointAck
+	ErrCheckpointAckDisabled = errors.New(`checkpoint ack is not enabled`)
+	// ErrCheckpointSubscriptionClosed is returned by Ack of closed subscription
+	ErrCheckpointSubscriptionClosed = errors.New(`checkpoint subscription is closed`)
+)
+
+func (d *deliverImpl) SubscribeCCCheckpointed(ctx context.Context, channelName string, ccName string, store api.CheckpointStore, opts ...api.CheckpointOpt) (api.CheckpointSubscription, error) {
+	options := &api.CheckpointOpts{
+		Key:          channelName + `/` + ccName,
+		Seek:         api.SeekOldest(),
+		RetryDelay:   DefaultCheckpointRetryDelay,
+		SaveInterval: DefaultCheckpointSaveInterval,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	checkpoint, err := store.Load(options.Key)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to load checkpoint`)
+	}
+
+	start, stop := options.Seek()
+	subCtx, cancel := context.WithCancel(ctx)
+
+	s := &checkpointSubscription{
+		ctx:        subCtx,
+		cancel:     cancel,
+		deliver:    d,
+		channel:    channelName,
+		ccName:     ccName,
+		store:      store,
+		opts:       options,
+		checkpoint: checkpoint,
+		start:      start,
+		stop:       stop,
+		events:     make(chan *peer.ChaincodeEvent),
+		errors:     make(chan error, 1),
+		acks:       make(chan *peer.ChaincodeEvent),
+		ackResults: make(chan error),
+		done:       make(chan struct{}),
+	}
+
+	go s.run()
+
+	return s, nil
+}
+
+// checkpointSubscription delivers chaincode events using sequence of deliver streams,
+// every next stream starts from block of last saved checkpoint
+type checkpointSubscription struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	deliver *deliverImpl
+	channel string
+	ccName  string
+	store   api.CheckpointStore
+	opts    *api.CheckpointOpts
+
+	// checkpoint is position of last delivered event or last processed block, nil if nothing is processed yet.
+	// Position of blocks without events is saved to store every SaveInterval blocks, unsaved is count of such blocks
+	checkpoint *api.EventCheckpoint
+	unsaved    int
+	start      *orderer.SeekPosition
+	stop       *orderer.SeekPosition
+
+	// lastBlock is number of last block received from current stream, nil if stream has not sent blocks yet
+	lastBlock *uint64
+	// handleErr is error which terminated block handling of current stream
+	handleErr error
+
+	events chan *peer.ChaincodeEvent
+	errors chan error
+	// acks passes acknowledged events to block handler, result of saving is returned by ackResults
+	acks       chan *peer.ChaincodeEvent
+	ackResults chan error
+	done       chan struct{}
+}
+
+func (s *checkpointSubscription) Events() chan *peer.ChaincodeEvent {
+	return s.events
+}
+
+func (s *checkpointSubscription) Errors() chan error {
+	return s.errors
+}
+
+func (s *checkpointSubscription) Ack(event *peer.ChaincodeEvent) error {
+	if !s.opts.Ack {
+		return ErrCheckpointAckDisabled
+	}
+
+	select {
+	case s.acks <- event:
+	case <-s.done:
+		return ErrCheckpointSubscriptionClosed
+	}
+
+	select {
+	case err := <-s.ackResults:
+		return err
+	case <-s.done:
+		return ErrCheckpointSubscriptionClosed
+	}
+}
+
+func (s *checkpointSubscription) Close() error {
+	s.cancel()
+	<-s.done
+	return nil
+}
+
+func (s *checkpointSubscription) run() {
+	defer close(s.done)
+	defer close(s.errors)
+	defer close(s.events)
+
+	retries := 0
+	for {
+		before := s.checkpoint
+		err := s.subscribe()
+		if s.ctx.Err() != nil {
+			return
+		}
+
+		if err == nil {
+			if s.stopReached() {
+				return
+			}
+			// stream is closed by peer before stop position
+			err = api.GRPCStreamError{Err: errors.New(`deliver stream closed`)}
+		}
+
+		if !retryable(err) {
+			s.errors <- err
+			return
+		}
+
+		if s.checkpoint != before {
+			retries = 0
+		}
+		retries++
+		if s.opts.MaxRetries > 0 && retries > s.opts.MaxRetries {
+			s.errors <- errors.Wrap(err, `subscription retries exceeded`)
+			return
+		}
+
+		select {
+		case <-time.After(s.opts.RetryDelay):
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// subscribe handles single deliver stream and returns error which terminated it
+func (s *checkpointSubscription) subscribe() error {
+	start := s.start
+	if s.checkpoint != nil {
+		// block of checkpoint is read again to deliver events of remaining transactions
+		start = &orderer.SeekPosition{Type: &orderer.SeekPosition_Specified{
+			Specified: &orderer.SeekSpecified{Number: s.checkpoint.BlockNumber}}}
+	}
+	stop := s.stop
+
+	s.lastBlock, s.handleErr = nil, nil
+	sub, err := s.deliver.handleSubscription(s.ctx, s.channel, s.handleBlock, func() (*orderer.SeekPosition, *orderer.SeekPosition) {
+		return start, stop
+	})
+	if err != nil {
+		return api.GRPCStreamError{Err: err}
+	}
+
+	sub.readyForHandling()
+	// error channel is closed when stream handling is finished
+	err = <-sub.Err()
+	if s.handleErr != nil {
+		return s.handleErr
+	}
+
+	return err
+}
+
+func (s *checkpointSubscription) handleBlock(block *common.Block) bool {
+	if block == nil {
+		return false
+	}
+
+	number := block.GetHeader().GetNumber()
+	if expected, ok := s.expectedBlock(); ok && number != expected {
+		s.handleErr = api.ErrBlockGap{Expected: expected, Received: number}
+		return true
+	}
+	s.lastBlock = &number
+
+	txFilter := txflags.ValidationFlags(block.GetMetadata().GetMetadata()[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	data := block.GetData().GetData()
+	for i, envelope := range data {
+		if s.checkpoint != nil && s.checkpoint.BlockNumber == number && i <= s.checkpoint.TxIndex {
+			continue
+		}
+		if !txFilter.IsValid(i) {
+			continue
+		}
+
+		event, err := util.GetEventFromEnvelope(envelope)
+		if err != nil {
+			if util.IsErrUnsupportedTxType(err) {
+				continue
+			}
+			s.handleErr = errors.Wrapf(err, "failed to get event from tx %d of block %d", i, number)
+			return true
+		}
+		if event.GetChaincodeId() != s.ccName {
+			continue
+		}
+
+		select {
+		case s.events <- event:
+		case <-s.ctx.Done():
+			return true
+		}
+
+		if s.opts.Ack {
+			if !s.waitAck(event, number, i) {
+				return true
+			}
+		} else if err = s.save(number, i); err != nil {
+			s.handleErr = err
+			return true
+		}
+	}
+
+	// whole block is processed, so it is not read again after reconnect,
+	// position of block is saved only if enough blocks without saved position are processed
+	position := &api.EventCheckpoint{BlockNumber: number, TxIndex: len(data) - 1}
+	if s.checkpoint == nil || s.checkpoint.BlockNumber != number {
+		s.unsaved++
+	}
+	if s.unsaved > 0 && ((s.opts.SaveInterval > 0 && s.unsaved >= s.opts.SaveInterval) || s.isStop(number)) {
+		if err := s.save(position.BlockNumber, position.TxIndex); err != nil {
+			s.handleErr = err
+			return true
+		}
+	}
+	s.checkpoint = position
+
+	return false
+}
+
+// waitAck waits until consumer acknowledges delivered event and saves its position,
+// returns false if subscription is closed or position is not saved
+func (s *checkpointSubscription) waitAck(event *peer.ChaincodeEvent, blockNumber uint64, txIndex int) bool {
+	for {
+		select {
+		case acked := <-s.acks:
+			if acked.GetTxId() != event.TxId {
+				s.ackResults <- errors.Errorf("acknowledged event of tx %s, expected event of tx %s", acked.GetTxId(), event.TxId)
+				continue
+			}
+
+			err := s.save(blockNumber, txIndex)
+			s.ackResults <- err
+			if err != nil {
+				s.handleErr = err
+				return false
+			}
+			return true
+
+		case <-s.ctx.Done():
+			return false
+		}
+	}
+}
+
+// expectedBlock returns number of block expected from current stream
+func (s *checkpointSubscription) expectedBlock() (uint64, bool) {
+	if s.lastBlock != nil {
+		return *s.lastBlock + 1, true
+	}
+	if s.checkpoint != nil {
+		return s.checkpoint.BlockNumber, true
+	}
+	return 0, false
+}
+
+func (s *checkpointSubscription) save(blockNumber uint64, txIndex int) error {
+	checkpoint := &api.EventCheckpoint{BlockNumber: blockNumber, TxIndex: txIndex}
+	if err := s.store.Save(s.opts.Key, *checkpoint); err != nil {
+		return errors.Wrap(err, `failed to save checkpoint`)
+	}
+	s.checkpoint, s.unsaved = checkpoint, 0
+	return nil
+}
+
+func (s *checkpointSubscription) stopReached() bool {
+	return s.checkpoint != nil && s.isStop(s.checkpoint.BlockNumber)
+}
+
+// isStop returns true if block is at or after stop position of subscription
+func (s *checkpointSubscription) isStop(blockNumber uint64) bool {
+	stop := s.stop.GetSpecified()
+	if stop == nil || stop.Number == math.MaxUint64 {
+		return false
+	}
+	return blockNumber >= stop.Number
+}
+
+// retryable returns true if subscription can be resumed with new stream: on transport errors and
+// SERVICE_UNAVAILABLE status. Other statuses, e.g. FORBIDDEN, and gaps in block numbers are not retried
+func retryable(err error) bool {
+	switch e := err.(type) {
+	case api.GRPCStreamError:
+		return true
+	case api.ErrUnexpectedDeliverStatus:
+		return e.Status == common.Status_SERVICE_UNAVAILABLE
+	default:
+		return false
+	}
+}
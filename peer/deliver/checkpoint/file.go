@@ -0,0 +1,93 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+)
+
+type fileStore struct {
+	path string
+	mx   sync.Mutex
+}
+
+// NewFileStore returns checkpoint store which keeps checkpoints of all keys in JSON file.
+// File is replaced atomically on every save, so checkpoints survive process restart
+func NewFileStore(path string) api.CheckpointStore {
+	return &fileStore{path: path}
+}
+
+func (f *fileStore) Load(key string) (*api.EventCheckpoint, error) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	checkpoints, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint, ok := checkpoints[key]
+	if !ok {
+		return nil, nil
+	}
+	return &checkpoint, nil
+}
+
+func (f *fileStore) Save(key string, checkpoint api.EventCheckpoint) error {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	checkpoints, err := f.read()
+	if err != nil {
+		return err
+	}
+	checkpoints[key] = checkpoint
+
+	raw, err := json.Marshal(checkpoints)
+	if err != nil {
+		return errors.Wrap(err, `failed to marshal checkpoints`)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(f.path), filepath.Base(f.path)+`.*.tmp`)
+	if err != nil {
+		return errors.Wrap(err, `failed to create temp file`)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err = tmp.Write(raw); err != nil {
+		_ = tmp.Close()
+		return errors.Wrap(err, `failed to write checkpoints`)
+	}
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return errors.Wrap(err, `failed to sync checkpoints`)
+	}
+	if err = tmp.Close(); err != nil {
+		return errors.Wrap(err, `failed to close temp file`)
+	}
+
+	return errors.Wrap(os.Rename(tmp.Name(), f.path), `failed to replace checkpoints file`)
+}
+
+func (f *fileStore) read() (map[string]api.EventCheckpoint, error) {
+	checkpoints := make(map[string]api.EventCheckpoint)
+
+	raw, err := ioutil.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return checkpoints, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to read checkpoints file`)
+	}
+
+	if err = json.Unmarshal(raw, &checkpoints); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal checkpoints`)
+	}
+	return checkpoints, nil
+}
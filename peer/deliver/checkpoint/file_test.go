@@ -0,0 +1,37 @@
+package checkpoint_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/peer/deliver/checkpoint"
+)
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir(``, `checkpoint`)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, `checkpoints.json`)
+
+	store := checkpoint.NewFileStore(path)
+	saved, err := store.Load(`channel/cc`)
+	require.NoError(t, err)
+	require.Nil(t, saved)
+
+	require.NoError(t, store.Save(`channel/cc`, api.EventCheckpoint{BlockNumber: 10, TxIndex: 2}))
+	require.NoError(t, store.Save(`channel/other`, api.EventCheckpoint{BlockNumber: 5}))
+
+	// checkpoints are read by new store, e.g. after restart
+	saved, err = checkpoint.NewFileStore(path).Load(`channel/cc`)
+	require.NoError(t, err)
+	require.Equal(t, &api.EventCheckpoint{BlockNumber: 10, TxIndex: 2}, saved)
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+}
@@ -0,0 +1,36 @@
+package checkpoint
+
+import (
+	"sync"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+)
+
+type memoryStore struct {
+	mx          sync.RWMutex
+	checkpoints map[string]api.EventCheckpoint
+}
+
+// NewMemoryStore returns checkpoint store which keeps checkpoints in memory, e.g. for resuming after stream failures
+func NewMemoryStore() api.CheckpointStore {
+	return &memoryStore{checkpoints: make(map[string]api.EventCheckpoint)}
+}
+
+func (m *memoryStore) Load(key string) (*api.EventCheckpoint, error) {
+	m.mx.RLock()
+	defer m.mx.RUnlock()
+
+	checkpoint, ok := m.checkpoints[key]
+	if !ok {
+		return nil, nil
+	}
+	return &checkpoint, nil
+}
+
+func (m *memoryStore) Save(key string, checkpoint api.EventCheckpoint) error {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	m.checkpoints[key] = checkpoint
+	return nil
+}
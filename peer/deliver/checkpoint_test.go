@@ -0,0 +1,384 @@
+package deliver_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/crypto"
+	"github.com/bogatyr285/hlf-sdk-go/crypto/ecdsa"
+	"github.com/bogatyr285/hlf-sdk-go/identity"
+	"github.com/bogatyr285/hlf-sdk-go/peer/deliver"
+	"github.com/bogatyr285/hlf-sdk-go/peer/deliver/checkpoint"
+)
+
+// mockDeliver serves chain, filtered blocks or blocks with private data,
+// stream number i fails with Unavailable after failAfter[i] blocks or sends failStatus[i] if it is set
+type mockDeliver struct {
+	peer.DeliverClient
+	chain      []*common.Block
+	filtered   []*peer.FilteredBlock
+	pvt        []*peer.BlockAndPrivateData
+	failAfter  []int
+	failStatus []common.Status
+	streams    int
+}
+
+func (m *mockDeliver) Deliver(ctx context.Context, _ ...grpc.CallOption) (peer.Deliver_DeliverClient, error) {
+	responses := make([]*peer.DeliverResponse, len(m.chain))
+	for i, block := range m.chain {
+		responses[i] = &peer.DeliverResponse{Type: &peer.DeliverResponse_Block{Block: block}}
+	}
+	return m.stream(ctx, responses), nil
+}
+
+func (m *mockDeliver) DeliverFiltered(ctx context.Context, _ ...grpc.CallOption) (peer.Deliver_DeliverFilteredClient, error) {
+	responses := make([]*peer.DeliverResponse, len(m.filtered))
+	for i, block := range m.filtered {
+		responses[i] = &peer.DeliverResponse{Type: &peer.DeliverResponse_FilteredBlock{FilteredBlock: block}}
+	}
+	return m.stream(ctx, responses), nil
+}
+
+func (m *mockDeliver) DeliverWithPrivateData(ctx context.Context, _ ...grpc.CallOption) (peer.Deliver_DeliverWithPrivateDataClient, error) {
+	responses := make([]*peer.DeliverResponse, len(m.pvt))
+	for i, block := range m.pvt {
+		responses[i] = &peer.DeliverResponse{Type: &peer.DeliverResponse_BlockAndPrivateData{BlockAndPrivateData: block}}
+	}
+	return m.stream(ctx, responses), nil
+}
+
+func (m *mockDeliver) stream(ctx context.Context, responses []*peer.DeliverResponse) *mockDeliverStream {
+	failAfter, failStatus := -1, common.Status_UNKNOWN
+	if m.streams < len(m.failAfter) {
+		failAfter = m.failAfter[m.streams]
+	}
+	if m.streams < len(m.failStatus) {
+		failStatus = m.failStatus[m.streams]
+	}
+	m.streams++
+	return &mockDeliverStream{ctx: ctx, chain: responses, failAfter: failAfter, failStatus: failStatus}
+}
+
+type mockDeliverStream struct {
+	grpc.ClientStream
+	ctx        context.Context
+	chain      []*peer.DeliverResponse
+	failAfter  int
+	failStatus common.Status
+	next       uint64
+	stop       uint64
+	sent       int
+}
+
+func (s *mockDeliverStream) Send(env *common.Envelope) error {
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return err
+	}
+	seekInfo := new(orderer.SeekInfo)
+	if err = proto.Unmarshal(payload.Data, seekInfo); err != nil {
+		return err
+	}
+	s.next = seekInfo.GetStart().GetSpecified().GetNumber()
+	s.stop = seekInfo.GetStop().GetSpecified().GetNumber()
+	return nil
+}
+
+func (s *mockDeliverStream) Recv() (*peer.DeliverResponse, error) {
+	if s.sent == s.failAfter {
+		if s.failStatus != common.Status_UNKNOWN {
+			// stream is closed after status
+			s.sent++
+			return &peer.DeliverResponse{Type: &peer.DeliverResponse_Status{Status: s.failStatus}}, nil
+		}
+		return nil, status.Error(codes.Unavailable, `peer is unavailable`)
+	}
+	if s.sent > s.failAfter && s.failAfter >= 0 && s.failStatus != common.Status_UNKNOWN {
+		return nil, io.EOF
+	}
+	if s.next > s.stop {
+		return nil, io.EOF
+	}
+	if s.next >= uint64(len(s.chain)) {
+		<-s.ctx.Done()
+		return nil, status.Error(codes.Canceled, s.ctx.Err().Error())
+	}
+
+	resp := s.chain[s.next]
+	s.next++
+	s.sent++
+	return resp, nil
+}
+
+func (s *mockDeliverStream) CloseSend() error {
+	return nil
+}
+
+func (s *mockDeliverStream) Context() context.Context {
+	return s.ctx
+}
+
+func eventEnvelope(t *testing.T, ccName, txID string) []byte {
+	events, err := proto.Marshal(&peer.ChaincodeEvent{ChaincodeId: ccName, TxId: txID, EventName: `event`})
+	require.NoError(t, err)
+	action, err := proto.Marshal(&peer.ChaincodeAction{Events: events})
+	require.NoError(t, err)
+	respPayload, err := proto.Marshal(&peer.ProposalResponsePayload{Extension: action})
+	require.NoError(t, err)
+	actionPayload, err := proto.Marshal(&peer.ChaincodeActionPayload{
+		Action: &peer.ChaincodeEndorsedAction{ProposalResponsePayload: respPayload}})
+	require.NoError(t, err)
+	tx, err := proto.Marshal(&peer.Transaction{Actions: []*peer.TransactionAction{{Payload: actionPayload}}})
+	require.NoError(t, err)
+
+	payload, err := proto.Marshal(&common.Payload{
+		Header: protoutil.MakePayloadHeader(
+			protoutil.MakeChannelHeader(common.HeaderType_ENDORSER_TRANSACTION, 0, `channel`, 0), &common.SignatureHeader{}),
+		Data: tx,
+	})
+	require.NoError(t, err)
+	envelope, err := proto.Marshal(&common.Envelope{Payload: payload})
+	require.NoError(t, err)
+	return envelope
+}
+
+// newChain returns blocks with two transactions, events of `other` and `cc` chaincodes
+func newChain(t *testing.T, size int) []*common.Block {
+	chain := make([]*common.Block, size)
+	for i := range chain {
+		chain[i] = protoutil.NewBlock(uint64(i), nil)
+		chain[i].Data.Data = [][]byte{
+			eventEnvelope(t, `other`, fmt.Sprintf("tx-%d-0", i)),
+			eventEnvelope(t, `cc`, fmt.Sprintf("tx-%d-1", i)),
+		}
+		chain[i].Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = []byte{0, 0}
+	}
+	return chain
+}
+
+// recordingStore records every saved checkpoint
+type recordingStore struct {
+	api.CheckpointStore
+	mx    sync.Mutex
+	saved []api.EventCheckpoint
+}
+
+func (r *recordingStore) Save(key string, checkpoint api.EventCheckpoint) error {
+	r.mx.Lock()
+	r.saved = append(r.saved, checkpoint)
+	r.mx.Unlock()
+	return r.CheckpointStore.Save(key, checkpoint)
+}
+
+func (r *recordingStore) savedCheckpoints() []api.EventCheckpoint {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	return append([]api.EventCheckpoint(nil), r.saved...)
+}
+
+func readEvents(t *testing.T, sub api.EventCCSubscription, count int) []string {
+	var txIDs []string
+	timeout := time.After(5 * time.Second)
+	for count < 0 || len(txIDs) < count {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return txIDs
+			}
+			txIDs = append(txIDs, event.TxId)
+		case <-timeout:
+			t.Fatal(`events are not received`)
+		}
+	}
+	return txIDs
+}
+
+func signingIdentity(t *testing.T) msp.SigningIdentity {
+	cs, err := crypto.GetSuite(ecdsa.Module, ecdsa.DefaultOpts)
+	require.NoError(t, err)
+	id, err := identity.NewMSPIdentityFromPath(`org1msp`, `../../client/chaincode/testdata/msp`)
+	require.NoError(t, err)
+	return id.GetSigningIdentity(cs)
+}
+
+func TestDeliver_SubscribeCCCheckpointed(t *testing.T) {
+	signer := signingIdentity(t)
+
+	chain := newChain(t, 5)
+	store := checkpoint.NewMemoryStore()
+	opts := []api.CheckpointOpt{
+		api.WithCheckpointSeek(api.SeekRange(0, 4)),
+		api.WithCheckpointRetry(time.Millisecond, 3),
+	}
+
+	// first stream fails after two blocks, subscription is closed after event of third block
+	cli := deliver.New(&mockDeliver{chain: chain, failAfter: []int{2}}, signer)
+	sub, err := cli.SubscribeCCCheckpointed(context.Background(), `channel`, `cc`, store, opts...)
+	require.NoError(t, err)
+	require.Equal(t, []string{`tx-0-1`, `tx-1-1`, `tx-2-1`}, readEvents(t, sub, 3))
+	require.NoError(t, sub.Close())
+
+	saved, err := store.Load(`channel/cc`)
+	require.NoError(t, err)
+	require.Equal(t, &api.EventCheckpoint{BlockNumber: 2, TxIndex: 1}, saved)
+
+	// new subscription resumes after last delivered event
+	sub, err = cli.SubscribeCCCheckpointed(context.Background(), `channel`, `cc`, store, opts...)
+	require.NoError(t, err)
+	require.Equal(t, []string{`tx-3-1`, `tx-4-1`}, readEvents(t, sub, -1))
+	require.NoError(t, <-sub.Errors())
+
+	saved, err = store.Load(`channel/cc`)
+	require.NoError(t, err)
+	require.Equal(t, &api.EventCheckpoint{BlockNumber: 4, TxIndex: 1}, saved)
+}
+
+func TestDeliver_SubscribeCCCheckpointed_Errors(t *testing.T) {
+	signer := signingIdentity(t)
+	opts := []api.CheckpointOpt{
+		api.WithCheckpointSeek(api.SeekRange(0, 4)),
+		api.WithCheckpointRetry(time.Millisecond, 3),
+	}
+
+	t.Run(`block gap`, func(t *testing.T) {
+		chain := newChain(t, 5)
+		// block 2 is missing in chain
+		chain[2].Header.Number = 3
+
+		mock := &mockDeliver{chain: chain}
+		sub, err := deliver.New(mock, signer).
+			SubscribeCCCheckpointed(context.Background(), `channel`, `cc`, checkpoint.NewMemoryStore(), opts...)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{`tx-0-1`, `tx-1-1`}, readEvents(t, sub, -1))
+		require.Equal(t, api.ErrBlockGap{Expected: 2, Received: 3}, <-sub.Errors())
+		require.Equal(t, 1, mock.streams)
+	})
+
+	t.Run(`forbidden status is not retried`, func(t *testing.T) {
+		mock := &mockDeliver{chain: newChain(t, 5), failAfter: []int{1}, failStatus: []common.Status{common.Status_FORBIDDEN}}
+		sub, err := deliver.New(mock, signer).
+			SubscribeCCCheckpointed(context.Background(), `channel`, `cc`, checkpoint.NewMemoryStore(), opts...)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{`tx-0-1`}, readEvents(t, sub, -1))
+		require.Equal(t, api.ErrUnexpectedDeliverStatus{Status: common.Status_FORBIDDEN}, <-sub.Errors())
+		require.Equal(t, 1, mock.streams)
+	})
+
+	t.Run(`service unavailable status is retried`, func(t *testing.T) {
+		mock := &mockDeliver{chain: newChain(t, 5), failAfter: []int{1}, failStatus: []common.Status{common.Status_SERVICE_UNAVAILABLE}}
+		sub, err := deliver.New(mock, signer).
+			SubscribeCCCheckpointed(context.Background(), `channel`, `cc`, checkpoint.NewMemoryStore(), opts...)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{`tx-0-1`, `tx-1-1`, `tx-2-1`, `tx-3-1`, `tx-4-1`}, readEvents(t, sub, -1))
+		require.NoError(t, <-sub.Errors())
+		require.Equal(t, 2, mock.streams)
+	})
+}
+
+func TestDeliver_SubscribeCCCheckpointed_SaveInterval(t *testing.T) {
+	signer := signingIdentity(t)
+
+	// only block 0 contains event of chaincode
+	chain := newChain(t, 7)
+	for _, block := range chain[1:] {
+		block.Data.Data = block.Data.Data[:1]
+		block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = []byte{0}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		interval int
+		expSaved []api.EventCheckpoint
+	}{
+		{
+			name:     `saved every two blocks`,
+			interval: 2,
+			expSaved: []api.EventCheckpoint{{BlockNumber: 0, TxIndex: 1}, {BlockNumber: 2}, {BlockNumber: 4}, {BlockNumber: 6}},
+		},
+		{
+			name:     `saved with events and on stop`,
+			expSaved: []api.EventCheckpoint{{BlockNumber: 0, TxIndex: 1}, {BlockNumber: 6}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &recordingStore{CheckpointStore: checkpoint.NewMemoryStore()}
+			sub, err := deliver.New(&mockDeliver{chain: chain}, signer).SubscribeCCCheckpointed(
+				context.Background(), `channel`, `cc`, store,
+				api.WithCheckpointSeek(api.SeekRange(0, 6)), api.WithCheckpointSaveInterval(tc.interval))
+			require.NoError(t, err)
+
+			require.Equal(t, []string{`tx-0-1`}, readEvents(t, sub, -1))
+			require.NoError(t, <-sub.Errors())
+			require.Equal(t, tc.expSaved, store.savedCheckpoints())
+		})
+	}
+}
+
+func TestDeliver_SubscribeCCCheckpointed_Ack(t *testing.T) {
+	signer := signingIdentity(t)
+	chain := newChain(t, 5)
+	store := &recordingStore{CheckpointStore: checkpoint.NewMemoryStore()}
+	opts := []api.CheckpointOpt{
+		api.WithCheckpointSeek(api.SeekRange(0, 4)),
+		api.WithCheckpointAck(),
+	}
+	cli := deliver.New(&mockDeliver{chain: chain}, signer)
+
+	sub, err := cli.SubscribeCCCheckpointed(context.Background(), `channel`, `cc`, store, opts...)
+	require.NoError(t, err)
+
+	event := <-sub.Events()
+	require.Equal(t, `tx-0-1`, event.TxId)
+	// position is not saved until event is acknowledged
+	require.Empty(t, store.savedCheckpoints())
+	require.Error(t, sub.Ack(&peer.ChaincodeEvent{TxId: `tx-1-1`}))
+	require.NoError(t, sub.Ack(event))
+	require.Equal(t, []api.EventCheckpoint{{BlockNumber: 0, TxIndex: 1}}, store.savedCheckpoints())
+
+	// event of block 1 is read, but subscription is closed before ack
+	event = <-sub.Events()
+	require.Equal(t, `tx-1-1`, event.TxId)
+	require.NoError(t, sub.Close())
+	require.Equal(t, deliver.ErrCheckpointSubscriptionClosed, sub.Ack(event))
+
+	// not acknowledged event is delivered again
+	sub, err = cli.SubscribeCCCheckpointed(context.Background(), `channel`, `cc`, store, opts...)
+	require.NoError(t, err)
+	defer sub.Close()
+	event = <-sub.Events()
+	require.Equal(t, `tx-1-1`, event.TxId)
+	require.NoError(t, sub.Ack(event))
+
+	saved, err := store.Load(`channel/cc`)
+	require.NoError(t, err)
+	require.Equal(t, &api.EventCheckpoint{BlockNumber: 1, TxIndex: 1}, saved)
+}
+
+func TestDeliver_SubscribeCCCheckpointed_AckDisabled(t *testing.T) {
+	sub, err := deliver.New(&mockDeliver{chain: newChain(t, 1)}, signingIdentity(t)).SubscribeCCCheckpointed(
+		context.Background(), `channel`, `cc`, checkpoint.NewMemoryStore(), api.WithCheckpointSeek(api.SeekRange(0, 0)))
+	require.NoError(t, err)
+	defer sub.Close()
+
+	event := <-sub.Events()
+	require.Equal(t, deliver.ErrCheckpointAckDisabled, sub.Ack(event))
+}
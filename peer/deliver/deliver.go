@@ -11,6 +11,8 @@ import (
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 
 	"github.com/bogatyr285/hlf-sdk-go/api"
 	"github.com/bogatyr285/hlf-sdk-go/peer/deliver/subs"
@@ -149,7 +151,79 @@ func (d *deliverImpl) SubscribeBlock(ctx context.Context, channelName string, se
 	return blocker.Serve(sub, sub.readyForHandling), nil
 }
 
+func (d *deliverImpl) SubscribeFilteredBlock(ctx context.Context, channelName string, seekOpt ...api.EventCCSeekOption) (api.FilteredBlockSubscription, error) {
+	blocker := subs.NewFilteredBlockSubscription()
+
+	sub, err := d.handleFilteredSubscription(ctx, channelName, blocker.Handler, seekOpt...)
+	if err != nil {
+		return nil, err
+	}
+
+	return blocker.Serve(sub, sub.readyForHandling), nil
+}
+
+func (d *deliverImpl) SubscribeFilteredCC(ctx context.Context, channelName string, ccName string, seekOpt ...api.EventCCSeekOption) (api.EventCCSubscription, error) {
+	events := subs.NewEventSubscription(ccName, ``)
+
+	sub, err := d.handleFilteredSubscription(ctx, channelName, events.FilteredHandler, seekOpt...)
+	if err != nil {
+		return nil, err
+	}
+
+	return events.Serve(sub, sub.readyForHandling), nil
+}
+
+func (d *deliverImpl) SubscribeFilteredTx(ctx context.Context, channelName string, txId api.ChaincodeTx, seekOpt ...api.EventCCSeekOption) (api.TxSubscription, error) {
+	txSub := subs.NewTxSubscription(txId)
+
+	sub, err := d.handleFilteredSubscription(ctx, channelName, txSub.FilteredHandler, seekOpt...)
+	if err != nil {
+		return nil, err
+	}
+
+	return txSub.Serve(sub, sub.readyForHandling), nil
+}
+
+func (d *deliverImpl) SubscribeBlockWithPrivateData(ctx context.Context, channelName string, seekOpt ...api.EventCCSeekOption) (api.BlockWithPrivateDataSubscription, error) {
+	blocker := subs.NewBlockWithPrivateDataSubscription()
+
+	sub, err := d.subscribe(ctx, channelName, func(ctx context.Context) (deliverStream, error) {
+		return d.cli.DeliverWithPrivateData(ctx)
+	}, handlers{blockWithPrivateDataHandler: blocker.Handler}, seekOpt...)
+	if err != nil {
+		return nil, err
+	}
+
+	return blocker.Serve(sub, sub.readyForHandling), nil
+}
+
+// deliverStream is common part of Deliver, DeliverFiltered and DeliverWithPrivateData streams
+type deliverStream interface {
+	Send(*common.Envelope) error
+	Recv() (*peer.DeliverResponse, error)
+	grpc.ClientStream
+}
+
+// handlers process responses of deliver stream, only handler matching stream type is set
+type handlers struct {
+	blockHandler                subs.BlockHandler
+	filteredBlockHandler        subs.FilteredBlockHandler
+	blockWithPrivateDataHandler subs.BlockWithPrivateDataHandler
+}
+
 func (d *deliverImpl) handleSubscription(ctx context.Context, channel string, blockHandler subs.BlockHandler, seekOpt ...api.EventCCSeekOption) (*subscriptionImpl, error) {
+	return d.subscribe(ctx, channel, func(ctx context.Context) (deliverStream, error) {
+		return d.cli.Deliver(ctx)
+	}, handlers{blockHandler: blockHandler}, seekOpt...)
+}
+
+func (d *deliverImpl) handleFilteredSubscription(ctx context.Context, channel string, blockHandler subs.FilteredBlockHandler, seekOpt ...api.EventCCSeekOption) (*subscriptionImpl, error) {
+	return d.subscribe(ctx, channel, func(ctx context.Context) (deliverStream, error) {
+		return d.cli.DeliverFiltered(ctx)
+	}, handlers{filteredBlockHandler: blockHandler}, seekOpt...)
+}
+
+func (d *deliverImpl) subscribe(ctx context.Context, channel string, open func(ctx context.Context) (deliverStream, error), h handlers, seekOpt ...api.EventCCSeekOption) (*subscriptionImpl, error) {
 	var startPos, stopPos *orderer.SeekPosition
 
 	if len(seekOpt) > 0 {
@@ -165,30 +239,32 @@ func (d *deliverImpl) handleSubscription(ctx context.Context, channel string, bl
 
 	subCtx, stopSub := context.WithCancel(ctx)
 
-	stream, err := d.cli.Deliver(subCtx)
+	stream, err := open(subCtx)
 	if err != nil {
+		stopSub()
 		return nil, errors.Wrap(err, `failed to open deliver stream`)
 	}
 
 	err = stream.Send(seek)
 	if err != nil {
+		stopSub()
 		return nil, errors.Wrap(err, `failed to send seek envelope to stream`)
 	}
 
-	return makeSubscription(subCtx, stopSub, stream, blockHandler), nil
+	return makeSubscription(subCtx, stopSub, stream, h), nil
 }
 
-func makeSubscription(ctx context.Context, stop context.CancelFunc, stream peer.Deliver_DeliverClient, blockHandler subs.BlockHandler) *subscriptionImpl {
+func makeSubscription(ctx context.Context, stop context.CancelFunc, stream deliverStream, h handlers) *subscriptionImpl {
 	s := &subscriptionImpl{
-		ctx:          ctx,
-		stop:         stop,
-		stream:       stream,
-		blockHandler: blockHandler,
-		once:         new(sync.Once),
-		err:          make(chan error, 1),  // only one error
-		done:         make(chan *struct{}), // done will be closed after finished sub.handle
-		up:           make(chan *struct{}),
-		run:          make(chan *struct{}),
+		ctx:      ctx,
+		stop:     stop,
+		stream:   stream,
+		handlers: h,
+		once:     new(sync.Once),
+		err:      make(chan error, 1),  // only one error
+		done:     make(chan *struct{}), // done will be closed after finished sub.handle
+		up:       make(chan *struct{}),
+		run:      make(chan *struct{}),
 	}
 
 	go s.handle()
@@ -198,15 +274,15 @@ func makeSubscription(ctx context.Context, stop context.CancelFunc, stream peer.
 }
 
 type subscriptionImpl struct {
-	ctx          context.Context
-	stop         context.CancelFunc
-	blockHandler subs.BlockHandler
-	stream       peer.Deliver_DeliverClient
-	err          chan error
-	once         *sync.Once
-	done         chan *struct{}
-	up           chan *struct{}
-	run          chan *struct{}
+	ctx    context.Context
+	stop   context.CancelFunc
+	stream deliverStream
+	handlers
+	err  chan error
+	once *sync.Once
+	done chan *struct{}
+	up   chan *struct{}
+	run  chan *struct{}
 }
 
 func (s *subscriptionImpl) handle() {
@@ -220,29 +296,62 @@ func (s *subscriptionImpl) handle() {
 	for {
 		ev, err := s.stream.Recv()
 		if err == io.EOF {
-			s.blockHandler(nil)
+			s.eof()
 			return
 		}
 
 		if err != nil {
-			s.err <- err
+			s.err <- api.GRPCStreamError{Code: status.Code(err), Err: err}
 			return
 		}
 
+		select {
+		case <-ctx.Done():
+			s.err <- ctx.Err()
+			return
+		default:
+		}
+
+		var skip bool
 		switch event := ev.Type.(type) {
 		case *peer.DeliverResponse_Block:
-			select {
-			case <-ctx.Done():
-				s.err <- ctx.Err()
+			if s.blockHandler != nil {
+				skip = s.blockHandler(event.Block)
+			}
+		case *peer.DeliverResponse_FilteredBlock:
+			if s.filteredBlockHandler != nil {
+				skip = s.filteredBlockHandler(event.FilteredBlock)
+			}
+		case *peer.DeliverResponse_BlockAndPrivateData:
+			if s.blockWithPrivateDataHandler != nil {
+				skip = s.blockWithPrivateDataHandler(event.BlockAndPrivateData)
+			}
+		case *peer.DeliverResponse_Status:
+			// SUCCESS is sent after stop position is reached, stream is closed by peer after it
+			if event.Status != common.Status_SUCCESS {
+				s.err <- api.ErrUnexpectedDeliverStatus{Status: event.Status}
 				return
-			default:
-				if skip := s.blockHandler(event.Block); skip {
-					return
-				}
 			}
+			continue
 		default:
 			continue
 		}
+
+		if skip {
+			return
+		}
+	}
+}
+
+// eof signals handler about end of stream
+func (s *subscriptionImpl) eof() {
+	switch {
+	case s.blockHandler != nil:
+		s.blockHandler(nil)
+	case s.filteredBlockHandler != nil:
+		s.filteredBlockHandler(nil)
+	case s.blockWithPrivateDataHandler != nil:
+		s.blockWithPrivateDataHandler(nil)
 	}
 }
 
@@ -0,0 +1,68 @@
+package deliver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/peer/deliver"
+)
+
+func filteredTx(txID string, code peer.TxValidationCode, ccName string) *peer.FilteredTransaction {
+	return &peer.FilteredTransaction{
+		Txid:             txID,
+		TxValidationCode: code,
+		Data: &peer.FilteredTransaction_TransactionActions{TransactionActions: &peer.FilteredTransactionActions{
+			ChaincodeActions: []*peer.FilteredChaincodeAction{{
+				ChaincodeEvent: &peer.ChaincodeEvent{ChaincodeId: ccName, TxId: txID, EventName: `event`},
+			}},
+		}},
+	}
+}
+
+func TestDeliver_SubscribeFiltered(t *testing.T) {
+	filtered := []*peer.FilteredBlock{
+		{Number: 0, FilteredTransactions: []*peer.FilteredTransaction{
+			filteredTx(`tx-0-0`, peer.TxValidationCode_VALID, `cc`),
+			filteredTx(`tx-0-1`, peer.TxValidationCode_VALID, `other`),
+		}},
+		{Number: 1, FilteredTransactions: []*peer.FilteredTransaction{
+			filteredTx(`tx-1-0`, peer.TxValidationCode_MVCC_READ_CONFLICT, `cc`),
+			filteredTx(`tx-1-1`, peer.TxValidationCode_VALID, `cc`),
+		}},
+	}
+	cli := deliver.New(&mockDeliver{filtered: filtered}, signingIdentity(t))
+
+	t.Run(`blocks`, func(t *testing.T) {
+		sub, err := cli.SubscribeFilteredBlock(context.Background(), `channel`, api.SeekRange(0, 1))
+		require.NoError(t, err)
+		defer sub.Close()
+
+		var numbers []uint64
+		for block := range sub.Blocks() {
+			numbers = append(numbers, block.Number)
+		}
+		require.Equal(t, []uint64{0, 1}, numbers)
+	})
+
+	t.Run(`chaincode events`, func(t *testing.T) {
+		sub, err := cli.SubscribeFilteredCC(context.Background(), `channel`, `cc`, api.SeekRange(0, 1))
+		require.NoError(t, err)
+		defer sub.Close()
+
+		require.Equal(t, []string{`tx-0-0`, `tx-1-1`}, readEvents(t, sub, -1))
+	})
+
+	t.Run(`tx`, func(t *testing.T) {
+		sub, err := cli.SubscribeFilteredTx(context.Background(), `channel`, `tx-1-0`, api.SeekRange(0, 1))
+		require.NoError(t, err)
+		defer sub.Close()
+
+		code, err := sub.Result()
+		require.Error(t, err)
+		require.Equal(t, peer.TxValidationCode_MVCC_READ_CONFLICT, code)
+	})
+}
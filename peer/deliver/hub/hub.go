@@ -0,0 +1,354 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/orderer"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/peer/deliver/subs"
+)
+
+const (
+	DefaultRetryDelay = time.Second
+	// DefaultBuffer is count of blocks queued for every subscriber
+	DefaultBuffer = 16
+)
+
+var (
+	ErrHubClosed = errors.New(`hub is closed`)
+	// ErrSubscriberOverflow is sent to subscriber which is disconnected because its queue is full
+	ErrSubscriberOverflow = errors.New(`subscriber queue overflow`)
+)
+
+// OverflowPolicy defines what hub does with subscriber whose queue is full
+type OverflowPolicy int
+
+const (
+	// OverflowDisconnect disconnects subscriber with ErrSubscriberOverflow, other subscribers are not delayed
+	OverflowDisconnect OverflowPolicy = iota
+	// OverflowBlock makes hub wait until subscriber reads queued block, so slow subscriber delays reading
+	// of stream and all other subscribers. Use it when stream is replayed from old blocks, which peer sends
+	// faster than subscribers handle them
+	OverflowBlock
+)
+
+// BlockFilter returns true if block must be sent to subscriber
+type BlockFilter func(block *common.Block) bool
+
+// Hub reads blocks of channel from one deliver stream and fans them out to any number of subscribers.
+// Subscribers receive blocks received after subscription. By default hub doesn't wait for slow subscribers:
+// subscriber whose queue is full is disconnected with ErrSubscriberOverflow, so others are not delayed.
+// This suits SeekNewest streams, replayed streams need OverflowBlock policy, see WithOverflow
+type Hub struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	deliver api.DeliverClient
+	channel string
+
+	seek       api.EventCCSeekOption
+	retryDelay time.Duration
+	maxRetries int
+	buffer     int
+	overflow   OverflowPolicy
+
+	// last is number of last received block, valid when received is true
+	last     uint64
+	received bool
+
+	mx          sync.Mutex
+	subscribers map[*subscriber]struct{}
+	closed      bool
+
+	done chan struct{}
+}
+
+type Opt func(h *Hub)
+
+// WithSeek sets seek option of hub stream, SeekNewest by default
+func WithSeek(seek api.EventCCSeekOption) Opt {
+	return func(h *Hub) {
+		h.seek = seek
+	}
+}
+
+// WithRetry sets delay before reconnect and max count of consecutive reconnects without received blocks,
+// 0 means unlimited
+func WithRetry(delay time.Duration, maxRetries int) Opt {
+	return func(h *Hub) {
+		h.retryDelay = delay
+		h.maxRetries = maxRetries
+	}
+}
+
+// WithBuffer sets count of blocks queued for every subscriber, queue overflow is handled by policy set by WithOverflow
+func WithBuffer(buffer int) Opt {
+	return func(h *Hub) {
+		h.buffer = buffer
+	}
+}
+
+// WithOverflow sets policy for subscribers whose queue is full, OverflowDisconnect by default
+func WithOverflow(policy OverflowPolicy) Opt {
+	return func(h *Hub) {
+		h.overflow = policy
+	}
+}
+
+// New opens block stream of channel and returns hub.
+// Stream is reopened from next block on GRPCStreamError or SERVICE_UNAVAILABLE status,
+// other errors, e.g. FORBIDDEN status, are sent to all subscribers and close hub
+func New(ctx context.Context, deliver api.DeliverClient, channel string, opts ...Opt) (*Hub, error) {
+	hubCtx, cancel := context.WithCancel(ctx)
+	h := &Hub{
+		ctx:         hubCtx,
+		cancel:      cancel,
+		deliver:     deliver,
+		channel:     channel,
+		seek:        api.SeekNewest(),
+		retryDelay:  DefaultRetryDelay,
+		buffer:      DefaultBuffer,
+		subscribers: make(map[*subscriber]struct{}),
+		done:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	sub, err := h.deliver.SubscribeBlock(h.ctx, h.channel, h.seek)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	go h.run(sub)
+
+	return h, nil
+}
+
+// SubscribeBlock adds subscriber on blocks passing all filters
+func (h *Hub) SubscribeBlock(filters ...BlockFilter) (api.BlockSubscription, error) {
+	blocker := subs.NewBlockSubscription()
+	handler := blocker.Handler
+	if len(filters) > 0 {
+		handler = func(block *common.Block) bool {
+			if block != nil {
+				for _, filter := range filters {
+					if !filter(block) {
+						return false
+					}
+				}
+			}
+			return blocker.Handler(block)
+		}
+	}
+
+	s, err := h.subscribe(handler)
+	if err != nil {
+		return nil, err
+	}
+	return blocker.Serve(s, s.readyForHandling), nil
+}
+
+// SubscribeCC adds subscriber on chaincode events
+func (h *Hub) SubscribeCC(ccName string) (api.EventCCSubscription, error) {
+	events := subs.NewEventSubscription(ccName, ``)
+
+	s, err := h.subscribe(events.Handler)
+	if err != nil {
+		return nil, err
+	}
+	return events.Serve(s, s.readyForHandling), nil
+}
+
+// SubscribeTx adds subscriber on transaction result, subscriber is removed when result is received
+func (h *Hub) SubscribeTx(txID api.ChaincodeTx) (api.TxSubscription, error) {
+	txSub := subs.NewTxSubscription(txID)
+
+	s, err := h.subscribe(txSub.Handler)
+	if err != nil {
+		return nil, err
+	}
+	return txSub.Serve(s, s.readyForHandling), nil
+}
+
+// Close closes stream and all subscribers
+func (h *Hub) Close() error {
+	h.cancel()
+	<-h.done
+	return nil
+}
+
+func (h *Hub) subscribe(handler subs.BlockHandler) (*subscriber, error) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	if h.closed {
+		return nil, ErrHubClosed
+	}
+
+	s := newSubscriber(h, handler)
+	h.subscribers[s] = struct{}{}
+	return s, nil
+}
+
+func (h *Hub) remove(s *subscriber) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+	delete(h.subscribers, s)
+}
+
+func (h *Hub) run(sub api.BlockSubscription) {
+	defer close(h.done)
+
+	var err error
+	retries := 0
+	for {
+		receivedBefore, lastBefore := h.received, h.last
+		if sub == nil {
+			if sub, err = h.deliver.SubscribeBlock(h.ctx, h.channel, h.resumeSeek()); err != nil {
+				err = api.GRPCStreamError{Err: err}
+			}
+		}
+		if sub != nil {
+			err = h.read(sub)
+			_ = sub.Close()
+			sub = nil
+		}
+
+		if h.ctx.Err() != nil {
+			err = nil
+			break
+		}
+		if err == nil && h.stopReached() {
+			break
+		}
+		if err == nil {
+			err = api.GRPCStreamError{Err: errors.New(`deliver stream closed`)}
+		}
+		if !reconnectable(err) {
+			break
+		}
+
+		if h.received != receivedBefore || h.last != lastBefore {
+			retries = 0
+		}
+		retries++
+		if h.maxRetries > 0 && retries > h.maxRetries {
+			break
+		}
+
+		select {
+		case <-time.After(h.retryDelay):
+		case <-h.ctx.Done():
+		}
+		if h.ctx.Err() != nil {
+			err = nil
+			break
+		}
+	}
+
+	h.shutdown(err)
+}
+
+// read dispatches blocks of stream until it ends, returns nil on end of stream
+func (h *Hub) read(sub api.BlockSubscription) error {
+	for {
+		select {
+		case block, ok := <-sub.Blocks():
+			if !ok {
+				return nil
+			}
+			h.last, h.received = block.GetHeader().GetNumber(), true
+			h.dispatch(block)
+
+		case err, ok := <-sub.Errors():
+			if !ok {
+				return nil
+			}
+			return err
+
+		case <-h.ctx.Done():
+			return h.ctx.Err()
+		}
+	}
+}
+
+func (h *Hub) dispatch(block *common.Block) {
+	h.mx.Lock()
+	subscribers := make([]*subscriber, 0, len(h.subscribers))
+	for s := range h.subscribers {
+		subscribers = append(subscribers, s)
+	}
+	h.mx.Unlock()
+
+	for _, s := range subscribers {
+		if h.overflow == OverflowBlock {
+			select {
+			case s.blocks <- block:
+			case <-s.done:
+			case <-h.ctx.Done():
+			}
+			continue
+		}
+
+		select {
+		case s.blocks <- block:
+		case <-s.done:
+		default:
+			h.remove(s)
+			s.finish(ErrSubscriberOverflow)
+		}
+	}
+}
+
+// shutdown sends err to subscribers and closes them
+func (h *Hub) shutdown(err error) {
+	h.mx.Lock()
+	h.closed = true
+	subscribers := h.subscribers
+	h.subscribers = make(map[*subscriber]struct{})
+	h.mx.Unlock()
+
+	for s := range subscribers {
+		s.finish(err)
+	}
+	h.cancel()
+}
+
+// reconnectable returns true if stream failed because of transport or peer temporary unavailability
+func reconnectable(err error) bool {
+	switch e := err.(type) {
+	case api.GRPCStreamError:
+		return true
+	case api.ErrUnexpectedDeliverStatus:
+		return e.Status == common.Status_SERVICE_UNAVAILABLE
+	default:
+		return false
+	}
+}
+
+func (h *Hub) resumeSeek() api.EventCCSeekOption {
+	if !h.received {
+		return h.seek
+	}
+	_, stop := h.seek()
+	return func() (*orderer.SeekPosition, *orderer.SeekPosition) {
+		return &orderer.SeekPosition{Type: &orderer.SeekPosition_Specified{
+			Specified: &orderer.SeekSpecified{Number: h.last + 1}}}, stop
+	}
+}
+
+func (h *Hub) stopReached() bool {
+	_, stop := h.seek()
+	number := stop.GetSpecified()
+	if number == nil || number.Number == math.MaxUint64 {
+		return false
+	}
+	return h.received && h.last >= number.Number
+}
@@ -0,0 +1,284 @@
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/peer/deliver/hub"
+)
+
+// mockDeliver returns block subscriptions controlled by test
+type mockDeliver struct {
+	api.DeliverClient
+
+	mx     sync.Mutex
+	starts []uint64
+	subs   chan *mockBlockSubscription
+}
+
+func (m *mockDeliver) SubscribeBlock(_ context.Context, _ string, seekOpt ...api.EventCCSeekOption) (api.BlockSubscription, error) {
+	start, _ := seekOpt[0]()
+	m.mx.Lock()
+	m.starts = append(m.starts, start.GetSpecified().GetNumber())
+	m.mx.Unlock()
+
+	sub := &mockBlockSubscription{blocks: make(chan *common.Block), errors: make(chan error, 1)}
+	m.subs <- sub
+	return sub, nil
+}
+
+type mockBlockSubscription struct {
+	blocks chan *common.Block
+	errors chan error
+}
+
+func (m *mockBlockSubscription) Blocks() <-chan *common.Block {
+	return m.blocks
+}
+
+func (m *mockBlockSubscription) Errors() chan error {
+	return m.errors
+}
+
+func (m *mockBlockSubscription) Close() error {
+	return nil
+}
+
+func newBlock(t *testing.T, number uint64, txIDs ...string) *common.Block {
+	block := protoutil.NewBlock(number, nil)
+	for _, txID := range txIDs {
+		chHeader := &common.ChannelHeader{Type: int32(common.HeaderType_ENDORSER_TRANSACTION), ChannelId: `channel`, TxId: txID}
+		payload := &common.Payload{Header: protoutil.MakePayloadHeader(chHeader, &common.SignatureHeader{})}
+		envelope, err := proto.Marshal(&common.Envelope{Payload: protoutil.MarshalOrPanic(payload)})
+		require.NoError(t, err)
+		block.Data.Data = append(block.Data.Data, envelope)
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = make([]byte, len(txIDs))
+	return block
+}
+
+func receive(t *testing.T, sub api.BlockSubscription) uint64 {
+	select {
+	case block := <-sub.Blocks():
+		return block.Header.Number
+	case <-time.After(5 * time.Second):
+		t.Fatal(`block is not received`)
+	}
+	return 0
+}
+
+func TestHub(t *testing.T) {
+	deliver := &mockDeliver{subs: make(chan *mockBlockSubscription, 2)}
+	h, err := hub.New(context.Background(), deliver, `channel`,
+		hub.WithSeek(api.SeekRange(0, 100)), hub.WithRetry(time.Millisecond, 0))
+	require.NoError(t, err)
+	defer h.Close()
+	stream := <-deliver.subs
+
+	all, err := h.SubscribeBlock()
+	require.NoError(t, err)
+	even, err := h.SubscribeBlock(func(block *common.Block) bool { return block.Header.Number%2 == 0 })
+	require.NoError(t, err)
+	tx, err := h.SubscribeTx(`tx-1`)
+	require.NoError(t, err)
+
+	stream.blocks <- newBlock(t, 0, `tx-0`)
+	stream.blocks <- newBlock(t, 1, `tx-1`)
+	require.Equal(t, uint64(0), receive(t, all))
+	require.Equal(t, uint64(1), receive(t, all))
+	require.Equal(t, uint64(0), receive(t, even))
+
+	code, err := tx.Result()
+	require.NoError(t, err)
+	require.Equal(t, peer.TxValidationCode_VALID, code)
+	require.NoError(t, tx.Close())
+
+	// removed subscriber doesn't block others
+	require.NoError(t, all.Close())
+
+	// stream is reopened from next block after failure
+	stream.errors <- api.GRPCStreamError{Err: errors.New(`peer is unavailable`)}
+	stream = <-deliver.subs
+	stream.blocks <- newBlock(t, 2)
+	require.Equal(t, uint64(2), receive(t, even))
+	require.Equal(t, []uint64{0, 2}, deliver.starts)
+
+	// added subscriber receives new blocks
+	late, err := h.SubscribeBlock()
+	require.NoError(t, err)
+	stream.blocks <- newBlock(t, 3)
+	require.Equal(t, uint64(3), receive(t, late))
+
+	// hub close finishes subscribers
+	require.NoError(t, h.Close())
+	_, ok := <-late.Blocks()
+	require.False(t, ok)
+}
+
+func TestHub_SlowSubscriber(t *testing.T) {
+	deliver := &mockDeliver{subs: make(chan *mockBlockSubscription, 1)}
+	h, err := hub.New(context.Background(), deliver, `channel`, hub.WithSeek(api.SeekRange(0, 100)), hub.WithBuffer(1))
+	require.NoError(t, err)
+	defer h.Close()
+	stream := <-deliver.subs
+
+	fast, err := h.SubscribeBlock()
+	require.NoError(t, err)
+	// slow subscriber doesn't read blocks
+	slow, err := h.SubscribeBlock()
+	require.NoError(t, err)
+
+	for i := uint64(0); i < 4; i++ {
+		stream.blocks <- newBlock(t, i)
+		require.Equal(t, i, receive(t, fast))
+	}
+
+	// slow subscriber receives queued blocks, then it is closed with overflow error
+	var received []uint64
+	for block := range slow.Blocks() {
+		received = append(received, block.Header.Number)
+	}
+	require.NotEmpty(t, received)
+	require.Equal(t, uint64(0), received[0])
+	require.True(t, len(received) < 4)
+	require.Equal(t, hub.ErrSubscriberOverflow, <-slow.Errors())
+	require.NoError(t, slow.Close())
+}
+
+func TestHub_OverflowBlock(t *testing.T) {
+	t.Run(`replay with slow subscriber`, func(t *testing.T) {
+		deliver := &mockDeliver{subs: make(chan *mockBlockSubscription, 1)}
+		h, err := hub.New(context.Background(), deliver, `channel`,
+			hub.WithSeek(api.SeekRange(0, 100)), hub.WithBuffer(1), hub.WithOverflow(hub.OverflowBlock))
+		require.NoError(t, err)
+		defer h.Close()
+		stream := <-deliver.subs
+
+		fast, err := h.SubscribeBlock()
+		require.NoError(t, err)
+		slow, err := h.SubscribeBlock()
+		require.NoError(t, err)
+
+		// peer replays old blocks as fast as hub reads them
+		const count = 10
+		go func() {
+			for i := uint64(0); i < count; i++ {
+				stream.blocks <- newBlock(t, i)
+			}
+		}()
+
+		fastDone := make(chan []uint64)
+		go func() {
+			var received []uint64
+			for i := 0; i < count; i++ {
+				received = append(received, receive(t, fast))
+			}
+			fastDone <- received
+		}()
+
+		var received []uint64
+		for i := 0; i < count; i++ {
+			time.Sleep(5 * time.Millisecond)
+			received = append(received, receive(t, slow))
+		}
+
+		expected := []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+		require.Equal(t, expected, received)
+		require.Equal(t, expected, <-fastDone)
+
+		select {
+		case err := <-slow.Errors():
+			t.Fatalf("unexpected subscriber error: %v", err)
+		default:
+		}
+	})
+
+	t.Run(`close unblocks hub`, func(t *testing.T) {
+		deliver := &mockDeliver{subs: make(chan *mockBlockSubscription, 1)}
+		h, err := hub.New(context.Background(), deliver, `channel`,
+			hub.WithSeek(api.SeekRange(0, 100)), hub.WithBuffer(1), hub.WithOverflow(hub.OverflowBlock))
+		require.NoError(t, err)
+		stream := <-deliver.subs
+
+		// subscriber doesn't read blocks
+		_, err = h.SubscribeBlock()
+		require.NoError(t, err)
+
+		go func() {
+			for i := uint64(0); i < 10; i++ {
+				select {
+				case stream.blocks <- newBlock(t, i):
+				case <-time.After(time.Second):
+					return
+				}
+			}
+		}()
+
+		time.Sleep(50 * time.Millisecond)
+		closed := make(chan struct{})
+		go func() {
+			_ = h.Close()
+			close(closed)
+		}()
+		select {
+		case <-closed:
+		case <-time.After(5 * time.Second):
+			t.Fatal(`hub is not closed`)
+		}
+	})
+}
+
+func TestHub_DeliverStatus(t *testing.T) {
+	t.Run(`service unavailable`, func(t *testing.T) {
+		deliver := &mockDeliver{subs: make(chan *mockBlockSubscription, 2)}
+		h, err := hub.New(context.Background(), deliver, `channel`,
+			hub.WithSeek(api.SeekRange(0, 100)), hub.WithRetry(time.Millisecond, 0))
+		require.NoError(t, err)
+		defer h.Close()
+		stream := <-deliver.subs
+
+		sub, err := h.SubscribeBlock()
+		require.NoError(t, err)
+		stream.blocks <- newBlock(t, 0)
+		require.Equal(t, uint64(0), receive(t, sub))
+
+		// stream is reopened from next block
+		stream.errors <- api.ErrUnexpectedDeliverStatus{Status: common.Status_SERVICE_UNAVAILABLE}
+		stream = <-deliver.subs
+		stream.blocks <- newBlock(t, 1)
+		require.Equal(t, uint64(1), receive(t, sub))
+	})
+
+	t.Run(`forbidden`, func(t *testing.T) {
+		deliver := &mockDeliver{subs: make(chan *mockBlockSubscription, 2)}
+		h, err := hub.New(context.Background(), deliver, `channel`,
+			hub.WithSeek(api.SeekRange(0, 100)), hub.WithRetry(time.Millisecond, 0))
+		require.NoError(t, err)
+		defer h.Close()
+		stream := <-deliver.subs
+
+		sub, err := h.SubscribeBlock()
+		require.NoError(t, err)
+
+		// stream is not reopened, subscribers receive error
+		stream.errors <- api.ErrUnexpectedDeliverStatus{Status: common.Status_FORBIDDEN}
+		_, ok := <-sub.Blocks()
+		require.False(t, ok)
+		require.Equal(t, api.ErrUnexpectedDeliverStatus{Status: common.Status_FORBIDDEN}, <-sub.Errors())
+		require.Empty(t, deliver.subs)
+		require.Len(t, deliver.starts, 1)
+
+		_, err = h.SubscribeBlock()
+		require.Equal(t, hub.ErrHubClosed, err)
+	})
+}
@@ -0,0 +1,114 @@
+package hub
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+
+	"github.com/bogatyr285/hlf-sdk-go/peer/deliver/subs"
+)
+
+// subscriber passes blocks queued by hub to block handler, implements subs.ErrorCloser
+type subscriber struct {
+	hub     *Hub
+	ctx     context.Context
+	cancel  context.CancelFunc
+	handler subs.BlockHandler
+	blocks  chan *common.Block
+
+	// mx guards err channel from concurrent finish by hub and Close
+	mx       sync.Mutex
+	err      chan error
+	closed   bool
+	finished bool
+	run      chan struct{}
+	done     chan struct{}
+}
+
+func newSubscriber(h *Hub, handler subs.BlockHandler) *subscriber {
+	// subscriber context is independent of hub, so subscriber handles queued blocks after hub is closed
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &subscriber{
+		hub:     h,
+		ctx:     ctx,
+		cancel:  cancel,
+		handler: handler,
+		blocks:  make(chan *common.Block, h.buffer),
+		err:     make(chan error, 1),
+		run:     make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	go s.handle()
+	return s
+}
+
+func (s *subscriber) handle() {
+	defer close(s.done)
+	<-s.run
+
+	for {
+		select {
+		case block, ok := <-s.blocks:
+			if !ok {
+				s.handler(nil)
+				return
+			}
+			if skip := s.handler(block); skip {
+				s.hub.remove(s)
+				return
+			}
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// finish is called by hub on shutdown or queue overflow, err is sent to subscriber and handler is signalled about end of stream
+func (s *subscriber) finish(err error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.closed || s.finished {
+		return
+	}
+	s.finished = true
+	if err != nil {
+		s.err <- err
+	}
+	close(s.blocks)
+}
+
+func (s *subscriber) readyForHandling() {
+	close(s.run)
+}
+
+func (s *subscriber) Done() <-chan struct{} {
+	return s.ctx.Done()
+}
+
+func (s *subscriber) Err() <-chan error {
+	return s.err
+}
+
+func (s *subscriber) Errors() chan error {
+	return s.err
+}
+
+// Close removes subscriber from hub
+func (s *subscriber) Close() error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	s.hub.remove(s)
+	s.cancel()
+	<-s.done
+	close(s.err)
+	return nil
+}
@@ -0,0 +1,51 @@
+package deliver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/peer/deliver"
+)
+
+func TestDeliver_SubscribeBlockWithPrivateData(t *testing.T) {
+	kvRWSet := &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: `key`, Value: []byte(`secret`)}}}
+	raw, err := proto.Marshal(kvRWSet)
+	require.NoError(t, err)
+
+	pvt := []*peer.BlockAndPrivateData{
+		{Block: protoutil.NewBlock(0, nil)},
+		{Block: protoutil.NewBlock(1, nil), PrivateDataMap: map[uint64]*rwset.TxPvtReadWriteSet{
+			2: {NsPvtRwset: []*rwset.NsPvtReadWriteSet{{
+				Namespace:          `cc`,
+				CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{{CollectionName: `collection`, Rwset: raw}},
+			}}},
+		}},
+	}
+	cli := deliver.New(&mockDeliver{pvt: pvt}, signingIdentity(t))
+
+	sub, err := cli.SubscribeBlockWithPrivateData(context.Background(), `channel`, api.SeekRange(0, 1))
+	require.NoError(t, err)
+	defer sub.Close()
+
+	var blocks []*api.BlockWithPrivateData
+	for block := range sub.Blocks() {
+		blocks = append(blocks, block)
+	}
+	require.Len(t, blocks, 2)
+	require.Empty(t, blocks[0].PvtDataCollections)
+
+	require.Equal(t, uint64(1), blocks[1].Block.Header.Number)
+	require.Len(t, blocks[1].PvtDataCollections[2], 1)
+	collection := blocks[1].PvtDataCollections[2][0]
+	require.Equal(t, `cc`, collection.Namespace)
+	require.Equal(t, `collection`, collection.Collection)
+	require.True(t, proto.Equal(kvRWSet, collection.RWSet))
+}
@@ -2,12 +2,17 @@ package subs
 
 import (
 	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
 )
 
 type (
 	// BlockHandler  when block == nil is eq EOF and signal for terminate all sub channels
-	BlockHandler     func(block *common.Block) bool
-	ReadyForHandling func()
+	BlockHandler func(block *common.Block) bool
+	// FilteredBlockHandler is BlockHandler for filtered blocks
+	FilteredBlockHandler func(block *peer.FilteredBlock) bool
+	// BlockWithPrivateDataHandler is BlockHandler for blocks with private data
+	BlockWithPrivateDataHandler func(block *peer.BlockAndPrivateData) bool
+	ReadyForHandling            func()
 
 	ErrorCloser interface {
 		Done() <-chan struct{}
@@ -51,3 +56,38 @@ func (b *BlockSubscription) Serve(base ErrorCloser, readyForHandling ReadyForHan
 	readyForHandling()
 	return b
 }
+
+func NewFilteredBlockSubscription() *FilteredBlockSubscription {
+	return &FilteredBlockSubscription{
+		blocks: make(chan *peer.FilteredBlock, 0),
+	}
+}
+
+type FilteredBlockSubscription struct {
+	blocks chan *peer.FilteredBlock
+	ErrorCloser
+}
+
+func (b *FilteredBlockSubscription) Blocks() <-chan *peer.FilteredBlock {
+	return b.blocks
+}
+
+func (b *FilteredBlockSubscription) Handler(block *peer.FilteredBlock) bool {
+	if block == nil {
+		close(b.blocks)
+	} else {
+		select {
+		case b.blocks <- block:
+		case <-b.ErrorCloser.Done():
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *FilteredBlockSubscription) Serve(base ErrorCloser, readyForHandling ReadyForHandling) *FilteredBlockSubscription {
+	b.ErrorCloser = base
+	readyForHandling()
+	return b
+}
@@ -74,6 +74,42 @@ func (e *EventSubscription) Handler(block *common.Block) bool {
 	return false
 }
 
+// FilteredHandler handles filtered block, chaincode events of filtered block have no payload
+func (e *EventSubscription) FilteredHandler(block *peer.FilteredBlock) bool {
+	if block == nil {
+		close(e.events)
+		return false
+	}
+
+	for _, tx := range block.GetFilteredTransactions() {
+		if tx.TxValidationCode != peer.TxValidationCode_VALID {
+			continue
+		}
+
+		for _, action := range tx.GetTransactionActions().GetChaincodeActions() {
+			ev := action.GetChaincodeEvent()
+			if ev == nil || ev.GetChaincodeId() != e.chaincodeID {
+				continue
+			}
+
+			if len(e.fromTx) > 0 {
+				if ev.TxId == e.fromTx {
+					//reset filter and go to next tx from block
+					e.fromTx = ``
+				}
+				continue
+			}
+
+			select {
+			case e.events <- ev:
+			case <-e.ErrorCloser.Done():
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (e *EventSubscription) Serve(base ErrorCloser, readyForHandling ReadyForHandling) *EventSubscription {
 	e.ErrorCloser = base
 	readyForHandling()
@@ -0,0 +1,83 @@
+package subs
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/pkg/errors"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+)
+
+func NewBlockWithPrivateDataSubscription() *BlockWithPrivateDataSubscription {
+	return &BlockWithPrivateDataSubscription{
+		blocks: make(chan *api.BlockWithPrivateData, 0),
+	}
+}
+
+type BlockWithPrivateDataSubscription struct {
+	blocks chan *api.BlockWithPrivateData
+	ErrorCloser
+}
+
+func (b *BlockWithPrivateDataSubscription) Blocks() <-chan *api.BlockWithPrivateData {
+	return b.blocks
+}
+
+func (b *BlockWithPrivateDataSubscription) Handler(block *peer.BlockAndPrivateData) bool {
+	if block == nil {
+		close(b.blocks)
+		return false
+	}
+
+	pvtData, err := DecodePrivateData(block)
+	if err != nil {
+		// subscription is stopped, so error channel is free
+		select {
+		case b.ErrorCloser.Errors() <- errors.Wrapf(err, "failed to decode private data of block %d", block.GetBlock().GetHeader().GetNumber()):
+		default:
+		}
+		return true
+	}
+
+	select {
+	case b.blocks <- &api.BlockWithPrivateData{Block: block.Block, PvtDataCollections: pvtData}:
+	case <-b.ErrorCloser.Done():
+		return true
+	}
+
+	return false
+}
+
+func (b *BlockWithPrivateDataSubscription) Serve(base ErrorCloser, readyForHandling ReadyForHandling) *BlockWithPrivateDataSubscription {
+	b.ErrorCloser = base
+	readyForHandling()
+	return b
+}
+
+// DecodePrivateData decodes collection read-write sets of block private data
+func DecodePrivateData(block *peer.BlockAndPrivateData) (map[uint64][]*api.CollectionPvtData, error) {
+	pvtData := make(map[uint64][]*api.CollectionPvtData, len(block.GetPrivateDataMap()))
+
+	for txIndex, txPvtRWSet := range block.GetPrivateDataMap() {
+		var collections []*api.CollectionPvtData
+		for _, nsPvtRWSet := range txPvtRWSet.GetNsPvtRwset() {
+			for _, collPvtRWSet := range nsPvtRWSet.GetCollectionPvtRwset() {
+				kvRWSet := new(kvrwset.KVRWSet)
+				if err := proto.Unmarshal(collPvtRWSet.Rwset, kvRWSet); err != nil {
+					return nil, errors.Wrapf(err, "failed to unmarshal rwset of collection %s/%s in tx %d",
+						nsPvtRWSet.Namespace, collPvtRWSet.CollectionName, txIndex)
+				}
+
+				collections = append(collections, &api.CollectionPvtData{
+					Namespace:  nsPvtRWSet.Namespace,
+					Collection: collPvtRWSet.CollectionName,
+					RWSet:      kvRWSet,
+				})
+			}
+		}
+		pvtData[txIndex] = collections
+	}
+
+	return pvtData, nil
+}
@@ -103,3 +103,26 @@ func (ts *TxSubscription) Handler(block *common.Block) bool {
 
 	return false
 }
+
+func (ts *TxSubscription) FilteredHandler(block *peer.FilteredBlock) bool {
+	if block == nil {
+		close(ts.result)
+		return false
+	}
+
+	for _, tx := range block.GetFilteredTransactions() {
+		if api.ChaincodeTx(tx.Txid) != ts.txId {
+			continue
+		}
+
+		if tx.TxValidationCode == peer.TxValidationCode_VALID {
+			ts.result <- &result{code: tx.TxValidationCode, err: nil}
+		} else {
+			err := errors.Errorf("TxId validation code failed: %s", peer.TxValidationCode_name[int32(tx.TxValidationCode)])
+			ts.result <- &result{code: tx.TxValidationCode, err: err}
+		}
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,311 @@
+package peer
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	fabricPeer "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/pkg/errors"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+)
+
+// max length of values presented in diff
+const diffValueLen = 64
+
+// CheckEndorsements compares proposal response payloads of endorsements
+// and returns *api.ErrEndorsementMismatch if they differ
+func CheckEndorsements(responses []*fabricPeer.ProposalResponse) error {
+	if len(responses) < 2 {
+		return nil
+	}
+
+	// group endorsements by payload, most common payload is expected
+	var groups [][]*fabricPeer.ProposalResponse
+	for _, resp := range responses {
+		var found bool
+		for i := range groups {
+			if bytes.Equal(groups[i][0].Payload, resp.Payload) {
+				groups[i] = append(groups[i], resp)
+				found = true
+				break
+			}
+		}
+		if !found {
+			groups = append(groups, []*fabricPeer.ProposalResponse{resp})
+		}
+	}
+
+	if len(groups) == 1 {
+		return nil
+	}
+
+	// endorsers are ordered by MSP id and name, so expected group is chosen deterministically
+	// when groups have the same size
+	for _, group := range groups {
+		sort.SliceStable(group, func(i, j int) bool {
+			return endorserLess(endorser(group[i]), endorser(group[j]))
+		})
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i]) != len(groups[j]) {
+			return len(groups[i]) > len(groups[j])
+		}
+		return endorserLess(endorser(groups[i][0]), endorser(groups[j][0]))
+	})
+
+	expected := groups[0][0]
+	mismatch := &api.ErrEndorsementMismatch{Expected: endorser(expected)}
+
+	for _, group := range groups[1:] {
+		diff := payloadDiff(expected.Payload, group[0].Payload)
+		for _, resp := range group {
+			mismatch.Diverged = append(mismatch.Diverged, api.DivergedEndorsement{
+				Endorser: endorser(resp),
+				Diff:     diff,
+			})
+		}
+	}
+
+	return mismatch
+}
+
+// endorser returns MSP id and certificate common name of peer signed endorsement
+func endorser(resp *fabricPeer.ProposalResponse) api.Endorser {
+	sID := new(msp.SerializedIdentity)
+	if err := proto.Unmarshal(resp.GetEndorsement().GetEndorser(), sID); err != nil {
+		return api.Endorser{Name: `unknown`}
+	}
+
+	e := api.Endorser{MspID: sID.Mspid, Name: `unknown`}
+	if block, _ := pem.Decode(sID.IdBytes); block != nil {
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+			e.Name = cert.Subject.CommonName
+		}
+	}
+	return e
+}
+
+func endorserLess(a, b api.Endorser) bool {
+	if a.MspID != b.MspID {
+		return a.MspID < b.MspID
+	}
+	return a.Name < b.Name
+}
+
+func payloadDiff(expected, actual []byte) []string {
+	expPayload, expAction, err := chaincodeAction(expected)
+	if err != nil {
+		return []string{fmt.Sprintf("expected payload: %s", err)}
+	}
+
+	actPayload, actAction, err := chaincodeAction(actual)
+	if err != nil {
+		return []string{fmt.Sprintf("payload: %s", err)}
+	}
+
+	var diff []string
+	if !bytes.Equal(expPayload.ProposalHash, actPayload.ProposalHash) {
+		diff = append(diff, `proposal hash differs`)
+	}
+
+	if !proto.Equal(expAction.ChaincodeId, actAction.ChaincodeId) {
+		diff = append(diff, fmt.Sprintf("chaincode id: %s != %s", expAction.ChaincodeId, actAction.ChaincodeId))
+	}
+
+	expResp, actResp := expAction.GetResponse(), actAction.GetResponse()
+	if expResp.GetStatus() != actResp.GetStatus() {
+		diff = append(diff, fmt.Sprintf("response status: %d != %d", expResp.GetStatus(), actResp.GetStatus()))
+	}
+	if expResp.GetMessage() != actResp.GetMessage() {
+		diff = append(diff, fmt.Sprintf("response message: %q != %q", expResp.GetMessage(), actResp.GetMessage()))
+	}
+	if !bytes.Equal(expResp.GetPayload(), actResp.GetPayload()) {
+		diff = append(diff, fmt.Sprintf("response payload: %s != %s", shorten(expResp.GetPayload()), shorten(actResp.GetPayload())))
+	}
+
+	if !bytes.Equal(expAction.Events, actAction.Events) {
+		diff = append(diff, `chaincode events differ`)
+	}
+
+	diff = append(diff, rwSetDiff(expAction.Results, actAction.Results)...)
+
+	if len(diff) == 0 {
+		diff = append(diff, `payload bytes differ`)
+	}
+	return diff
+}
+
+func chaincodeAction(payload []byte) (*fabricPeer.ProposalResponsePayload, *fabricPeer.ChaincodeAction, error) {
+	respPayload := new(fabricPeer.ProposalResponsePayload)
+	if err := proto.Unmarshal(payload, respPayload); err != nil {
+		return nil, nil, errors.Wrap(err, `failed to unmarshal proposal response payload`)
+	}
+
+	action := new(fabricPeer.ChaincodeAction)
+	if err := proto.Unmarshal(respPayload.Extension, action); err != nil {
+		return nil, nil, errors.Wrap(err, `failed to unmarshal chaincode action`)
+	}
+
+	return respPayload, action, nil
+}
+
+func rwSetDiff(expected, actual []byte) []string {
+	expSets, err := nsRWSets(expected)
+	if err != nil {
+		return []string{fmt.Sprintf("expected rwset: %s", err)}
+	}
+
+	actSets, err := nsRWSets(actual)
+	if err != nil {
+		return []string{fmt.Sprintf("rwset: %s", err)}
+	}
+
+	namespaces := make([]string, 0)
+	for ns := range expSets {
+		namespaces = append(namespaces, ns)
+	}
+	for ns := range actSets {
+		if _, ok := expSets[ns]; !ok {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	sort.Strings(namespaces)
+
+	var diff []string
+	for _, ns := range namespaces {
+		exp, expOk := expSets[ns]
+		act, actOk := actSets[ns]
+		switch {
+		case !actOk:
+			diff = append(diff, fmt.Sprintf("ns %s: missing", ns))
+			continue
+		case !expOk:
+			diff = append(diff, fmt.Sprintf("ns %s: unexpected", ns))
+			continue
+		}
+
+		for _, line := range kvRWSetDiff(exp.kv, act.kv) {
+			diff = append(diff, fmt.Sprintf("ns %s: %s", ns, line))
+		}
+
+		if !proto.Equal(&rwset.NsReadWriteSet{CollectionHashedRwset: exp.collections},
+			&rwset.NsReadWriteSet{CollectionHashedRwset: act.collections}) {
+			diff = append(diff, fmt.Sprintf("ns %s: collection hashed rwsets differ", ns))
+		}
+	}
+
+	return diff
+}
+
+type nsRWSet struct {
+	kv          *kvrwset.KVRWSet
+	collections []*rwset.CollectionHashedReadWriteSet
+}
+
+func nsRWSets(results []byte) (map[string]nsRWSet, error) {
+	txRWSet := new(rwset.TxReadWriteSet)
+	if err := proto.Unmarshal(results, txRWSet); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal tx rwset`)
+	}
+
+	sets := make(map[string]nsRWSet, len(txRWSet.NsRwset))
+	for _, ns := range txRWSet.NsRwset {
+		kv := new(kvrwset.KVRWSet)
+		if err := proto.Unmarshal(ns.Rwset, kv); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal kv rwset of ns %s", ns.Namespace)
+		}
+		sets[ns.Namespace] = nsRWSet{kv: kv, collections: ns.CollectionHashedRwset}
+	}
+	return sets, nil
+}
+
+func kvRWSetDiff(expected, actual *kvrwset.KVRWSet) []string {
+	var diff []string
+
+	expReads, actReads := make(map[string]string), make(map[string]string)
+	for _, r := range expected.Reads {
+		expReads[r.Key] = versionString(r.Version)
+	}
+	for _, r := range actual.Reads {
+		actReads[r.Key] = versionString(r.Version)
+	}
+	diff = append(diff, mapDiff(`read`, expReads, actReads)...)
+
+	expWrites, actWrites := make(map[string]string), make(map[string]string)
+	for _, w := range expected.Writes {
+		expWrites[w.Key] = writeString(w)
+	}
+	for _, w := range actual.Writes {
+		actWrites[w.Key] = writeString(w)
+	}
+	diff = append(diff, mapDiff(`write`, expWrites, actWrites)...)
+
+	if !proto.Equal(&kvrwset.KVRWSet{RangeQueriesInfo: expected.RangeQueriesInfo},
+		&kvrwset.KVRWSet{RangeQueriesInfo: actual.RangeQueriesInfo}) {
+		diff = append(diff, `range queries differ`)
+	}
+
+	if !proto.Equal(&kvrwset.KVRWSet{MetadataWrites: expected.MetadataWrites},
+		&kvrwset.KVRWSet{MetadataWrites: actual.MetadataWrites}) {
+		diff = append(diff, `metadata writes differ`)
+	}
+
+	return diff
+}
+
+func mapDiff(kind string, expected, actual map[string]string) []string {
+	keys := make([]string, 0)
+	for key := range expected {
+		keys = append(keys, key)
+	}
+	for key := range actual {
+		if _, ok := expected[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var diff []string
+	for _, key := range keys {
+		exp, expOk := expected[key]
+		act, actOk := actual[key]
+		switch {
+		case !actOk:
+			diff = append(diff, fmt.Sprintf("%s %q: missing, expected %s", kind, key, exp))
+		case !expOk:
+			diff = append(diff, fmt.Sprintf("%s %q: unexpected %s", kind, key, act))
+		case exp != act:
+			diff = append(diff, fmt.Sprintf("%s %q: %s != %s", kind, key, exp, act))
+		}
+	}
+	return diff
+}
+
+func versionString(v *kvrwset.Version) string {
+	if v == nil {
+		return `<nil>`
+	}
+	return fmt.Sprintf("%d:%d", v.BlockNum, v.TxNum)
+}
+
+func writeString(w *kvrwset.KVWrite) string {
+	if w.IsDelete {
+		return `<delete>`
+	}
+	return shorten(w.Value)
+}
+
+func shorten(value []byte) string {
+	if len(value) > diffValueLen {
+		return fmt.Sprintf("%q...(%d)", value[:diffValueLen], len(value)-diffValueLen)
+	}
+	return fmt.Sprintf("%q", value)
+}
@@ -0,0 +1,152 @@
+package peer_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	fabricPeer "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/peer"
+)
+
+type result struct {
+	reads       []*kvrwset.KVRead
+	writes      []*kvrwset.KVWrite
+	namespace   string
+	collections []*rwset.CollectionHashedReadWriteSet
+	event       string
+}
+
+func marshal(t *testing.T, msg proto.Message) []byte {
+	data, err := proto.Marshal(msg)
+	require.NoError(t, err)
+	return data
+}
+
+func response(t *testing.T, mspID string, r result) *fabricPeer.ProposalResponse {
+	certs, err := filepath.Glob(`../client/chaincode/testdata/msp/signcerts/*.pem`)
+	require.NoError(t, err)
+	cert, err := ioutil.ReadFile(certs[0])
+	require.NoError(t, err)
+
+	namespace := r.namespace
+	if namespace == `` {
+		namespace = `cc`
+	}
+	results := marshal(t, &rwset.TxReadWriteSet{NsRwset: []*rwset.NsReadWriteSet{{
+		Namespace:             namespace,
+		Rwset:                 marshal(t, &kvrwset.KVRWSet{Reads: r.reads, Writes: r.writes}),
+		CollectionHashedRwset: r.collections,
+	}}})
+
+	var events []byte
+	if r.event != `` {
+		events = marshal(t, &fabricPeer.ChaincodeEvent{ChaincodeId: `cc`, EventName: r.event})
+	}
+
+	return &fabricPeer.ProposalResponse{
+		Payload: marshal(t, &fabricPeer.ProposalResponsePayload{
+			ProposalHash: []byte(`hash`),
+			Extension: marshal(t, &fabricPeer.ChaincodeAction{
+				Results:  results,
+				Events:   events,
+				Response: &fabricPeer.Response{Status: 200},
+			}),
+		}),
+		Endorsement: &fabricPeer.Endorsement{Endorser: marshal(t, &msp.SerializedIdentity{Mspid: mspID, IdBytes: cert})},
+	}
+}
+
+func TestCheckEndorsements(t *testing.T) {
+	base := result{
+		reads:  []*kvrwset.KVRead{{Key: `read`, Version: &kvrwset.Version{BlockNum: 1}}},
+		writes: []*kvrwset.KVWrite{{Key: `write`, Value: []byte(`a`)}},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		diverged result
+		expDiff  []string
+	}{
+		{
+			name: `read version`,
+			diverged: result{
+				reads:  []*kvrwset.KVRead{{Key: `read`, Version: &kvrwset.Version{BlockNum: 2}}},
+				writes: base.writes,
+			},
+			expDiff: []string{`ns cc: read "read": 1:0 != 2:0`},
+		},
+		{
+			name: `write value`,
+			diverged: result{
+				reads:  base.reads,
+				writes: []*kvrwset.KVWrite{{Key: `write`, Value: []byte(`b`)}, {Key: `other`, IsDelete: true}},
+			},
+			expDiff: []string{
+				`ns cc: write "other": unexpected <delete>`,
+				`ns cc: write "write": "a" != "b"`,
+			},
+		},
+		{
+			name: `collection hash`,
+			diverged: result{
+				reads:  base.reads,
+				writes: base.writes,
+				collections: []*rwset.CollectionHashedReadWriteSet{
+					{CollectionName: `collection`, PvtRwsetHash: []byte(`pvt-hash`)}},
+			},
+			expDiff: []string{`ns cc: collection hashed rwsets differ`},
+		},
+		{
+			name:     `event`,
+			diverged: result{reads: base.reads, writes: base.writes, event: `event`},
+			expDiff:  []string{`chaincode events differ`},
+		},
+		{
+			name:     `namespace`,
+			diverged: result{namespace: `other`},
+			expDiff:  []string{`ns cc: missing`, `ns other: unexpected`},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := peer.CheckEndorsements([]*fabricPeer.ProposalResponse{
+				response(t, `org1msp`, base),
+				response(t, `org2msp`, tc.diverged),
+				response(t, `org3msp`, base),
+			})
+
+			mismatch, ok := err.(*api.ErrEndorsementMismatch)
+			require.True(t, ok)
+			require.Equal(t, `org1msp`, mismatch.Expected.MspID)
+			require.Len(t, mismatch.Diverged, 1)
+			require.Equal(t, `org2msp`, mismatch.Diverged[0].Endorser.MspID)
+			require.Equal(t, tc.expDiff, mismatch.Diverged[0].Diff)
+		})
+	}
+}
+
+func TestCheckEndorsements_Tie(t *testing.T) {
+	first := result{writes: []*kvrwset.KVWrite{{Key: `key`, Value: []byte(`a`)}}}
+	second := result{writes: []*kvrwset.KVWrite{{Key: `key`, Value: []byte(`b`)}}}
+
+	// expected payload doesn't depend on order of responses
+	for _, responses := range [][]*fabricPeer.ProposalResponse{
+		{response(t, `org1msp`, first), response(t, `org2msp`, second)},
+		{response(t, `org2msp`, second), response(t, `org1msp`, first)},
+	} {
+		err := peer.CheckEndorsements(responses)
+		require.EqualError(t, err, "endorsements mismatch, expected payload of peer0.org1.example.com (org1msp):\n"+
+			"peer0.org1.example.com (org2msp) diverges:\n"+
+			"\tns cc: write \"key\": \"a\" != \"b\"\n")
+	}
+
+	require.NoError(t, peer.CheckEndorsements([]*fabricPeer.ProposalResponse{
+		response(t, `org1msp`, first), response(t, `org2msp`, first)}))
+}
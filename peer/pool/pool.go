@@ -112,7 +112,7 @@ func (p *peerPool) Process(ctx context.Context, mspId string, proposal *peer.Sig
 	var lastError error
 
 	for pos, poolPeer := range peers {
-		if !poolPeer.ready {
+		if !p.isReady(poolPeer) {
 			log.Debug(api.ErrPeerNotReady.Error(), zap.String(`uri`, poolPeer.peer.Uri()))
 			continue
 		}
@@ -154,6 +154,23 @@ func (p *peerPool) Process(ctx context.Context, mspId string, proposal *peer.Sig
 	return nil, lastError
 
 }
+
+func (p *peerPool) Peers(mspId string) ([]api.Peer, error) {
+	p.storeMx.RLock()
+	defer p.storeMx.RUnlock()
+
+	peers, ok := p.store[mspId]
+	if !ok {
+		return nil, api.ErrMSPNotFound
+	}
+
+	res := make([]api.Peer, 0, len(peers))
+	for _, poolPeer := range peers {
+		res = append(res, poolPeer.peer)
+	}
+	return res, nil
+}
+
 func (p *peerPool) DeliverClient(mspId string, identity msp.SigningIdentity) (api.DeliverClient, error) {
 	poolPeer, err := p.getFirstReadyPeer(mspId)
 	if err != nil {
@@ -162,6 +179,13 @@ func (p *peerPool) DeliverClient(mspId string, identity msp.SigningIdentity) (ap
 	return poolPeer.DeliverClient(identity)
 }
 
+// isReady reads readiness of peer, which is updated by poolChecker under storeMx
+func (p *peerPool) isReady(poolPeer *peerPoolPeer) bool {
+	p.storeMx.RLock()
+	defer p.storeMx.RUnlock()
+	return poolPeer.ready
+}
+
 func (p *peerPool) getFirstReadyPeer(mspId string) (api.Peer, error) {
 	log := p.log.Named(`getFirstReadyPeer`)
 	p.storeMx.RLock()
@@ -183,7 +207,7 @@ func (p *peerPool) getFirstReadyPeer(mspId string) (api.Peer, error) {
 	log.Debug(`Peers pool`, zap.String(`mspId`, mspId), zap.Int(`peerNum`, len(peers)))
 
 	for _, poolPeer := range peers {
-		if poolPeer.ready {
+		if p.isReady(poolPeer) {
 			return poolPeer.peer, nil
 		}
 	}
@@ -2,6 +2,7 @@ package peer
 
 import (
 	"context"
+	"sort"
 
 	"github.com/bogatyr285/hlf-sdk-go/api"
 	"github.com/bogatyr285/hlf-sdk-go/util"
@@ -16,12 +17,31 @@ type processor struct {
 	channelName string
 }
 
-type endorseChannelResponse struct {
-	Response *fabricPeer.ProposalResponse
-	Error    error
+func (p *processor) CreateProposal(chaincodeName string, identity msp.SigningIdentity, fn string, args [][]byte, transArgs api.TransArgs) (*fabricPeer.SignedProposal, api.ChaincodeTx, error) {
+	creator, err := identity.Serialize()
+	if err != nil {
+		return nil, ``, errors.Wrap(err, `failed to get creator`)
+	}
+
+	proposal, txId, err := p.unsignedProposal(chaincodeName, creator, fn, args, transArgs)
+	if err != nil {
+		return nil, ``, err
+	}
+
+	proposalBytes, err := proto.Marshal(proposal)
+	if err != nil {
+		return nil, ``, errors.Wrap(err, `failed to marshal proposal`)
+	}
+
+	signedBytes, err := identity.Sign(proposalBytes)
+	if err != nil {
+		return nil, ``, errors.Wrap(err, `failed to sign proposal bytes`)
+	}
+
+	return &fabricPeer.SignedProposal{ProposalBytes: proposalBytes, Signature: signedBytes}, txId, nil
 }
 
-func (p *processor) CreateProposal(chaincodeName string, identity msp.SigningIdentity, fn string, args [][]byte, transArgs api.TransArgs) (*fabricPeer.SignedProposal, api.ChaincodeTx, error) {
+func (p *processor) unsignedProposal(chaincodeName string, creator []byte, fn string, args [][]byte, transArgs api.TransArgs) (*fabricPeer.Proposal, api.ChaincodeTx, error) {
 	invSpec, err := p.invocationSpec(chaincodeName, fn, args)
 	if err != nil {
 		return nil, ``, errors.Wrap(err, `failed to get invocation spec`)
@@ -29,7 +49,7 @@ func (p *processor) CreateProposal(chaincodeName string, identity msp.SigningIde
 
 	extension := &fabricPeer.ChaincodeHeaderExtension{ChaincodeId: &fabricPeer.ChaincodeID{Name: chaincodeName}}
 
-	txId, nonce, err := util.NewTxWithNonce(identity)
+	txId, nonce, err := util.NewTxWithCreator(creator)
 	if err != nil {
 		return nil, ``, errors.Wrap(err, `failed to get tx id`)
 	}
@@ -44,7 +64,7 @@ func (p *processor) CreateProposal(chaincodeName string, identity msp.SigningIde
 		return nil, ``, errors.Wrap(err, `failed to marshal proposal payload`)
 	}
 
-	sigHeader, err := util.NewSignatureHeader(identity, nonce)
+	sigHeader, err := proto.Marshal(&common.SignatureHeader{Creator: creator, Nonce: nonce})
 	if err != nil {
 		return nil, ``, errors.Wrap(err, `failed to get signatire header`)
 	}
@@ -57,53 +77,200 @@ func (p *processor) CreateProposal(chaincodeName string, identity msp.SigningIde
 		return nil, ``, errors.Wrap(err, `failed to marshal transaction header`)
 	}
 
-	proposal, err := proto.Marshal(&fabricPeer.Proposal{
-		Header:  header,
-		Payload: proposalPayload,
-	})
-	if err != nil {
-		return nil, ``, errors.Wrap(err, `failed to marshal proposal`)
+	return &fabricPeer.Proposal{Header: header, Payload: proposalPayload}, api.ChaincodeTx(txId), nil
+}
+
+func (*processor) Send(ctx context.Context, proposal *fabricPeer.SignedProposal, endorsingMspIDs []string, pool api.PeerPool, opts ...api.PeerSendOpt) ([]*fabricPeer.ProposalResponse, error) {
+	sendOpts := new(api.PeerSendOpts)
+	for _, applyOpt := range opts {
+		if err := applyOpt(sendOpts); err != nil {
+			return nil, err
+		}
 	}
 
-	signedBytes, err := identity.Sign(proposal)
-	if err != nil {
-		return nil, ``, errors.Wrap(err, `failed to sign proposal bytes`)
+	layouts := sendOpts.EndorsementLayouts
+	if len(layouts) == 0 {
+		// one endorsement from each of presented MSPs
+		layout := make(api.EndorsementLayout)
+		for _, mspID := range endorsingMspIDs {
+			layout[mspID]++
+		}
+		layouts = []api.EndorsementLayout{layout}
 	}
 
-	return &fabricPeer.SignedProposal{ProposalBytes: proposal, Signature: signedBytes}, api.ChaincodeTx(txId), nil
+	c := &endorsementCollector{
+		proposal: proposal,
+		pool:     pool,
+		endorsed: make(map[string][]*fabricPeer.ProposalResponse),
+		failed:   make(map[string]bool),
+	}
+
+	return c.collect(ctx, sortLayouts(layouts))
 }
 
-func (*processor) Send(ctx context.Context, proposal *fabricPeer.SignedProposal, endorsingMspIDs []string, pool api.PeerPool) ([]*fabricPeer.ProposalResponse, error) {
-	respList := make([]*fabricPeer.ProposalResponse, 0)
-	respChan := make(chan endorseChannelResponse)
+// endorsementCollector collects endorsements by layouts, endorsements received for failed layout
+// are reused for next ones
+type endorsementCollector struct {
+	proposal *fabricPeer.SignedProposal
+	pool     api.PeerPool
+	// MSP id to endorsements of distinct peers of this MSP
+	endorsed map[string][]*fabricPeer.ProposalResponse
+	// MSPs which can not give more endorsements than already received
+	failed map[string]bool
+}
+
+type endorseMSPResponse struct {
+	MspID     string
+	Responses []*fabricPeer.ProposalResponse
+	Error     error
+}
+
+func (c *endorsementCollector) collect(ctx context.Context, layouts []api.EndorsementLayout) ([]*fabricPeer.ProposalResponse, error) {
+	mErr := new(api.MultiError)
+
+	for _, layout := range layouts {
+		if !c.satisfiable(layout) {
+			continue
+		}
+
+		respChan := make(chan endorseMSPResponse)
+		var requested int
+
+		// send proposals to MSPs concurrently, goroutines get copy of already received endorsements,
+		// so c.endorsed is accessed only by collect
+		for mspID, count := range layout {
+			if need := count - len(c.endorsed[mspID]); need > 0 {
+				requested++
+				endorsed := append([]*fabricPeer.ProposalResponse(nil), c.endorsed[mspID]...)
+				go func(mspID string, need int, endorsed []*fabricPeer.ProposalResponse) {
+					resp, err := c.endorse(ctx, mspID, need, endorsed)
+					respChan <- endorseMSPResponse{MspID: mspID, Responses: resp, Error: err}
+				}(mspID, need, endorsed)
+			}
+		}
+
+		var errOccurred bool
+		for i := 0; i < requested; i++ {
+			resp := <-respChan
+			c.endorsed[resp.MspID] = append(c.endorsed[resp.MspID], resp.Responses...)
+			if resp.Error != nil {
+				errOccurred = true
+				c.failed[resp.MspID] = true
+				mErr.Add(errors.Wrap(resp.Error, resp.MspID))
+			}
+		}
 
-	// send all proposals concurrently
-	for i := 0; i < len(endorsingMspIDs); i++ {
-		go func(mspId string) {
-			resp, err := pool.Process(ctx, mspId, proposal)
-			respChan <- endorseChannelResponse{Response: resp, Error: err}
-		}(endorsingMspIDs[i])
+		if !errOccurred {
+			return c.responses(layout), nil
+		}
 	}
 
-	var errOccurred bool
+	if len(mErr.Errors) == 0 {
+		mErr.Add(errors.New(`no endorsement layout can be satisfied`))
+	}
 
-	mErr := new(api.MultiError)
+	return nil, mErr
+}
 
-	// collecting peer responses
-	for i := 0; i < len(endorsingMspIDs); i++ {
-		resp := <-respChan
-		if resp.Error != nil {
-			errOccurred = true
-			mErr.Add(resp.Error)
+// satisfiable returns false if layout requires more endorsements from failed MSP than already received
+func (c *endorsementCollector) satisfiable(layout api.EndorsementLayout) bool {
+	for mspID, count := range layout {
+		if c.failed[mspID] && len(c.endorsed[mspID]) < count {
+			return false
 		}
-		respList = append(respList, resp.Response)
 	}
+	return true
+}
+
+// endorse collects endorsements from count peers of MSP, which are not presented in endorsed
+func (c *endorsementCollector) endorse(ctx context.Context, mspID string, count int, endorsed []*fabricPeer.ProposalResponse) ([]*fabricPeer.ProposalResponse, error) {
+	// pool chooses ready peer itself when single endorsement is required
+	if count == 1 && len(endorsed) == 0 {
+		resp, err := c.pool.Process(ctx, mspID, c.proposal)
+		if err != nil {
+			return nil, err
+		}
+		return []*fabricPeer.ProposalResponse{resp}, nil
+	}
+
+	peers, err := c.pool.Peers(mspID)
+	if err != nil {
+		return nil, err
+	}
+
+	endorsers := make(map[string]bool)
+	for _, resp := range endorsed {
+		endorsers[string(resp.GetEndorsement().GetEndorser())] = true
+	}
+
+	var (
+		responses []*fabricPeer.ProposalResponse
+		lastError error
+	)
+	for _, p := range peers {
+		if len(responses) == count {
+			break
+		}
+
+		resp, err := p.Endorse(ctx, c.proposal)
+		if err != nil {
+			lastError = errors.Wrap(err, p.Uri())
+			continue
+		}
 
-	if errOccurred {
-		return respList, mErr
+		endorser := string(resp.GetEndorsement().GetEndorser())
+		if endorsers[endorser] {
+			continue
+		}
+		endorsers[endorser] = true
+		responses = append(responses, resp)
 	}
 
-	return respList, nil
+	if len(responses) < count {
+		if lastError == nil {
+			lastError = errors.Errorf("required %d endorsements, got %d", count, len(responses))
+		}
+		return responses, lastError
+	}
+
+	return responses, nil
+}
+
+// responses returns endorsements required by layout in order of MSP ids
+func (c *endorsementCollector) responses(layout api.EndorsementLayout) []*fabricPeer.ProposalResponse {
+	mspIDs := make([]string, 0, len(layout))
+	for mspID := range layout {
+		mspIDs = append(mspIDs, mspID)
+	}
+	sort.Strings(mspIDs)
+
+	respList := make([]*fabricPeer.ProposalResponse, 0)
+	for _, mspID := range mspIDs {
+		respList = append(respList, c.endorsed[mspID][:layout[mspID]]...)
+	}
+	return respList
+}
+
+// sortLayouts returns layouts sorted by number of required endorsements, then by number of MSPs
+func sortLayouts(layouts []api.EndorsementLayout) []api.EndorsementLayout {
+	sorted := make([]api.EndorsementLayout, len(layouts))
+	copy(sorted, layouts)
+
+	total := func(layout api.EndorsementLayout) (sum int) {
+		for _, count := range layout {
+			sum += count
+		}
+		return sum
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if ti, tj := total(sorted[i]), total(sorted[j]); ti != tj {
+			return ti < tj
+		}
+		return len(sorted[i]) < len(sorted[j])
+	})
+
+	return sorted
 }
 
 func (p *processor) invocationSpec(chaincodeName string, fn string, args [][]byte) ([]byte, error) {
@@ -129,6 +296,12 @@ func (p *processor) prepareArgs(fn string, args [][]byte) [][]byte {
 	return byteArgs
 }
 
+// NewProposal creates unsigned proposal for serialized creator identity,
+// marshalled proposal must be signed by creator before sending to endorsers
+func NewProposal(channelName, chaincodeName string, creator []byte, fn string, args [][]byte, transArgs api.TransArgs) (*fabricPeer.Proposal, api.ChaincodeTx, error) {
+	return (&processor{channelName: channelName}).unsignedProposal(chaincodeName, creator, fn, args, transArgs)
+}
+
 func NewProcessor(channelName string) api.PeerProcessor {
 	return &processor{channelName: channelName}
 }
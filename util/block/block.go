@@ -0,0 +1,364 @@
+// Package block decodes blocks and transactions into Go structs
+package block
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/pkg/errors"
+
+	"github.com/bogatyr285/hlf-sdk-go/configtx"
+	"github.com/bogatyr285/hlf-sdk-go/util/txflags"
+)
+
+type Block struct {
+	Number       uint64         `json:"number"`
+	PreviousHash []byte         `json:"previousHash"`
+	DataHash     []byte         `json:"dataHash"`
+	Transactions []*Transaction `json:"transactions"`
+}
+
+type Transaction struct {
+	// Index is index of transaction in block
+	Index          int            `json:"index"`
+	ValidationCode ValidationCode `json:"validationCode"`
+	ChannelHeader  *ChannelHeader `json:"channelHeader"`
+	Creator        *Identity      `json:"creator"`
+	// Actions are set for endorser transactions
+	Actions []*Action `json:"actions,omitempty"`
+	// Config is set for config transactions
+	Config *Config `json:"config,omitempty"`
+	// Error is set if transaction can't be decoded, only index and validation code are set then
+	Error string `json:"error,omitempty"`
+}
+
+// Config is channel config of config transaction parsed by configtx package
+type Config struct {
+	Sequence uint64                  `json:"sequence"`
+	Channel  *configtx.ChannelConfig `json:"channel"`
+}
+
+type ChannelHeader struct {
+	Type      HeaderType `json:"type"`
+	ChannelID string     `json:"channelId"`
+	TxID      string     `json:"txId"`
+	Timestamp time.Time  `json:"timestamp"`
+	Epoch     uint64     `json:"epoch"`
+}
+
+// Identity is decoded serialized identity, Cert is PEM encoded certificate
+type Identity struct {
+	MSPID string `json:"mspId"`
+	Cert  string `json:"cert"`
+}
+
+type Endorser struct {
+	Identity  *Identity `json:"identity"`
+	Signature []byte    `json:"signature"`
+}
+
+// Action is chaincode action of endorser transaction
+type Action struct {
+	Creator     *Identity         `json:"creator"`
+	ChaincodeID *peer.ChaincodeID `json:"chaincodeId"`
+	Args        [][]byte          `json:"args"`
+	// ProposalPayload is marshaled chaincode proposal payload without transient map
+	ProposalPayload []byte               `json:"proposalPayload"`
+	Response        *peer.Response       `json:"response"`
+	RWSets          []*NsRWSet           `json:"rwsets"`
+	Endorsers       []*Endorser          `json:"endorsers"`
+	Event           *peer.ChaincodeEvent `json:"event,omitempty"`
+}
+
+// NsRWSet is read-write set of namespace
+type NsRWSet struct {
+	Namespace        string            `json:"namespace"`
+	RWSet            *kvrwset.KVRWSet  `json:"rwset"`
+	CollectionHashes []*CollectionHash `json:"collectionHashes,omitempty"`
+}
+
+// CollectionHash is hashed read-write set of private data collection
+type CollectionHash struct {
+	Collection   string               `json:"collection"`
+	HashedRWSet  *kvrwset.HashedRWSet `json:"hashedRwset"`
+	PvtRWSetHash []byte               `json:"pvtRwsetHash"`
+}
+
+// HeaderType is common.HeaderType exported to JSON by name
+type HeaderType common.HeaderType
+
+func (t HeaderType) String() string {
+	return common.HeaderType(t).String()
+}
+
+func (t HeaderType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// ValidationCode is peer.TxValidationCode exported to JSON by name
+type ValidationCode peer.TxValidationCode
+
+func (c ValidationCode) String() string {
+	return peer.TxValidationCode(c).String()
+}
+
+func (c ValidationCode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.String())
+}
+
+// Decode decodes all transactions of block, validation codes are taken from transactions filter of block metadata.
+// Transaction which can't be decoded doesn't fail the block, its decoding error is set to Transaction.Error
+func Decode(block *common.Block) (*Block, error) {
+	if block.GetHeader() == nil {
+		return nil, errors.New(`block header is missing`)
+	}
+
+	b := &Block{
+		Number:       block.Header.Number,
+		PreviousHash: block.Header.PreviousHash,
+		DataHash:     block.Header.DataHash,
+	}
+
+	txFilter := txflags.ValidationFlags(block.GetMetadata().GetMetadata()[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	for i, data := range block.GetData().GetData() {
+		tx, err := decodeTransaction(data)
+		if err != nil {
+			tx = &Transaction{Error: errors.Wrapf(err, "failed to decode tx %d", i).Error()}
+		}
+
+		tx.Index = i
+		tx.ValidationCode = ValidationCode(peer.TxValidationCode_NOT_VALIDATED)
+		if i < len(txFilter) {
+			tx.ValidationCode = ValidationCode(txFilter.Flag(i))
+		}
+		b.Transactions = append(b.Transactions, tx)
+	}
+
+	return b, nil
+}
+
+func decodeTransaction(data []byte) (*Transaction, error) {
+	envelope := new(common.Envelope)
+	if err := proto.Unmarshal(data, envelope); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal envelope`)
+	}
+	return DecodeEnvelope(envelope)
+}
+
+// JSON returns block encoded to JSON
+func (b *Block) JSON() ([]byte, error) {
+	return json.Marshal(b)
+}
+
+// DecodeEnvelope decodes transaction envelope, validation code is not set
+func DecodeEnvelope(envelope *common.Envelope) (*Transaction, error) {
+	payload := new(common.Payload)
+	if err := proto.Unmarshal(envelope.Payload, payload); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal payload`)
+	}
+
+	chHeader := new(common.ChannelHeader)
+	if err := proto.Unmarshal(payload.GetHeader().GetChannelHeader(), chHeader); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal channel header`)
+	}
+
+	tx := &Transaction{
+		ChannelHeader: &ChannelHeader{
+			Type:      HeaderType(chHeader.Type),
+			ChannelID: chHeader.ChannelId,
+			TxID:      chHeader.TxId,
+			Epoch:     chHeader.Epoch,
+		},
+	}
+	if chHeader.Timestamp != nil {
+		timestamp, err := ptypes.Timestamp(chHeader.Timestamp)
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to convert timestamp`)
+		}
+		tx.ChannelHeader.Timestamp = timestamp
+	}
+
+	var err error
+	if tx.Creator, err = signatureHeaderCreator(payload.GetHeader().GetSignatureHeader()); err != nil {
+		return nil, err
+	}
+
+	switch common.HeaderType(chHeader.Type) {
+	case common.HeaderType_ENDORSER_TRANSACTION:
+		if tx.Actions, err = decodeActions(payload.Data); err != nil {
+			return nil, err
+		}
+	case common.HeaderType_CONFIG:
+		if tx.Config, err = decodeConfig(payload.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	return tx, nil
+}
+
+func decodeConfig(data []byte) (*Config, error) {
+	envelope := new(common.ConfigEnvelope)
+	if err := proto.Unmarshal(data, envelope); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal config envelope`)
+	}
+
+	channel, err := configtx.ParseConfig(envelope.Config)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to parse channel config`)
+	}
+
+	return &Config{Sequence: envelope.Config.GetSequence(), Channel: channel}, nil
+}
+
+func decodeActions(data []byte) ([]*Action, error) {
+	tx := new(peer.Transaction)
+	if err := proto.Unmarshal(data, tx); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal transaction`)
+	}
+
+	actions := make([]*Action, len(tx.Actions))
+	for i, txAction := range tx.Actions {
+		action, err := decodeAction(txAction)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode action %d", i)
+		}
+		actions[i] = action
+	}
+	return actions, nil
+}
+
+func decodeAction(txAction *peer.TransactionAction) (*Action, error) {
+	creator, err := signatureHeaderCreator(txAction.Header)
+	if err != nil {
+		return nil, err
+	}
+	action := &Action{Creator: creator}
+
+	actionPayload := new(peer.ChaincodeActionPayload)
+	if err = proto.Unmarshal(txAction.Payload, actionPayload); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal chaincode action payload`)
+	}
+
+	if err = action.decodeProposalPayload(actionPayload.ChaincodeProposalPayload); err != nil {
+		return nil, err
+	}
+
+	for _, endorsement := range actionPayload.GetAction().GetEndorsements() {
+		endorser, err := decodeIdentity(endorsement.Endorser)
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to decode endorser`)
+		}
+		action.Endorsers = append(action.Endorsers, &Endorser{Identity: endorser, Signature: endorsement.Signature})
+	}
+
+	respPayload := new(peer.ProposalResponsePayload)
+	if err = proto.Unmarshal(actionPayload.GetAction().GetProposalResponsePayload(), respPayload); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal proposal response payload`)
+	}
+
+	ccAction := new(peer.ChaincodeAction)
+	if err = proto.Unmarshal(respPayload.Extension, ccAction); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal chaincode action`)
+	}
+
+	if ccAction.ChaincodeId != nil {
+		action.ChaincodeID = ccAction.ChaincodeId
+	}
+	action.Response = ccAction.Response
+
+	if len(ccAction.Events) > 0 {
+		action.Event = new(peer.ChaincodeEvent)
+		if err = proto.Unmarshal(ccAction.Events, action.Event); err != nil {
+			return nil, errors.Wrap(err, `failed to unmarshal chaincode event`)
+		}
+	}
+
+	if action.RWSets, err = decodeRWSets(ccAction.Results); err != nil {
+		return nil, err
+	}
+
+	return action, nil
+}
+
+// decodeProposalPayload sets chaincode id, args and proposal payload without transient map
+func (a *Action) decodeProposalPayload(data []byte) error {
+	proposalPayload := new(peer.ChaincodeProposalPayload)
+	if err := proto.Unmarshal(data, proposalPayload); err != nil {
+		return errors.Wrap(err, `failed to unmarshal chaincode proposal payload`)
+	}
+
+	// transient map is already removed by endorsing client, it is cleared in case it was not
+	proposalPayload.TransientMap = nil
+	var err error
+	if a.ProposalPayload, err = proto.Marshal(proposalPayload); err != nil {
+		return errors.Wrap(err, `failed to marshal chaincode proposal payload`)
+	}
+
+	spec := new(peer.ChaincodeInvocationSpec)
+	if err = proto.Unmarshal(proposalPayload.Input, spec); err != nil {
+		return errors.Wrap(err, `failed to unmarshal chaincode invocation spec`)
+	}
+	a.ChaincodeID = spec.GetChaincodeSpec().GetChaincodeId()
+	a.Args = spec.GetChaincodeSpec().GetInput().GetArgs()
+	return nil
+}
+
+func decodeRWSets(results []byte) ([]*NsRWSet, error) {
+	txRWSet := new(rwset.TxReadWriteSet)
+	if err := proto.Unmarshal(results, txRWSet); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal tx rwset`)
+	}
+
+	var nsRWSets []*NsRWSet
+	for _, nsRWSet := range txRWSet.NsRwset {
+		ns := &NsRWSet{Namespace: nsRWSet.Namespace, RWSet: new(kvrwset.KVRWSet)}
+		if err := proto.Unmarshal(nsRWSet.Rwset, ns.RWSet); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal rwset of namespace %s", nsRWSet.Namespace)
+		}
+
+		for _, collection := range nsRWSet.CollectionHashedRwset {
+			hash := &CollectionHash{
+				Collection:   collection.CollectionName,
+				HashedRWSet:  new(kvrwset.HashedRWSet),
+				PvtRWSetHash: collection.PvtRwsetHash,
+			}
+			if err := proto.Unmarshal(collection.HashedRwset, hash.HashedRWSet); err != nil {
+				return nil, errors.Wrapf(err, "failed to unmarshal hashed rwset of collection %s", collection.CollectionName)
+			}
+			ns.CollectionHashes = append(ns.CollectionHashes, hash)
+		}
+		nsRWSets = append(nsRWSets, ns)
+	}
+	return nsRWSets, nil
+}
+
+func signatureHeaderCreator(data []byte) (*Identity, error) {
+	header := new(common.SignatureHeader)
+	if err := proto.Unmarshal(data, header); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal signature header`)
+	}
+	creator, err := decodeIdentity(header.Creator)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to decode creator`)
+	}
+	return creator, nil
+}
+
+func decodeIdentity(data []byte) (*Identity, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	id := new(msp.SerializedIdentity)
+	if err := proto.Unmarshal(data, id); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal serialized identity`)
+	}
+	return &Identity{MSPID: id.Mspid, Cert: string(id.IdBytes)}, nil
+}
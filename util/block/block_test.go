@@ -0,0 +1,185 @@
+package block_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bogatyr285/hlf-sdk-go/api"
+	"github.com/bogatyr285/hlf-sdk-go/configtx"
+	"github.com/bogatyr285/hlf-sdk-go/util"
+	"github.com/bogatyr285/hlf-sdk-go/util/block"
+)
+
+func marshal(t *testing.T, msg proto.Message) []byte {
+	data, err := proto.Marshal(msg)
+	require.NoError(t, err)
+	return data
+}
+
+func signatureHeader(t *testing.T, mspID string) []byte {
+	return marshal(t, &common.SignatureHeader{
+		Creator: marshal(t, &msp.SerializedIdentity{Mspid: mspID, IdBytes: []byte(`cert-` + mspID)})})
+}
+
+func envelope(t *testing.T, headerType common.HeaderType, txID string, data []byte) []byte {
+	chHeader := protoutil.MakeChannelHeader(headerType, 0, `channel`, 0)
+	chHeader.TxId = txID
+	return marshal(t, &common.Envelope{Payload: marshal(t, &common.Payload{
+		Header: &common.Header{ChannelHeader: marshal(t, chHeader), SignatureHeader: signatureHeader(t, `org1msp`)},
+		Data:   data,
+	})})
+}
+
+func txAction(t *testing.T, ccName, eventName string) *peer.TransactionAction {
+	results := marshal(t, &rwset.TxReadWriteSet{NsRwset: []*rwset.NsReadWriteSet{{
+		Namespace: ccName,
+		Rwset:     marshal(t, &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: `key`, Value: []byte(`value`)}}}),
+		CollectionHashedRwset: []*rwset.CollectionHashedReadWriteSet{{
+			CollectionName: `collection`,
+			HashedRwset:    marshal(t, &kvrwset.HashedRWSet{HashedWrites: []*kvrwset.KVWriteHash{{KeyHash: []byte(`hash`)}}}),
+			PvtRwsetHash:   []byte(`pvt-hash`),
+		}},
+	}}})
+
+	ccAction := &peer.ChaincodeAction{
+		Results:     results,
+		Events:      marshal(t, &peer.ChaincodeEvent{ChaincodeId: ccName, EventName: eventName}),
+		Response:    &peer.Response{Status: 200},
+		ChaincodeId: &peer.ChaincodeID{Name: ccName, Version: `1.0`},
+	}
+	proposalPayload := &peer.ChaincodeProposalPayload{
+		Input: marshal(t, &peer.ChaincodeInvocationSpec{ChaincodeSpec: &peer.ChaincodeSpec{
+			ChaincodeId: &peer.ChaincodeID{Name: ccName},
+			Input:       &peer.ChaincodeInput{Args: [][]byte{[]byte(`invoke`), []byte(eventName)}},
+		}}),
+		TransientMap: map[string][]byte{`secret`: []byte(`value`)},
+	}
+
+	return &peer.TransactionAction{
+		Header: signatureHeader(t, `org1msp`),
+		Payload: marshal(t, &peer.ChaincodeActionPayload{
+			ChaincodeProposalPayload: marshal(t, proposalPayload),
+			Action: &peer.ChaincodeEndorsedAction{
+				ProposalResponsePayload: marshal(t, &peer.ProposalResponsePayload{Extension: marshal(t, ccAction)}),
+				Endorsements: []*peer.Endorsement{
+					{Endorser: marshal(t, &msp.SerializedIdentity{Mspid: `org2msp`, IdBytes: []byte(`cert-org2msp`)}), Signature: []byte(`sig`)},
+				},
+			},
+		}),
+	}
+}
+
+// channelConfig returns config of channel created by ApplicationGenesis profile with presented sequence
+func channelConfig(t *testing.T, sequence uint64) *common.Config {
+	profile, err := configtx.LoadProfile(`../../configtx/testdata/configtx.yaml`, `ApplicationGenesis`)
+	require.NoError(t, err)
+	genesis, err := configtx.GenesisBlock(`channel`, profile)
+	require.NoError(t, err)
+	config, err := util.GetConfigFromBlock(genesis)
+	require.NoError(t, err)
+	config.Sequence = sequence
+	return config
+}
+
+func TestDecode(t *testing.T) {
+	b := protoutil.NewBlock(3, []byte(`prev`))
+	b.Data.Data = [][]byte{
+		envelope(t, common.HeaderType_ENDORSER_TRANSACTION, `tx-1`, marshal(t, &peer.Transaction{
+			Actions: []*peer.TransactionAction{txAction(t, `cc1`, `first`), txAction(t, `cc2`, `second`)}})),
+		envelope(t, common.HeaderType_CONFIG, ``, marshal(t, &common.ConfigEnvelope{Config: channelConfig(t, 2)})),
+	}
+	b.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = []byte{
+		uint8(peer.TxValidationCode_MVCC_READ_CONFLICT), uint8(peer.TxValidationCode_VALID)}
+
+	decoded, err := block.Decode(b)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), decoded.Number)
+	require.Len(t, decoded.Transactions, 2)
+
+	tx := decoded.Transactions[0]
+	require.Equal(t, block.ValidationCode(peer.TxValidationCode_MVCC_READ_CONFLICT), tx.ValidationCode)
+	require.Equal(t, `tx-1`, tx.ChannelHeader.TxID)
+	require.Equal(t, &block.Identity{MSPID: `org1msp`, Cert: `cert-org1msp`}, tx.Creator)
+	require.Len(t, tx.Actions, 2)
+
+	action := tx.Actions[1]
+	require.Equal(t, `cc2`, action.ChaincodeID.Name)
+	require.Equal(t, `1.0`, action.ChaincodeID.Version)
+	require.Equal(t, [][]byte{[]byte(`invoke`), []byte(`second`)}, action.Args)
+	require.Equal(t, `second`, action.Event.EventName)
+	require.Equal(t, `org2msp`, action.Endorsers[0].Identity.MSPID)
+	require.Equal(t, `key`, action.RWSets[0].RWSet.Writes[0].Key)
+	require.Equal(t, []byte(`pvt-hash`), action.RWSets[0].CollectionHashes[0].PvtRWSetHash)
+
+	proposalPayload := new(peer.ChaincodeProposalPayload)
+	require.NoError(t, proto.Unmarshal(action.ProposalPayload, proposalPayload))
+	require.Nil(t, proposalPayload.TransientMap)
+
+	config := decoded.Transactions[1]
+	require.Equal(t, block.HeaderType(common.HeaderType_CONFIG), config.ChannelHeader.Type)
+	require.Equal(t, uint64(2), config.Config.Sequence)
+	org, ok := config.Config.Channel.Application.OrganizationByMSP(`org1msp`)
+	require.True(t, ok)
+	require.Equal(t, []api.HostPort{{Host: `peer0.org1.example.com`, Port: 7051}}, org.AnchorPeers)
+
+	data, err := decoded.JSON()
+	require.NoError(t, err)
+	var exported map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &exported))
+	txs := exported[`transactions`].([]interface{})
+	require.Equal(t, `MVCC_READ_CONFLICT`, txs[0].(map[string]interface{})[`validationCode`])
+	require.Equal(t, `CONFIG`, txs[1].(map[string]interface{})[`channelHeader`].(map[string]interface{})[`type`])
+	require.Equal(t, float64(2), txs[1].(map[string]interface{})[`config`].(map[string]interface{})[`sequence`])
+}
+
+func TestDecode_MalformedTx(t *testing.T) {
+	b := protoutil.NewBlock(4, []byte(`prev`))
+	b.Data.Data = [][]byte{
+		[]byte(`not an envelope`),
+		envelope(t, common.HeaderType_ENDORSER_TRANSACTION, `tx-2`, []byte(`not a transaction`)),
+		envelope(t, common.HeaderType_ENDORSER_TRANSACTION, `tx-3`, marshal(t, &peer.Transaction{
+			Actions: []*peer.TransactionAction{txAction(t, `cc1`, `third`)}})),
+	}
+	b.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = []byte{
+		uint8(peer.TxValidationCode_BAD_PAYLOAD), uint8(peer.TxValidationCode_VALID), uint8(peer.TxValidationCode_VALID)}
+
+	decoded, err := block.Decode(b)
+	require.NoError(t, err)
+	require.Len(t, decoded.Transactions, 3)
+
+	broken := decoded.Transactions[0]
+	require.Equal(t, 0, broken.Index)
+	require.Equal(t, block.ValidationCode(peer.TxValidationCode_BAD_PAYLOAD), broken.ValidationCode)
+	require.Contains(t, broken.Error, `failed to decode tx 0: failed to unmarshal envelope`)
+	require.Nil(t, broken.ChannelHeader)
+
+	require.Contains(t, decoded.Transactions[1].Error, `failed to decode tx 1: failed to unmarshal transaction`)
+
+	tx := decoded.Transactions[2]
+	require.Empty(t, tx.Error)
+	require.Equal(t, 2, tx.Index)
+	require.Equal(t, `tx-3`, tx.ChannelHeader.TxID)
+	require.Equal(t, `cc1`, tx.Actions[0].ChaincodeID.Name)
+
+	data, err := decoded.JSON()
+	require.NoError(t, err)
+	var exported map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &exported))
+	txs := exported[`transactions`].([]interface{})
+	require.Contains(t, txs[0].(map[string]interface{})[`error`], `failed to decode tx 0`)
+	require.NotContains(t, txs[2].(map[string]interface{}), `error`)
+}
+
+func TestDecode_NoHeader(t *testing.T) {
+	_, err := block.Decode(&common.Block{Data: &common.BlockData{}})
+	require.EqualError(t, err, `block header is missing`)
+}
@@ -3,11 +3,13 @@ package util
 import (
 	"bytes"
 	"context"
+	"sort"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 
 	"github.com/bogatyr285/hlf-sdk-go/api"
@@ -15,21 +17,98 @@ import (
 
 var (
 	ErrOrdererGroupNotFound = errors.New(`orderer addresses not found`)
+	ErrNotConfigBlock       = errors.New(`block is not config block`)
 )
 
+// GetConfigFromBlock returns channel config from config block
+func GetConfigFromBlock(block *common.Block) (*common.Config, error) {
+	if len(block.GetData().GetData()) != 1 {
+		return nil, ErrNotConfigBlock
+	}
+
+	envelope, err := protoutil.UnmarshalEnvelope(block.Data.Data[0])
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal envelope`)
+	}
+
+	payload, err := protoutil.UnmarshalPayload(envelope.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal payload`)
+	}
+
+	chHeader, err := protoutil.UnmarshalChannelHeader(payload.GetHeader().GetChannelHeader())
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal channel header`)
+	}
+
+	if common.HeaderType(chHeader.Type) != common.HeaderType_CONFIG {
+		return nil, ErrNotConfigBlock
+	}
+
+	configEnvelope := new(common.ConfigEnvelope)
+	if err = proto.Unmarshal(payload.Data, configEnvelope); err != nil {
+		return nil, errors.Wrap(err, `failed to unmarshal config envelope`)
+	}
+
+	return configEnvelope.Config, nil
+}
+
+// GetOrdererHostFromChannelConfig returns first orderer address from channel config
 func GetOrdererHostFromChannelConfig(conf *common.Config) (string, error) {
-	ordValues, ok := conf.ChannelGroup.Values[channelconfig.OrdererAddressesKey]
-	if !ok {
-		return ``, ErrOrdererGroupNotFound
+	addresses, err := GetOrdererHostsFromChannelConfig(conf)
+	if err != nil {
+		return ``, err
+	}
+	return addresses[0], nil
+}
+
+// GetOrdererHostsFromChannelConfig returns orderer addresses of orderer organizations
+// and global orderer addresses from channel config
+func GetOrdererHostsFromChannelConfig(conf *common.Config) ([]string, error) {
+	var addresses []string
+	seen := make(map[string]bool)
+
+	appendAddresses := func(value *common.ConfigValue) error {
+		ordererAddresses := common.OrdererAddresses{}
+		if err := proto.Unmarshal(value.Value, &ordererAddresses); err != nil {
+			return errors.Wrap(err, `failed to unmarshal orderer addresses`)
+		}
+		for _, address := range ordererAddresses.Addresses {
+			if !seen[address] {
+				seen[address] = true
+				addresses = append(addresses, address)
+			}
+		}
+		return nil
 	}
 
-	ordererAddresses := common.OrdererAddresses{}
-	if err := proto.Unmarshal(ordValues.Value, &ordererAddresses); err != nil {
-		return ``, errors.Wrap(err, `failed to unmarshal orderer addresses`)
+	if ordGroup, ok := conf.GetChannelGroup().GetGroups()[channelconfig.OrdererGroupKey]; ok {
+		orgNames := make([]string, 0, len(ordGroup.Groups))
+		for name := range ordGroup.Groups {
+			orgNames = append(orgNames, name)
+		}
+		sort.Strings(orgNames)
+
+		for _, name := range orgNames {
+			if endpoints, ok := ordGroup.Groups[name].Values[channelconfig.EndpointsKey]; ok {
+				if err := appendAddresses(endpoints); err != nil {
+					return nil, err
+				}
+			}
+		}
 	}
 
-	// TODO return all addresses instead of first
-	return ordererAddresses.Addresses[0], nil
+	if ordValues, ok := conf.GetChannelGroup().GetValues()[channelconfig.OrdererAddressesKey]; ok {
+		if err := appendAddresses(ordValues); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(addresses) == 0 {
+		return nil, ErrOrdererGroupNotFound
+	}
+
+	return addresses, nil
 }
 
 func ProceedChannelUpdate(ctx context.Context, channelName string, update *common.ConfigUpdate, orderer api.Orderer, id msp.SigningIdentity) error {
@@ -38,47 +117,78 @@ func ProceedChannelUpdate(ctx context.Context, channelName string, update *commo
 		return errors.Wrap(err, `failed to marshal common.ConfigUpdate`)
 	}
 
-	txId, nonce, err := NewTxWithNonce(id)
+	sig, err := NewConfigSignature(id, confUpdBytes)
+	if err != nil {
+		return err
+	}
+
+	envelope, err := NewConfigUpdateTx(channelName, &common.ConfigUpdateEnvelope{
+		ConfigUpdate: confUpdBytes,
+		Signatures:   []*common.ConfigSignature{sig},
+	}, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := orderer.Broadcast(ctx, envelope); err != nil {
+		return errors.WithMessage(err, "failed broadcast to orderer")
+	}
+
+	return nil
+}
+
+// NewConfigSignature returns signature of marshalled common.ConfigUpdate made by presented identity
+func NewConfigSignature(id msp.SigningIdentity, configUpdate []byte) (*common.ConfigSignature, error) {
+	_, nonce, err := NewTxWithNonce(id)
 	if err != nil {
-		return errors.Wrap(err, `failed to get nonce`)
+		return nil, errors.Wrap(err, `failed to get nonce`)
 	}
 
 	signatureHeader, err := NewSignatureHeader(id, nonce)
 	if err != nil {
-		return errors.Wrap(err, `failed to get signature header`)
+		return nil, errors.Wrap(err, `failed to get signature header`)
 	}
 
-	buf := bytes.NewBuffer(signatureHeader)
-	buf.Write(confUpdBytes)
+	buf := bytes.NewBuffer(nil)
+	buf.Write(signatureHeader)
+	buf.Write(configUpdate)
 
 	signature, err := id.Sign(buf.Bytes())
 	if err != nil {
-		return errors.Wrap(err, `failed to sign bytes`)
+		return nil, errors.Wrap(err, `failed to sign bytes`)
 	}
 
-	sig := &common.ConfigSignature{
+	return &common.ConfigSignature{
 		SignatureHeader: signatureHeader,
 		Signature:       signature,
+	}, nil
+}
+
+// NewConfigUpdateTx returns config update transaction with presented signatures, signed by submitter identity
+func NewConfigUpdateTx(channelName string, confUpdEnvelope *common.ConfigUpdateEnvelope, id msp.SigningIdentity) (*common.Envelope, error) {
+	confUpdEnvBytes, err := proto.Marshal(confUpdEnvelope)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to marshal common.ConfigUpdateEnvelope`)
 	}
 
-	confUpdEnvelope := &common.ConfigUpdateEnvelope{
-		ConfigUpdate: confUpdBytes,
-		Signatures:   []*common.ConfigSignature{sig},
+	txId, nonce, err := NewTxWithNonce(id)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to get nonce`)
 	}
 
-	confUpdEnvBytes, err := proto.Marshal(confUpdEnvelope)
+	signatureHeader, err := NewSignatureHeader(id, nonce)
 	if err != nil {
-		return errors.Wrap(err, `failed to marshal common.ConfigUpdateEnvelope`)
+		return nil, errors.Wrap(err, `failed to get signature header`)
 	}
 
 	channelHeader, err := NewChannelHeader(common.HeaderType_CONFIG_UPDATE, txId, channelName, 0, nil)
 	if err != nil {
-		return errors.Wrap(err, `failed to get channel header`)
+		return nil, errors.Wrap(err, `failed to get channel header`)
 	}
 
 	payload, err := NewPayloadFromHeader(channelHeader, signatureHeader, confUpdEnvBytes)
 	if err != nil {
-		return errors.Wrap(err, `failed to get payload`)
+		return nil, errors.Wrap(err, `failed to get payload`)
 	}
 
 	envelope := &common.Envelope{
@@ -87,12 +197,8 @@ func ProceedChannelUpdate(ctx context.Context, channelName string, update *commo
 
 	envelope.Signature, err = id.Sign(envelope.Payload)
 	if err != nil {
-		return errors.WithMessage(err, "signing payload failed")
+		return nil, errors.WithMessage(err, "signing payload failed")
 	}
 
-	if _, err := orderer.Broadcast(ctx, envelope); err != nil {
-		return errors.WithMessage(err, "failed broadcast to orderer")
-	}
-
-	return nil
+	return envelope, nil
 }
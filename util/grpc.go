@@ -45,6 +45,40 @@ const (
 	maxSendMsgSize = 100 * 1024 * 1024
 )
 
+// NewTLSConfig creates TLS config with CA certificate and client certificate for mutual TLS if they are presented
+func NewTLSConfig(c config.TlsConfig) (*tls.Config, error) {
+	var err error
+	tlsCfg := &tls.Config{InsecureSkipVerify: c.SkipVerify}
+	// if custom CA certificate is presented, use it
+	if c.CACertPath != `` {
+		caCert, err := ioutil.ReadFile(c.CACertPath)
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to read CA certificate`)
+		}
+		certPool := x509.NewCertPool()
+		if ok := certPool.AppendCertsFromPEM(caCert); !ok {
+			return nil, errors.New(`failed to append CA certificate to chain`)
+		}
+		tlsCfg.RootCAs = certPool
+	} else {
+		// otherwise we use system certificates
+		if tlsCfg.RootCAs, err = x509.SystemCertPool(); err != nil {
+			return nil, errors.Wrap(err, `failed to get system cert pool`)
+		}
+	}
+	if c.CertPath != `` {
+		// use mutual tls if certificate and pk is presented
+		if c.KeyPath != `` {
+			cert, err := tls.LoadX509KeyPair(c.CertPath, c.KeyPath)
+			if err != nil {
+				return nil, errors.Wrap(err, `failed to load client certificate`)
+			}
+			tlsCfg.Certificates = append(tlsCfg.Certificates, cert)
+		}
+	}
+	return tlsCfg, nil
+}
+
 // NewGRPCOptionsFromConfig - adds tracing, TLS certs and connection limits
 func NewGRPCOptionsFromConfig(c config.ConnectionConfig, log *zap.Logger) ([]grpc.DialOption, error) {
 
@@ -59,38 +93,12 @@ func NewGRPCOptionsFromConfig(c config.ConnectionConfig, log *zap.Logger) ([]grp
 	}
 
 	if c.Tls.Enabled {
-		var err error
-		var tlsCfg tls.Config
-		tlsCfg.InsecureSkipVerify = c.Tls.SkipVerify
-		// if custom CA certificate is presented, use it
-		if c.Tls.CACertPath != `` {
-			caCert, err := ioutil.ReadFile(c.Tls.CACertPath)
-			if err != nil {
-				return nil, errors.Wrap(err, `failed to read CA certificate`)
-			}
-			certPool := x509.NewCertPool()
-			if ok := certPool.AppendCertsFromPEM(caCert); !ok {
-				return nil, errors.New(`failed to append CA certificate to chain`)
-			}
-			tlsCfg.RootCAs = certPool
-		} else {
-			// otherwise we use system certificates
-			if tlsCfg.RootCAs, err = x509.SystemCertPool(); err != nil {
-				return nil, errors.Wrap(err, `failed to get system cert pool`)
-			}
-		}
-		if c.Tls.CertPath != `` {
-			// use mutual tls if certificate and pk is presented
-			if c.Tls.KeyPath != `` {
-				cert, err := tls.LoadX509KeyPair(c.Tls.CertPath, c.Tls.KeyPath)
-				if err != nil {
-					return nil, errors.Wrap(err, `failed to load client certificate`)
-				}
-				tlsCfg.Certificates = append(tlsCfg.Certificates, cert)
-			}
+		tlsCfg, err := NewTLSConfig(c.Tls)
+		if err != nil {
+			return nil, err
 		}
 
-		cred := credentials.NewTLS(&tlsCfg)
+		cred := credentials.NewTLS(tlsCfg)
 		grpcOptions = append(grpcOptions, grpc.WithTransportCredentials(cred))
 	} else {
 		grpcOptions = append(grpcOptions, grpc.WithInsecure())
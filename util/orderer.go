@@ -5,16 +5,19 @@ import (
 
 	"github.com/bogatyr285/hlf-sdk-go/api"
 	"github.com/hyperledger/fabric-protos-go/common"
+	fabricOrderer "github.com/hyperledger/fabric-protos-go/orderer"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 )
 
-// GetConfigBlockFromOrderer returns config block from orderer by channel name
+// GetConfigBlockFromOrderer returns latest config block from orderer by channel name,
+// config block number is taken from LAST_CONFIG metadata of newest block
 func GetConfigBlockFromOrderer(ctx context.Context, id msp.SigningIdentity, orderer api.Orderer, channelName string) (*common.Block, error) {
-	startPos, endPos := api.SeekNewest()()
+	// only newest block is requested
+	newest := &fabricOrderer.SeekPosition{Type: &fabricOrderer.SeekPosition_Newest{Newest: &fabricOrderer.SeekNewest{}}}
 
-	seekEnvelope, err := SeekEnvelope(channelName, startPos, endPos, id)
+	seekEnvelope, err := SeekEnvelope(channelName, newest, newest, id)
 	if err != nil {
 		return nil, errors.Wrap(err, `failed to create seek envelope`)
 	}
@@ -29,7 +32,7 @@ func GetConfigBlockFromOrderer(ctx context.Context, id msp.SigningIdentity, orde
 		return nil, errors.Wrap(err, `failed to fetch block id with config`)
 	}
 
-	startPos, endPos = api.SeekSingle(blockId)()
+	startPos, endPos := api.SeekSingle(blockId)()
 
 	seekEnvelope, err = SeekEnvelope(channelName, startPos, endPos, id)
 	if err != nil {
@@ -41,5 +44,9 @@ func GetConfigBlockFromOrderer(ctx context.Context, id msp.SigningIdentity, orde
 		return nil, errors.Wrap(err, `failed to fetch block with config`)
 	}
 
+	if _, err = GetConfigFromBlock(configBlock); err != nil {
+		return nil, errors.Wrapf(err, "failed to get config from block %d", blockId)
+	}
+
 	return configBlock, nil
 }
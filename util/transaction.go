@@ -23,6 +23,15 @@ func NewTxWithNonce(id msp.SigningIdentity) (string, []byte, error) {
 	}
 }
 
+// NewTxWithCreator generates new transaction id with crypto nonce for serialized creator identity
+func NewTxWithCreator(creator []byte) (string, []byte, error) {
+	nonce, err := crypto.RandomBytes(24)
+	if err != nil {
+		return ``, nil, errors.Wrap(err, `failed to get nonce`)
+	}
+	return generateTxId(nonce, creator), nonce, nil
+}
+
 // generateTxId returns SHA-256 hash of nonce and creator concatenation
 func generateTxId(nonce, creator []byte) string {
 	f := sha256.New()